@@ -75,8 +75,13 @@ func (app *KVStoreApplication) Info(req types.RequestInfo) (resInfo types.Respon
 	}
 }
 
-// tx is either "key=value" or just arbitrary bytes
+// tx is either "lottery:player", "key=value" or just arbitrary bytes
 func (app *KVStoreApplication) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+	// if it starts with "lottery:", buy a lottery ticket
+	if isLotteryTx(req.Tx) {
+		return app.execLotteryTx(req.Tx)
+	}
+
 	var key, value []byte
 	parts := bytes.Split(req.Tx, []byte("="))
 	if len(parts) == 2 {
@@ -101,6 +106,11 @@ func (app *KVStoreApplication) DeliverTx(req types.RequestDeliverTx) types.Respo
 	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Events: events}
 }
 
+// Draw the lottery with the random data of the block
+func (app *KVStoreApplication) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock {
+	return types.ResponseBeginBlock{Events: app.drawLottery(req.Header.Height, req.Header.RandomNumber)}
+}
+
 func (app *KVStoreApplication) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 	return types.ResponseCheckTx{Code: code.CodeTypeOK, GasWanted: 1}
 }
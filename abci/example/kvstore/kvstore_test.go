@@ -77,6 +77,38 @@ func TestPersistentKVStoreKV(t *testing.T) {
 	testKVStore(t, kvstore, tx, key, value)
 }
 
+func TestKVStoreLottery(t *testing.T) {
+	kvstore := NewKVStoreApplication()
+	for _, player := range []string{"alice", "bob", "alice", "carol"} {
+		res := kvstore.DeliverTx(types.RequestDeliverTx{Tx: MakeLotteryTx(player)})
+		require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+	}
+	res := kvstore.DeliverTx(types.RequestDeliverTx{Tx: MakeLotteryTx("")})
+	require.Equal(t, code.CodeTypeEncodingError, res.Code)
+
+	// No draw without random data.
+	resBegin := kvstore.BeginBlock(types.RequestBeginBlock{Header: types.Header{Height: 2}})
+	require.Empty(t, resBegin.Events)
+
+	// The winner can be verified with the random data of the block.
+	randomData := []byte("random data of block 3")
+	winner, err := DrawLotteryWinner(randomData, []LotteryTickets{
+		{Player: "alice", Tickets: 2}, {Player: "bob", Tickets: 1}, {Player: "carol", Tickets: 1},
+	})
+	require.NoError(t, err)
+	resBegin = kvstore.BeginBlock(types.RequestBeginBlock{Header: types.Header{Height: 3, RandomNumber: randomData}})
+	require.Len(t, resBegin.Events, 1)
+	require.Equal(t, "lottery", resBegin.Events[0].Type)
+	require.Equal(t, winner, string(resBegin.Events[0].Attributes[0].Value))
+
+	resQuery := kvstore.Query(types.RequestQuery{Path: "/store", Data: LotteryWinnerKey(3)})
+	require.Equal(t, winner, string(resQuery.Value))
+
+	// The tickets are only drawn once.
+	resBegin = kvstore.BeginBlock(types.RequestBeginBlock{Header: types.Header{Height: 4, RandomNumber: randomData}})
+	require.Empty(t, resBegin.Events)
+}
+
 func TestPersistentKVStoreInfo(t *testing.T) {
 	dir, err := ioutil.TempDir("/tmp", "abci-kvstore-test") // TODO
 	if err != nil {
@@ -0,0 +1,142 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tendermint/tendermint/abci/example/code"
+	"github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types/random"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	LotteryPrefix string = "lottery:"
+
+	// LotteryLabel is the label the lottery winners are derived from the
+	// random data of the blocks with, see types/random.
+	LotteryLabel = "kvstore/lottery"
+)
+
+var lotteryTicketsKey = []byte("lotteryTickets")
+
+// LotteryTickets is the number of tickets a player bought for the next draw of
+// the lottery.
+type LotteryTickets struct {
+	Player  string `json:"player"`
+	Tickets uint64 `json:"tickets"`
+}
+
+func MakeLotteryTx(player string) []byte {
+	return []byte(LotteryPrefix + player)
+}
+
+func isLotteryTx(tx []byte) bool {
+	return strings.HasPrefix(string(tx), LotteryPrefix)
+}
+
+// LotteryWinnerKey is the key of the winner of the draw at the given height.
+func LotteryWinnerKey(height int64) []byte {
+	return []byte(fmt.Sprintf("%swinner:%d", LotteryPrefix, height))
+}
+
+// DrawLotteryWinner returns the winner of a draw among the given tickets with
+// the random data of a block. Each ticket wins with the same probability.
+// Anyone holding the random data of the block can verify the winner.
+func DrawLotteryWinner(randomData []byte, tickets []LotteryTickets) (string, error) {
+	weights := make([]uint64, len(tickets))
+	for i, t := range tickets {
+		weights[i] = t.Tickets
+	}
+	idx, err := random.WeightedPick(randomData, LotteryLabel, weights)
+	if err != nil {
+		return "", err
+	}
+	return tickets[idx].Player, nil
+}
+
+func loadLotteryTickets(db dbm.DB) []LotteryTickets {
+	var tickets []LotteryTickets
+	if bz := db.Get(lotteryTicketsKey); len(bz) != 0 {
+		if err := json.Unmarshal(bz, &tickets); err != nil {
+			panic(err)
+		}
+	}
+	return tickets
+}
+
+func saveLotteryTickets(db dbm.DB, tickets []LotteryTickets) {
+	bz, err := json.Marshal(tickets)
+	if err != nil {
+		panic(err)
+	}
+	db.Set(lotteryTicketsKey, bz)
+}
+
+// format is "lottery:player"
+// buys a ticket for the next draw for the player
+func (app *KVStoreApplication) execLotteryTx(tx []byte) types.ResponseDeliverTx {
+	player := string(tx[len(LotteryPrefix):])
+	if player == "" {
+		return types.ResponseDeliverTx{
+			Code: code.CodeTypeEncodingError,
+			Log:  "Expected 'lottery:player'. Got an empty player"}
+	}
+
+	tickets := loadLotteryTickets(app.state.db)
+	found := false
+	for i := range tickets {
+		if tickets[i].Player == player {
+			tickets[i].Tickets++
+			found = true
+			break
+		}
+	}
+	if !found {
+		tickets = append(tickets, LotteryTickets{Player: player, Tickets: 1})
+	}
+	saveLotteryTickets(app.state.db, tickets)
+
+	events := []types.Event{
+		{
+			Type: "lottery",
+			Attributes: []cmn.KVPair{
+				{Key: []byte("player"), Value: []byte(player)},
+			},
+		},
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Events: events}
+}
+
+// drawLottery draws the winner among the tickets bought in the previous blocks
+// with the random data of the block at the given height. The tickets were
+// bought before the random data was known, so nobody could bias the draw.
+func (app *KVStoreApplication) drawLottery(height int64, randomData []byte) []types.Event {
+	if len(randomData) == 0 {
+		return nil
+	}
+	tickets := loadLotteryTickets(app.state.db)
+	if len(tickets) == 0 {
+		return nil
+	}
+	winner, err := DrawLotteryWinner(randomData, tickets)
+	if err != nil {
+		panic(err)
+	}
+
+	app.state.db.Set(prefixKey(LotteryWinnerKey(height)), []byte(winner))
+	app.state.db.Delete(lotteryTicketsKey)
+
+	return []types.Event{
+		{
+			Type: "lottery",
+			Attributes: []cmn.KVPair{
+				{Key: []byte("winner"), Value: []byte(winner)},
+				{Key: []byte("height"), Value: []byte(strconv.FormatInt(height, 10))},
+			},
+		},
+	}
+}
@@ -65,7 +65,7 @@ func (app *PersistentKVStoreApplication) SetOption(req types.RequestSetOption) t
 	return app.app.SetOption(req)
 }
 
-// tx is either "val:pubkey!power" or "key=value" or just arbitrary bytes
+// tx is either "val:pubkey!power", "lottery:player", "key=value" or just arbitrary bytes
 func (app *PersistentKVStoreApplication) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
 	// if it starts with "val:", update the validator set
 	// format is "val:pubkey!power"
@@ -121,7 +121,10 @@ func (app *PersistentKVStoreApplication) BeginBlock(req types.RequestBeginBlock)
 	app.ValUpdates = make([]types.ValidatorUpdate, 0)
 
 	for _, ev := range req.ByzantineValidators {
-		if ev.Type == tmtypes.ABCIEvidenceTypeDuplicateVote {
+		switch ev.Type {
+		case tmtypes.ABCIEvidenceTypeDuplicateVote,
+			tmtypes.ABCIEvidenceTypeDKGCorruptData,
+			tmtypes.ABCIEvidenceTypeRandomShares:
 			// decrease voting power by 1
 			if ev.TotalVotingPower == 0 {
 				continue
@@ -132,7 +135,7 @@ func (app *PersistentKVStoreApplication) BeginBlock(req types.RequestBeginBlock)
 			})
 		}
 	}
-	return types.ResponseBeginBlock{}
+	return app.app.BeginBlock(req)
 }
 
 // Update the validator set
@@ -1601,6 +1601,9 @@ type ResponseCheckTx struct {
 	GasUsed              int64    `protobuf:"varint,6,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
 	Events               []Event  `protobuf:"bytes,7,rep,name=events,proto3" json:"events,omitempty"`
 	Codespace            string   `protobuf:"bytes,8,opt,name=codespace,proto3" json:"codespace,omitempty"`
+	Priority             int64    `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	Sender               string   `protobuf:"bytes,10,opt,name=sender,proto3" json:"sender,omitempty"`
+	Nonce                uint64   `protobuf:"varint,11,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1695,6 +1698,27 @@ func (m *ResponseCheckTx) GetCodespace() string {
 	return ""
 }
 
+func (m *ResponseCheckTx) GetPriority() int64 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *ResponseCheckTx) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
+}
+
+func (m *ResponseCheckTx) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
 type ResponseDeliverTx struct {
 	Code                 uint32   `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -3050,154 +3074,155 @@ func init() { proto.RegisterFile("types.proto", fileDescriptor_d938547f84707355)
 func init() { golang_proto.RegisterFile("types.proto", fileDescriptor_d938547f84707355) }
 
 var fileDescriptor_d938547f84707355 = []byte{
-	// 2339 bytes of a gzipped FileDescriptorProto
+	// 2367 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x59, 0xcd, 0x73, 0x1b, 0x49,
-	0x15, 0xf7, 0xe8, 0x5b, 0x4f, 0xd6, 0x47, 0x3a, 0x4e, 0xa2, 0x88, 0x60, 0xa7, 0x26, 0x90, 0xb5,
-	0x77, 0xbd, 0xf2, 0xae, 0x97, 0x50, 0x0e, 0x59, 0xb6, 0xca, 0x4a, 0x02, 0x76, 0x6d, 0x36, 0x98,
-	0x49, 0x62, 0x2e, 0x54, 0x4d, 0xb5, 0x34, 0x1d, 0x69, 0x2a, 0xd2, 0xcc, 0xec, 0x4c, 0xcb, 0x91,
-	0xb8, 0x71, 0xdf, 0xc3, 0x1e, 0xf8, 0x13, 0x38, 0xf0, 0x27, 0xec, 0x91, 0x13, 0xb5, 0x47, 0x0e,
-	0x70, 0x0d, 0x60, 0x8a, 0x0b, 0xff, 0x00, 0x70, 0xa3, 0xfa, 0x75, 0xcf, 0xa7, 0x47, 0x61, 0x13,
-	0xb8, 0x71, 0x52, 0x77, 0xbf, 0xdf, 0xeb, 0xe9, 0x7e, 0xfd, 0xbe, 0x05, 0x0d, 0xbe, 0xf4, 0x58,
-	0xd0, 0xf7, 0x7c, 0x97, 0xbb, 0xa4, 0x8c, 0x93, 0xde, 0xfb, 0x63, 0x9b, 0x4f, 0xe6, 0xc3, 0xfe,
-	0xc8, 0x9d, 0xed, 0x8d, 0xdd, 0xb1, 0xbb, 0x87, 0xd4, 0xe1, 0xfc, 0x39, 0xce, 0x70, 0x82, 0x23,
-	0xc9, 0xd5, 0xbb, 0x97, 0x80, 0x73, 0xe6, 0x58, 0xcc, 0x9f, 0xd9, 0x0e, 0x4f, 0x0e, 0x47, 0xfe,
-	0xd2, 0xe3, 0xee, 0xde, 0x8c, 0xf9, 0x2f, 0xa6, 0x4c, 0xfd, 0x28, 0xe6, 0x83, 0xff, 0xc8, 0x3c,
-	0xb5, 0x87, 0xc1, 0xde, 0xc8, 0x9d, 0xcd, 0x5c, 0x67, 0x2f, 0x71, 0xd8, 0xde, 0xd6, 0xd8, 0x75,
-	0xc7, 0x53, 0x16, 0x1f, 0x8e, 0xdb, 0x33, 0x16, 0x70, 0x3a, 0xf3, 0x24, 0x40, 0xff, 0x5d, 0x09,
-	0xaa, 0x06, 0xfb, 0x7c, 0xce, 0x02, 0x4e, 0xb6, 0xa1, 0xc4, 0x46, 0x13, 0xb7, 0x5b, 0xb8, 0xa9,
-	0x6d, 0x37, 0xf6, 0x49, 0x5f, 0x6e, 0xa4, 0xa8, 0x0f, 0x47, 0x13, 0xf7, 0x68, 0xcd, 0x40, 0x04,
-	0x79, 0x0f, 0xca, 0xcf, 0xa7, 0xf3, 0x60, 0xd2, 0x2d, 0x22, 0xf4, 0x72, 0x1a, 0xfa, 0x23, 0x41,
-	0x3a, 0x5a, 0x33, 0x24, 0x46, 0x6c, 0x6b, 0x3b, 0xcf, 0xdd, 0x6e, 0x29, 0x6f, 0xdb, 0x63, 0xe7,
-	0x39, 0x6e, 0x2b, 0x10, 0xe4, 0x00, 0x20, 0x60, 0xdc, 0x74, 0x3d, 0x6e, 0xbb, 0x4e, 0xb7, 0x8c,
-	0xf8, 0x6b, 0x69, 0xfc, 0x13, 0xc6, 0x7f, 0x82, 0xe4, 0xa3, 0x35, 0xa3, 0x1e, 0x84, 0x13, 0xc1,
-	0x69, 0x3b, 0x36, 0x37, 0x47, 0x13, 0x6a, 0x3b, 0xdd, 0x4a, 0x1e, 0xe7, 0xb1, 0x63, 0xf3, 0xfb,
-	0x82, 0x2c, 0x38, 0xed, 0x70, 0x22, 0xae, 0xf2, 0xf9, 0x9c, 0xf9, 0xcb, 0x6e, 0x35, 0xef, 0x2a,
-	0x3f, 0x15, 0x24, 0x71, 0x15, 0xc4, 0x90, 0x7b, 0xd0, 0x18, 0xb2, 0xb1, 0xed, 0x98, 0xc3, 0xa9,
-	0x3b, 0x7a, 0xd1, 0xad, 0x21, 0x4b, 0x37, 0xcd, 0x32, 0x10, 0x80, 0x81, 0xa0, 0x1f, 0xad, 0x19,
-	0x30, 0x8c, 0x66, 0x64, 0x1f, 0x6a, 0xa3, 0x09, 0x1b, 0xbd, 0x30, 0xf9, 0xa2, 0x5b, 0x47, 0xce,
-	0x2b, 0x69, 0xce, 0xfb, 0x82, 0xfa, 0x74, 0x71, 0xb4, 0x66, 0x54, 0x47, 0x72, 0x28, 0xee, 0x65,
-	0xb1, 0xa9, 0x7d, 0xc6, 0x7c, 0xc1, 0x75, 0x39, 0xef, 0x5e, 0x0f, 0x24, 0x1d, 0xf9, 0xea, 0x56,
-	0x38, 0x21, 0x77, 0xa0, 0xce, 0x1c, 0x4b, 0x1d, 0xb4, 0x81, 0x8c, 0x57, 0x33, 0x2f, 0xea, 0x58,
-	0xe1, 0x31, 0x6b, 0x4c, 0x8d, 0x49, 0x1f, 0x2a, 0x42, 0x8d, 0x6c, 0xde, 0x5d, 0x47, 0x9e, 0x8d,
-	0xcc, 0x11, 0x91, 0x76, 0xb4, 0x66, 0x28, 0xd4, 0xa0, 0x0a, 0xe5, 0x33, 0x3a, 0x9d, 0x33, 0xfd,
-	0x1d, 0x68, 0x24, 0x34, 0x85, 0x74, 0xa1, 0x3a, 0x63, 0x41, 0x40, 0xc7, 0xac, 0xab, 0xdd, 0xd4,
-	0xb6, 0xeb, 0x46, 0x38, 0xd5, 0x5b, 0xb0, 0x9e, 0xd4, 0x13, 0x7d, 0x16, 0x31, 0x0a, 0x5d, 0x10,
-	0x8c, 0x67, 0xcc, 0x0f, 0x84, 0x02, 0x28, 0x46, 0x35, 0x25, 0xb7, 0xa0, 0x89, 0xb7, 0x31, 0x43,
-	0xba, 0xd0, 0xd3, 0x92, 0xb1, 0x8e, 0x8b, 0xa7, 0x0a, 0xb4, 0x05, 0x0d, 0x6f, 0xdf, 0x8b, 0x20,
-	0x45, 0x84, 0x80, 0xb7, 0xef, 0x29, 0x80, 0xfe, 0x03, 0xe8, 0x64, 0x55, 0x89, 0x74, 0xa0, 0xf8,
-	0x82, 0x2d, 0xd5, 0xf7, 0xc4, 0x90, 0x6c, 0xa8, 0x6b, 0xe1, 0x37, 0xea, 0x86, 0xba, 0xe3, 0x97,
-	0x85, 0x88, 0x39, 0xd2, 0x26, 0x72, 0x00, 0x25, 0x61, 0x54, 0xc8, 0xdd, 0xd8, 0xef, 0xf5, 0xa5,
-	0xc5, 0xf5, 0x43, 0x8b, 0xeb, 0x3f, 0x0d, 0x2d, 0x6e, 0x50, 0xfb, 0xfa, 0xd5, 0xd6, 0xda, 0x97,
-	0x7f, 0xda, 0xd2, 0x0c, 0xe4, 0x20, 0xd7, 0x85, 0x42, 0x50, 0xdb, 0x31, 0x6d, 0x4b, 0x7d, 0xa7,
-	0x8a, 0xf3, 0x63, 0x8b, 0x1c, 0x42, 0x67, 0xe4, 0x3a, 0x01, 0x73, 0x82, 0x79, 0x60, 0x7a, 0xd4,
-	0xa7, 0xb3, 0x40, 0xd9, 0x5a, 0xf8, 0x88, 0xf7, 0x43, 0xf2, 0x09, 0x52, 0x8d, 0xf6, 0x28, 0xbd,
-	0x40, 0x3e, 0x06, 0x38, 0xa3, 0x53, 0xdb, 0xa2, 0xdc, 0xf5, 0x83, 0x6e, 0xe9, 0x66, 0x31, 0xc1,
-	0x7c, 0x1a, 0x12, 0x9e, 0x79, 0x16, 0xe5, 0x6c, 0x50, 0x12, 0x27, 0x33, 0x12, 0x78, 0x72, 0x1b,
-	0xda, 0xd4, 0xf3, 0xcc, 0x80, 0x53, 0xce, 0xcc, 0xe1, 0x92, 0xb3, 0x00, 0xed, 0x71, 0xdd, 0x68,
-	0x52, 0xcf, 0x7b, 0x22, 0x56, 0x07, 0x62, 0x51, 0xb7, 0xa2, 0xd7, 0x44, 0x53, 0x21, 0x04, 0x4a,
-	0x16, 0xe5, 0x14, 0xa5, 0xb1, 0x6e, 0xe0, 0x58, 0xac, 0x79, 0x94, 0x4f, 0xd4, 0x1d, 0x71, 0x4c,
-	0xae, 0x42, 0x65, 0xc2, 0xec, 0xf1, 0x84, 0xe3, 0xb5, 0x8a, 0x86, 0x9a, 0x09, 0xc1, 0x7b, 0xbe,
-	0x7b, 0xc6, 0xd0, 0x5b, 0xd4, 0x0c, 0x39, 0xd1, 0xff, 0xa6, 0xc1, 0xa5, 0x0b, 0xe6, 0x25, 0xf6,
-	0x9d, 0xd0, 0x60, 0x12, 0x7e, 0x4b, 0x8c, 0xc9, 0x7b, 0x62, 0x5f, 0x6a, 0x31, 0x5f, 0x79, 0xb1,
-	0xa6, 0xba, 0xf1, 0x11, 0x2e, 0xaa, 0x8b, 0x2a, 0x08, 0x79, 0x08, 0x9d, 0x29, 0x0d, 0xb8, 0x29,
-	0x75, 0xd9, 0x44, 0x2f, 0x55, 0x4c, 0x59, 0xe6, 0x23, 0x1a, 0xea, 0xbc, 0x50, 0x4e, 0xc5, 0xde,
-	0x9a, 0xa6, 0x56, 0xc9, 0x11, 0x6c, 0x0c, 0x97, 0xbf, 0xa0, 0x0e, 0xb7, 0x1d, 0x66, 0x5e, 0x90,
-	0x79, 0x5b, 0x6d, 0xf5, 0xf0, 0xcc, 0xb6, 0x98, 0x33, 0x0a, 0x85, 0x7d, 0x39, 0x62, 0x89, 0x1e,
-	0x23, 0xd0, 0x8f, 0xa0, 0x95, 0xf6, 0x05, 0xa4, 0x05, 0x05, 0xbe, 0x50, 0x37, 0x2c, 0xf0, 0x05,
-	0xb9, 0x0d, 0x25, 0xb1, 0x1d, 0xde, 0xae, 0x15, 0x39, 0x53, 0x85, 0x7e, 0xba, 0xf4, 0x98, 0x81,
-	0x74, 0x5d, 0x8f, 0x34, 0x35, 0xf2, 0x0f, 0xd9, 0xbd, 0xf4, 0x1d, 0x68, 0x67, 0x5c, 0x41, 0xe2,
-	0x59, 0xb4, 0xe4, 0xb3, 0xe8, 0x6d, 0x68, 0xa6, 0x3c, 0x80, 0xfe, 0x45, 0x19, 0x6a, 0x06, 0x0b,
-	0x3c, 0xa1, 0x74, 0xe4, 0x00, 0xea, 0x6c, 0x31, 0x62, 0xd2, 0x6d, 0x6b, 0x19, 0xa7, 0x28, 0x31,
-	0x0f, 0x43, 0xba, 0xf0, 0x52, 0x11, 0x98, 0xec, 0xa4, 0x42, 0xce, 0xe5, 0x2c, 0x53, 0x32, 0xe6,
-	0xec, 0xa6, 0x63, 0xce, 0x46, 0x06, 0x9b, 0x09, 0x3a, 0x3b, 0xa9, 0xa0, 0x93, 0xdd, 0x38, 0x15,
-	0x75, 0xee, 0xe6, 0x44, 0x9d, 0xec, 0xf1, 0x57, 0x84, 0x9d, 0xbb, 0x39, 0x61, 0xa7, 0x7b, 0xe1,
-	0x5b, 0xb9, 0x71, 0x67, 0x37, 0x1d, 0x77, 0xb2, 0xd7, 0xc9, 0x04, 0x9e, 0x8f, 0xf3, 0x02, 0xcf,
-	0xf5, 0x0c, 0xcf, 0xca, 0xc8, 0xf3, 0xd1, 0x85, 0xc8, 0x73, 0x35, 0xc3, 0x9a, 0x13, 0x7a, 0xee,
-	0xa6, 0x42, 0x0f, 0xe4, 0xde, 0x6d, 0x45, 0xec, 0xf9, 0xfe, 0xc5, 0xd8, 0x73, 0x2d, 0xfb, 0xb4,
-	0x79, 0xc1, 0x67, 0x2f, 0x13, 0x7c, 0xae, 0x64, 0x4f, 0xb9, 0x32, 0xfa, 0xec, 0x08, 0xff, 0x90,
-	0xd1, 0x34, 0xe1, 0x4b, 0x98, 0xef, 0xbb, 0xbe, 0x72, 0xec, 0x72, 0xa2, 0x6f, 0x0b, 0x8f, 0x15,
-	0xeb, 0xd7, 0x6b, 0x22, 0x15, 0x2a, 0x7d, 0x42, 0xbb, 0xf4, 0xaf, 0xb4, 0x98, 0x17, 0x2d, 0x3f,
-	0xe9, 0xed, 0xea, 0xca, 0xdb, 0x25, 0x02, 0x58, 0x21, 0x1d, 0xc0, 0xb6, 0xa0, 0x21, 0x7c, 0x6a,
-	0x26, 0x36, 0x51, 0x2f, 0x8c, 0x4d, 0xe4, 0x5d, 0xb8, 0x84, 0xfe, 0x48, 0x86, 0x39, 0x65, 0x88,
-	0x25, 0x34, 0xc4, 0xb6, 0x20, 0x48, 0x89, 0x49, 0x47, 0xf9, 0x3e, 0x5c, 0x4e, 0x60, 0xc5, 0xbe,
-	0xe8, 0x0b, 0xa5, 0x93, 0xee, 0x44, 0xe8, 0x43, 0xcf, 0x3b, 0xa2, 0xc1, 0x44, 0xff, 0x2c, 0x16,
-	0x50, 0x1c, 0xf7, 0x08, 0x94, 0x46, 0xae, 0x25, 0xef, 0xdd, 0x34, 0x70, 0x2c, 0x62, 0xe1, 0xd4,
-	0x1d, 0xe3, 0xe1, 0xea, 0x86, 0x18, 0x0a, 0x54, 0x64, 0x4a, 0x75, 0x69, 0x33, 0xfa, 0xaf, 0xb4,
-	0x78, 0xbf, 0x38, 0x14, 0xe6, 0x45, 0x2d, 0xed, 0xbf, 0x89, 0x5a, 0x85, 0x37, 0x8b, 0x5a, 0xfa,
-	0xb9, 0x16, 0x3f, 0x59, 0x14, 0x8f, 0xde, 0xee, 0x8a, 0x42, 0x7b, 0x6c, 0xc7, 0x62, 0x0b, 0x14,
-	0x69, 0xd1, 0x90, 0x93, 0x30, 0x55, 0xa8, 0xa0, 0x98, 0xd3, 0xa9, 0x42, 0x15, 0xd7, 0xe4, 0x84,
-	0xdc, 0xc2, 0x38, 0xe6, 0x3e, 0x57, 0xa6, 0xda, 0xec, 0xab, 0x84, 0xfe, 0x44, 0x2c, 0x1a, 0x92,
-	0x96, 0xf0, 0xb6, 0xf5, 0x54, 0x10, 0xbc, 0x01, 0x75, 0x71, 0xd0, 0xc0, 0xa3, 0x23, 0x86, 0x96,
-	0x57, 0x37, 0xe2, 0x05, 0xfd, 0x29, 0x90, 0x8b, 0x16, 0x4f, 0x3e, 0x81, 0x0a, 0x3b, 0x63, 0x0e,
-	0x17, 0x12, 0x17, 0x42, 0x5b, 0x8f, 0xc2, 0x0e, 0x73, 0xf8, 0xa0, 0x2b, 0x44, 0xf5, 0xf7, 0x57,
-	0x5b, 0x1d, 0x89, 0xd9, 0x75, 0x67, 0x36, 0x67, 0x33, 0x8f, 0x2f, 0x0d, 0xc5, 0xa5, 0xff, 0x43,
-	0x13, 0xd1, 0x20, 0xe5, 0x0d, 0x72, 0x85, 0x17, 0xaa, 0x7c, 0x21, 0x11, 0xe0, 0xbf, 0x99, 0x40,
-	0xbf, 0x0d, 0x30, 0xa6, 0x81, 0xf9, 0x92, 0x3a, 0x9c, 0x59, 0x4a, 0xaa, 0xf5, 0x31, 0x0d, 0x7e,
-	0x86, 0x0b, 0x22, 0x1b, 0x12, 0xe4, 0x79, 0xc0, 0x2c, 0x14, 0x6f, 0xd1, 0xa8, 0x8e, 0x69, 0xf0,
-	0x2c, 0x60, 0x56, 0xe2, 0x6e, 0xd5, 0xb7, 0xb9, 0x5b, 0x5a, 0x9e, 0xb5, 0xac, 0x3c, 0xff, 0x95,
-	0xd0, 0xe5, 0x38, 0x58, 0xfe, 0x7f, 0xdc, 0xfd, 0x97, 0x98, 0xd1, 0xa6, 0x5d, 0x32, 0x39, 0x86,
-	0x4b, 0x91, 0x4d, 0x99, 0x73, 0xb4, 0xb5, 0x50, 0xab, 0x5e, 0x6f, 0x8a, 0x9d, 0xb3, 0xf4, 0x72,
-	0x40, 0x1e, 0xc3, 0xb5, 0x8c, 0x47, 0x88, 0x36, 0x2c, 0xbc, 0xd6, 0x31, 0x5c, 0x49, 0x3b, 0x86,
-	0x70, 0xbf, 0x58, 0x1a, 0xc5, 0xb7, 0x92, 0x06, 0x81, 0x52, 0xc0, 0x98, 0x85, 0x6f, 0xb3, 0x6e,
-	0xe0, 0x58, 0xff, 0x8e, 0x48, 0xba, 0x92, 0x01, 0x26, 0xef, 0x9d, 0xf5, 0x5f, 0x6b, 0xd0, 0xce,
-	0x1c, 0x92, 0x6c, 0x43, 0x59, 0xc6, 0x38, 0x2d, 0x55, 0xda, 0xa2, 0x14, 0xd5, 0x3d, 0x24, 0x80,
-	0x7c, 0x08, 0x35, 0xa6, 0xf2, 0x3f, 0x75, 0xf1, 0x2b, 0x99, 0xb4, 0x50, 0xe1, 0x23, 0x18, 0xf9,
-	0x1e, 0xd4, 0x23, 0x71, 0x66, 0x72, 0xff, 0x48, 0xfa, 0x8a, 0x29, 0x06, 0xea, 0xf7, 0xa1, 0x91,
-	0xf8, 0x3c, 0xf9, 0x16, 0xd4, 0x67, 0x74, 0xa1, 0x12, 0x78, 0x99, 0xd2, 0xd5, 0x66, 0x74, 0x81,
-	0xb9, 0x3b, 0xb9, 0x06, 0x55, 0x41, 0x1c, 0x53, 0xf9, 0x18, 0x45, 0xa3, 0x32, 0xa3, 0x8b, 0x1f,
-	0xd3, 0x40, 0xdf, 0x81, 0x56, 0xfa, 0x58, 0x21, 0x34, 0x0c, 0x92, 0x12, 0x7a, 0x38, 0x66, 0xfa,
-	0x1d, 0x68, 0x67, 0x4e, 0x43, 0x74, 0x68, 0x7a, 0xf3, 0xa1, 0xf9, 0x82, 0x2d, 0x4d, 0x3c, 0x2e,
-	0xaa, 0x4e, 0xdd, 0x68, 0x78, 0xf3, 0xe1, 0xa7, 0x6c, 0x29, 0x72, 0xd4, 0x40, 0x7f, 0x02, 0xad,
-	0x74, 0x6a, 0x2d, 0xdc, 0xa8, 0xef, 0xce, 0x1d, 0x0b, 0xf7, 0x2f, 0x1b, 0x72, 0x22, 0xaa, 0xf3,
-	0x33, 0x57, 0x6a, 0x4b, 0x32, 0x97, 0x3e, 0x75, 0x39, 0x4b, 0x24, 0xe4, 0x12, 0xa3, 0xdb, 0x50,
-	0x46, 0x3d, 0x10, 0xef, 0x87, 0x49, 0xb2, 0x0a, 0xcb, 0x62, 0x4c, 0x1e, 0x01, 0x50, 0xce, 0x7d,
-	0x7b, 0x38, 0x8f, 0xb7, 0x6b, 0xf5, 0x65, 0xcb, 0xa4, 0xff, 0xe9, 0xe9, 0x09, 0xb5, 0xfd, 0xc1,
-	0x0d, 0xa5, 0x3f, 0x1b, 0x31, 0x32, 0xa1, 0x43, 0x09, 0x7e, 0xfd, 0x8f, 0x65, 0xa8, 0xc8, 0x92,
-	0x82, 0xf4, 0xd3, 0x05, 0xab, 0xd8, 0x55, 0x1d, 0x52, 0xae, 0xaa, 0x33, 0x46, 0x59, 0xc0, 0xed,
-	0x6c, 0xd5, 0x37, 0x68, 0x9c, 0xbf, 0xda, 0xaa, 0x62, 0x04, 0x3d, 0x7e, 0x10, 0x97, 0x80, 0xab,
-	0x2a, 0xa4, 0xb0, 0xde, 0x2c, 0xbd, 0x71, 0xbd, 0x79, 0x0d, 0xaa, 0xce, 0x7c, 0x66, 0xf2, 0x45,
-	0xa0, 0x3c, 0x50, 0xc5, 0x99, 0xcf, 0x9e, 0x2e, 0x50, 0x4b, 0xb8, 0xcb, 0xe9, 0x14, 0x49, 0xd2,
-	0xff, 0xd4, 0x70, 0x41, 0x10, 0x0f, 0xa0, 0x99, 0x48, 0x34, 0x6c, 0x4b, 0x25, 0xac, 0xad, 0xa4,
-	0xb2, 0x1f, 0x3f, 0x50, 0xb7, 0x6c, 0x44, 0x89, 0xc7, 0xb1, 0x45, 0xb6, 0xd3, 0xe5, 0x15, 0xe6,
-	0x27, 0x35, 0x34, 0xa9, 0x44, 0x05, 0x25, 0xb2, 0x13, 0x71, 0x00, 0x61, 0x64, 0x12, 0x52, 0x47,
-	0x48, 0x4d, 0x2c, 0x20, 0xf1, 0x1d, 0x68, 0xc7, 0x21, 0x5e, 0x42, 0x40, 0xee, 0x12, 0x2f, 0x23,
-	0xf0, 0x03, 0xd8, 0x70, 0xd8, 0x82, 0x9b, 0x59, 0x74, 0x03, 0xd1, 0x44, 0xd0, 0x4e, 0xd3, 0x1c,
-	0xdf, 0x85, 0x56, 0xec, 0x9e, 0x10, 0xbb, 0x2e, 0x8b, 0xdc, 0x68, 0x15, 0x61, 0xd7, 0xa1, 0x16,
-	0x25, 0x58, 0x4d, 0x04, 0x54, 0xa9, 0xcc, 0xab, 0xa2, 0x94, 0xcd, 0x67, 0xc1, 0x7c, 0xca, 0xd5,
-	0x26, 0x2d, 0xc4, 0x60, 0xca, 0x66, 0xc8, 0x75, 0xc4, 0xde, 0x82, 0x66, 0x68, 0xdd, 0x12, 0xd7,
-	0x46, 0xdc, 0x7a, 0xb8, 0x88, 0xa0, 0x1d, 0xe8, 0x78, 0xbe, 0xeb, 0xb9, 0x01, 0xf3, 0x4d, 0x6a,
-	0x59, 0x3e, 0x0b, 0x82, 0x6e, 0x47, 0xee, 0x17, 0xae, 0x1f, 0xca, 0x65, 0xb1, 0x9f, 0x4f, 0x1d,
-	0xcb, 0x9d, 0x99, 0xce, 0x7c, 0x36, 0x64, 0x7e, 0xf7, 0x92, 0xdc, 0x4f, 0x2e, 0x3e, 0xc6, 0x35,
-	0xb2, 0x0b, 0x24, 0x05, 0x92, 0x5f, 0x26, 0x32, 0x4d, 0x4c, 0x22, 0x31, 0x4d, 0xfc, 0x10, 0xaa,
-	0x61, 0x32, 0xba, 0x01, 0xe5, 0x41, 0xe4, 0xdc, 0x4a, 0x86, 0x9c, 0x88, 0x70, 0x77, 0xe8, 0x79,
-	0xaa, 0xf5, 0x22, 0x86, 0xfa, 0xcf, 0xa1, 0xaa, 0x74, 0x20, 0xb7, 0x20, 0xff, 0x21, 0xac, 0x7b,
-	0xd4, 0x17, 0x92, 0x49, 0x96, 0xe5, 0x61, 0xb9, 0x73, 0x42, 0x7d, 0xfe, 0x84, 0xf1, 0x54, 0x75,
-	0xde, 0x40, 0xbc, 0x5c, 0xd2, 0xef, 0x42, 0x33, 0x85, 0x11, 0xc7, 0x42, 0xd5, 0x0c, 0xfd, 0x04,
-	0x4e, 0xa2, 0x2f, 0x17, 0xe2, 0x2f, 0xeb, 0xf7, 0xa0, 0x1e, 0x3d, 0xb7, 0xc8, 0xca, 0x43, 0x69,
-	0x6a, 0xea, 0x05, 0x95, 0x14, 0x37, 0xa0, 0xec, 0xb9, 0x2f, 0x99, 0xaf, 0xcc, 0x4c, 0x4e, 0xf4,
-	0x67, 0x09, 0xbf, 0x26, 0x83, 0x0f, 0xd9, 0x85, 0xaa, 0xf2, 0x6b, 0xca, 0xd0, 0xc3, 0xde, 0xc2,
-	0x09, 0x3a, 0xb6, 0xb0, 0xb7, 0x20, 0xdd, 0x5c, 0xbc, 0x6d, 0x21, 0xb9, 0xed, 0x14, 0x6a, 0xa1,
-	0xef, 0x4a, 0x3b, 0x78, 0xb9, 0x63, 0x27, 0xeb, 0xe0, 0xd5, 0xa6, 0x31, 0x50, 0x28, 0x5c, 0x60,
-	0x8f, 0x1d, 0x66, 0x99, 0xb1, 0x55, 0xe2, 0x37, 0x6a, 0x46, 0x5b, 0x12, 0x1e, 0x85, 0x26, 0xa8,
-	0x7f, 0x00, 0x15, 0x79, 0xb6, 0x5c, 0x8f, 0x98, 0x17, 0xe5, 0xfe, 0xa0, 0x41, 0x2d, 0x74, 0xfd,
-	0xb9, 0x4c, 0xa9, 0x43, 0x17, 0xbe, 0xe9, 0xa1, 0xff, 0xf7, 0xbe, 0x6c, 0x17, 0x88, 0x74, 0x59,
-	0x67, 0x2e, 0xb7, 0x9d, 0xb1, 0x29, 0x65, 0x2d, 0xdd, 0x5a, 0x07, 0x29, 0xa7, 0x48, 0x38, 0x41,
-	0xb1, 0x0f, 0xa1, 0xf5, 0x99, 0x6b, 0xcd, 0xa7, 0x71, 0x8e, 0x93, 0xae, 0x33, 0xb4, 0x37, 0xec,
-	0x8e, 0x85, 0x69, 0x44, 0x21, 0x4e, 0x23, 0xde, 0xbd, 0x05, 0x8d, 0x44, 0x1b, 0x86, 0x54, 0xa1,
-	0xf8, 0x98, 0xbd, 0xec, 0xac, 0x91, 0x06, 0x54, 0x0d, 0x86, 0x45, 0x75, 0x47, 0xdb, 0xff, 0xa2,
-	0x0c, 0xed, 0xc3, 0xc1, 0xfd, 0xe3, 0x43, 0xcf, 0x9b, 0xda, 0x23, 0x8a, 0x55, 0xd8, 0x1e, 0x94,
-	0xb0, 0x10, 0xcd, 0x69, 0xb8, 0xf7, 0xf2, 0x3a, 0x22, 0x64, 0x1f, 0xca, 0x58, 0x8f, 0x92, 0xbc,
-	0xbe, 0x7b, 0x2f, 0xb7, 0x31, 0x22, 0x3e, 0x22, 0x2b, 0xd6, 0x8b, 0xed, 0xf7, 0x5e, 0x5e, 0x77,
-	0x84, 0x7c, 0x02, 0xf5, 0xb8, 0x50, 0x5c, 0xd5, 0x84, 0xef, 0xad, 0xec, 0x93, 0x08, 0xfe, 0x38,
-	0x99, 0x5e, 0xd5, 0xb2, 0xee, 0xad, 0x6c, 0x28, 0x90, 0x03, 0xa8, 0x86, 0x65, 0x48, 0x7e, 0x9b,
-	0xbc, 0xb7, 0xa2, 0x87, 0x21, 0xc4, 0x23, 0x6b, 0xbf, 0xbc, 0x5e, 0x7e, 0x2f, 0xb7, 0xd1, 0x42,
-	0xee, 0x40, 0x45, 0xe5, 0x7e, 0xb9, 0x0d, 0xef, 0x5e, 0x7e, 0x27, 0x42, 0x5c, 0x32, 0xae, 0x7e,
-	0x57, 0xfd, 0xdf, 0xd0, 0x5b, 0xd9, 0x11, 0x22, 0x87, 0x00, 0x89, 0x12, 0x6e, 0xe5, 0x1f, 0x09,
-	0xbd, 0xd5, 0x9d, 0x1e, 0x72, 0x0f, 0x6a, 0x71, 0xf7, 0x2e, 0xbf, 0xc1, 0xdf, 0x5b, 0xd5, 0x7c,
-	0x19, 0xdc, 0xf8, 0xe7, 0x5f, 0x36, 0xb5, 0xdf, 0x9c, 0x6f, 0x6a, 0x5f, 0x9d, 0x6f, 0x6a, 0x5f,
-	0x9f, 0x6f, 0x6a, 0xbf, 0x3f, 0xdf, 0xd4, 0xfe, 0x7c, 0xbe, 0xa9, 0xfd, 0xf6, 0xaf, 0x9b, 0xda,
-	0xb0, 0x82, 0x76, 0xf8, 0xd1, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x8f, 0x5f, 0x25, 0xff,
-	0x1a, 0x00, 0x00,
+	0x15, 0xf7, 0xe8, 0x5b, 0x4f, 0x9f, 0xee, 0x38, 0x89, 0x22, 0x82, 0x9d, 0x9a, 0x40, 0xd6, 0xde,
+	0xf5, 0xca, 0xbb, 0x5e, 0x42, 0x39, 0x64, 0xd9, 0x2a, 0x2b, 0x09, 0xd8, 0xb5, 0xd9, 0x60, 0x26,
+	0x89, 0xb9, 0x50, 0x35, 0x35, 0xd2, 0x74, 0xa4, 0x29, 0x4b, 0x33, 0xb3, 0x33, 0x2d, 0x47, 0xe2,
+	0xc6, 0x7d, 0x0f, 0x7b, 0xe0, 0x4f, 0xe0, 0xc0, 0x9f, 0xb0, 0x47, 0xb8, 0x50, 0x7b, 0xe4, 0x00,
+	0xd7, 0x00, 0xa6, 0xb8, 0xf0, 0x17, 0xc0, 0x8d, 0xea, 0xd7, 0xdd, 0xa3, 0x99, 0xf1, 0x28, 0x6c,
+	0x02, 0x37, 0x4e, 0xea, 0x8f, 0xdf, 0x7b, 0xd3, 0xfd, 0xfa, 0x7d, 0x0b, 0x6a, 0x6c, 0xe1, 0xd3,
+	0xb0, 0xe7, 0x07, 0x1e, 0xf3, 0x48, 0x11, 0x27, 0xdd, 0xf7, 0x47, 0x0e, 0x1b, 0xcf, 0x06, 0xbd,
+	0xa1, 0x37, 0xdd, 0x1b, 0x79, 0x23, 0x6f, 0x0f, 0x77, 0x07, 0xb3, 0x17, 0x38, 0xc3, 0x09, 0x8e,
+	0x04, 0x55, 0xf7, 0x7e, 0x0c, 0xce, 0xa8, 0x6b, 0xd3, 0x60, 0xea, 0xb8, 0x2c, 0x3e, 0x1c, 0x06,
+	0x0b, 0x9f, 0x79, 0x7b, 0x53, 0x1a, 0x9c, 0x4d, 0xa8, 0xfc, 0x91, 0xc4, 0x07, 0xff, 0x91, 0x78,
+	0xe2, 0x0c, 0xc2, 0xbd, 0xa1, 0x37, 0x9d, 0x7a, 0xee, 0x5e, 0xec, 0xb0, 0xdd, 0xad, 0x91, 0xe7,
+	0x8d, 0x26, 0x74, 0x79, 0x38, 0xe6, 0x4c, 0x69, 0xc8, 0xac, 0xa9, 0x2f, 0x00, 0xfa, 0xef, 0x0b,
+	0x50, 0x36, 0xe8, 0xe7, 0x33, 0x1a, 0x32, 0xb2, 0x0d, 0x05, 0x3a, 0x1c, 0x7b, 0x9d, 0xdc, 0x2d,
+	0x6d, 0xbb, 0xb6, 0x4f, 0x7a, 0x82, 0x91, 0xdc, 0x7d, 0x34, 0x1c, 0x7b, 0x47, 0x6b, 0x06, 0x22,
+	0xc8, 0x7b, 0x50, 0x7c, 0x31, 0x99, 0x85, 0xe3, 0x4e, 0x1e, 0xa1, 0x57, 0x92, 0xd0, 0x1f, 0xf1,
+	0xad, 0xa3, 0x35, 0x43, 0x60, 0x38, 0x5b, 0xc7, 0x7d, 0xe1, 0x75, 0x0a, 0x59, 0x6c, 0x8f, 0xdd,
+	0x17, 0xc8, 0x96, 0x23, 0xc8, 0x01, 0x40, 0x48, 0x99, 0xe9, 0xf9, 0xcc, 0xf1, 0xdc, 0x4e, 0x11,
+	0xf1, 0xd7, 0x93, 0xf8, 0xa7, 0x94, 0xfd, 0x04, 0xb7, 0x8f, 0xd6, 0x8c, 0x6a, 0xa8, 0x26, 0x9c,
+	0xd2, 0x71, 0x1d, 0x66, 0x0e, 0xc7, 0x96, 0xe3, 0x76, 0x4a, 0x59, 0x94, 0xc7, 0xae, 0xc3, 0x1e,
+	0xf0, 0x6d, 0x4e, 0xe9, 0xa8, 0x09, 0xbf, 0xca, 0xe7, 0x33, 0x1a, 0x2c, 0x3a, 0xe5, 0xac, 0xab,
+	0xfc, 0x94, 0x6f, 0xf1, 0xab, 0x20, 0x86, 0xdc, 0x87, 0xda, 0x80, 0x8e, 0x1c, 0xd7, 0x1c, 0x4c,
+	0xbc, 0xe1, 0x59, 0xa7, 0x82, 0x24, 0x9d, 0x24, 0x49, 0x9f, 0x03, 0xfa, 0x7c, 0xff, 0x68, 0xcd,
+	0x80, 0x41, 0x34, 0x23, 0xfb, 0x50, 0x19, 0x8e, 0xe9, 0xf0, 0xcc, 0x64, 0xf3, 0x4e, 0x15, 0x29,
+	0xaf, 0x26, 0x29, 0x1f, 0xf0, 0xdd, 0x67, 0xf3, 0xa3, 0x35, 0xa3, 0x3c, 0x14, 0x43, 0x7e, 0x2f,
+	0x9b, 0x4e, 0x9c, 0x73, 0x1a, 0x70, 0xaa, 0x2b, 0x59, 0xf7, 0x7a, 0x28, 0xf6, 0x91, 0xae, 0x6a,
+	0xab, 0x09, 0xb9, 0x0b, 0x55, 0xea, 0xda, 0xf2, 0xa0, 0x35, 0x24, 0xbc, 0x96, 0x7a, 0x51, 0xd7,
+	0x56, 0xc7, 0xac, 0x50, 0x39, 0x26, 0x3d, 0x28, 0x71, 0x35, 0x72, 0x58, 0xa7, 0x8e, 0x34, 0x1b,
+	0xa9, 0x23, 0xe2, 0xde, 0xd1, 0x9a, 0x21, 0x51, 0xfd, 0x32, 0x14, 0xcf, 0xad, 0xc9, 0x8c, 0xea,
+	0xef, 0x40, 0x2d, 0xa6, 0x29, 0xa4, 0x03, 0xe5, 0x29, 0x0d, 0x43, 0x6b, 0x44, 0x3b, 0xda, 0x2d,
+	0x6d, 0xbb, 0x6a, 0xa8, 0xa9, 0xde, 0x84, 0x7a, 0x5c, 0x4f, 0xf4, 0x29, 0xd4, 0x62, 0xba, 0xc0,
+	0x09, 0xcf, 0x69, 0x10, 0x72, 0x05, 0x90, 0x84, 0x72, 0x4a, 0x6e, 0x43, 0x03, 0x6f, 0x63, 0xaa,
+	0x7d, 0xae, 0xa7, 0x05, 0xa3, 0x8e, 0x8b, 0xa7, 0x12, 0xb4, 0x05, 0x35, 0x7f, 0xdf, 0x8f, 0x20,
+	0x79, 0x84, 0x80, 0xbf, 0xef, 0x4b, 0x80, 0xfe, 0x03, 0x68, 0xa7, 0x55, 0x89, 0xb4, 0x21, 0x7f,
+	0x46, 0x17, 0xf2, 0x7b, 0x7c, 0x48, 0x36, 0xe4, 0xb5, 0xf0, 0x1b, 0x55, 0x43, 0xde, 0xf1, 0xcb,
+	0x1c, 0xb4, 0xd3, 0xda, 0x44, 0x0e, 0xa0, 0xc0, 0x8d, 0x0a, 0xa9, 0x6b, 0xfb, 0xdd, 0x9e, 0xb0,
+	0xb8, 0x9e, 0xb2, 0xb8, 0xde, 0x33, 0x65, 0x71, 0xfd, 0xca, 0xd7, 0xaf, 0xb6, 0xd6, 0xbe, 0xfc,
+	0xf3, 0x96, 0x66, 0x20, 0x05, 0xb9, 0xc1, 0x15, 0xc2, 0x72, 0x5c, 0xd3, 0xb1, 0xe5, 0x77, 0xca,
+	0x38, 0x3f, 0xb6, 0xc9, 0x21, 0xb4, 0x87, 0x9e, 0x1b, 0x52, 0x37, 0x9c, 0x85, 0xa6, 0x6f, 0x05,
+	0xd6, 0x34, 0xec, 0xe4, 0x13, 0x8f, 0xf8, 0x40, 0x6d, 0x9f, 0xe0, 0xae, 0xd1, 0x1a, 0x26, 0x17,
+	0xc8, 0xc7, 0x00, 0xe7, 0xd6, 0xc4, 0xb1, 0x2d, 0xe6, 0x05, 0x61, 0xa7, 0x70, 0x2b, 0x1f, 0x23,
+	0x3e, 0x55, 0x1b, 0xcf, 0x7d, 0xdb, 0x62, 0xb4, 0x5f, 0xe0, 0x27, 0x33, 0x62, 0x78, 0x72, 0x07,
+	0x5a, 0x96, 0xef, 0x9b, 0x21, 0xb3, 0x18, 0x35, 0x07, 0x0b, 0x46, 0x43, 0xb4, 0xc7, 0xba, 0xd1,
+	0xb0, 0x7c, 0xff, 0x29, 0x5f, 0xed, 0xf3, 0x45, 0xdd, 0x86, 0x7a, 0xdc, 0x54, 0x08, 0x81, 0x82,
+	0x6d, 0x31, 0x0b, 0xa5, 0x51, 0x37, 0x70, 0xcc, 0xd7, 0x7c, 0x8b, 0x8d, 0xe5, 0x1d, 0x71, 0x4c,
+	0xae, 0x41, 0x69, 0x4c, 0x9d, 0xd1, 0x98, 0xe1, 0xb5, 0xf2, 0x86, 0x9c, 0x71, 0xc1, 0xfb, 0x81,
+	0x77, 0x4e, 0xd1, 0x5b, 0x54, 0x0c, 0x31, 0xd1, 0xff, 0xae, 0xc1, 0xfa, 0x25, 0xf3, 0xe2, 0x7c,
+	0xc7, 0x56, 0x38, 0x56, 0xdf, 0xe2, 0x63, 0xf2, 0x1e, 0xe7, 0x6b, 0xd9, 0x34, 0x90, 0x5e, 0xac,
+	0x21, 0x6f, 0x7c, 0x84, 0x8b, 0xf2, 0xa2, 0x12, 0x42, 0x1e, 0x41, 0x7b, 0x62, 0x85, 0xcc, 0x14,
+	0xba, 0x6c, 0xa2, 0x97, 0xca, 0x27, 0x2c, 0xf3, 0xb1, 0xa5, 0x74, 0x9e, 0x2b, 0xa7, 0x24, 0x6f,
+	0x4e, 0x12, 0xab, 0xe4, 0x08, 0x36, 0x06, 0x8b, 0x5f, 0x58, 0x2e, 0x73, 0x5c, 0x6a, 0x5e, 0x92,
+	0x79, 0x4b, 0xb2, 0x7a, 0x74, 0xee, 0xd8, 0xd4, 0x1d, 0x2a, 0x61, 0x5f, 0x89, 0x48, 0xa2, 0xc7,
+	0x08, 0xf5, 0x23, 0x68, 0x26, 0x7d, 0x01, 0x69, 0x42, 0x8e, 0xcd, 0xe5, 0x0d, 0x73, 0x6c, 0x4e,
+	0xee, 0x40, 0x81, 0xb3, 0xc3, 0xdb, 0x35, 0x23, 0x67, 0x2a, 0xd1, 0xcf, 0x16, 0x3e, 0x35, 0x70,
+	0x5f, 0xd7, 0xa1, 0x9d, 0xf6, 0x0f, 0x69, 0x5e, 0xfa, 0x0e, 0xb4, 0x52, 0xae, 0x20, 0xf6, 0x2c,
+	0x5a, 0xfc, 0x59, 0xf4, 0x16, 0x34, 0x12, 0x1e, 0x40, 0xff, 0xa2, 0x08, 0x15, 0x83, 0x86, 0x3e,
+	0x57, 0x3a, 0x72, 0x00, 0x55, 0x3a, 0x1f, 0x52, 0xe1, 0xb6, 0xb5, 0x94, 0x53, 0x14, 0x98, 0x47,
+	0x6a, 0x9f, 0x7b, 0xa9, 0x08, 0x4c, 0x76, 0x12, 0x21, 0xe7, 0x4a, 0x9a, 0x28, 0x1e, 0x73, 0x76,
+	0x93, 0x31, 0x67, 0x23, 0x85, 0x4d, 0x05, 0x9d, 0x9d, 0x44, 0xd0, 0x49, 0x33, 0x4e, 0x44, 0x9d,
+	0x7b, 0x19, 0x51, 0x27, 0x7d, 0xfc, 0x15, 0x61, 0xe7, 0x5e, 0x46, 0xd8, 0xe9, 0x5c, 0xfa, 0x56,
+	0x66, 0xdc, 0xd9, 0x4d, 0xc6, 0x9d, 0xf4, 0x75, 0x52, 0x81, 0xe7, 0xe3, 0xac, 0xc0, 0x73, 0x23,
+	0x45, 0xb3, 0x32, 0xf2, 0x7c, 0x74, 0x29, 0xf2, 0x5c, 0x4b, 0x91, 0x66, 0x84, 0x9e, 0x7b, 0x89,
+	0xd0, 0x03, 0x99, 0x77, 0x5b, 0x11, 0x7b, 0xbe, 0x7f, 0x39, 0xf6, 0x5c, 0x4f, 0x3f, 0x6d, 0x56,
+	0xf0, 0xd9, 0x4b, 0x05, 0x9f, 0xab, 0xe9, 0x53, 0xae, 0x8c, 0x3e, 0x3b, 0xb0, 0xae, 0x40, 0x91,
+	0xa6, 0x71, 0x5f, 0x42, 0x83, 0xc0, 0x0b, 0xa4, 0x63, 0x17, 0x13, 0x7d, 0x1b, 0xea, 0x11, 0xf4,
+	0xf5, 0x91, 0x0a, 0x95, 0x3e, 0xa6, 0x5d, 0xfa, 0x57, 0x1a, 0xd4, 0xe3, 0x2a, 0x94, 0xf0, 0x76,
+	0x55, 0xe9, 0xed, 0x62, 0x01, 0x2c, 0x97, 0x0c, 0x60, 0x5b, 0x50, 0xe3, 0x3e, 0x35, 0x15, 0x9b,
+	0x2c, 0x5f, 0xc5, 0x26, 0xf2, 0x2e, 0xac, 0xa3, 0x3f, 0x12, 0x61, 0x4e, 0x1a, 0x62, 0x01, 0x0d,
+	0xb1, 0xc5, 0x37, 0x84, 0xc4, 0x70, 0x99, 0xbc, 0x0f, 0x57, 0x62, 0x58, 0xce, 0x17, 0x7d, 0xa1,
+	0x70, 0xd2, 0xed, 0x08, 0x7d, 0xe8, 0xfb, 0x47, 0x56, 0x38, 0xd6, 0x3f, 0x83, 0xf5, 0x4b, 0xba,
+	0xcc, 0x8f, 0x3f, 0xf4, 0x6c, 0x71, 0xef, 0x86, 0x81, 0x63, 0x1e, 0x0b, 0x27, 0xde, 0x08, 0x0f,
+	0x57, 0x35, 0xf8, 0x90, 0xa3, 0x22, 0x53, 0xaa, 0x0a, 0x9b, 0xd1, 0x7f, 0xa5, 0xc1, 0xfa, 0x25,
+	0x05, 0xcf, 0x8c, 0x5a, 0xda, 0x7f, 0x13, 0xb5, 0x72, 0x6f, 0x16, 0xb5, 0xf4, 0x0b, 0x0d, 0x1a,
+	0x09, 0x0b, 0x7a, 0xfb, 0x2b, 0x72, 0xed, 0x71, 0x5c, 0x9b, 0xce, 0x51, 0xa4, 0x79, 0x43, 0x4c,
+	0x54, 0xaa, 0x50, 0x42, 0x31, 0x27, 0x53, 0x85, 0x32, 0xae, 0x89, 0x09, 0xb9, 0x8d, 0x71, 0xcc,
+	0x7b, 0x21, 0x4d, 0xb5, 0xd1, 0x93, 0x09, 0xfd, 0x09, 0x5f, 0x34, 0xc4, 0x5e, 0xcc, 0xdb, 0x56,
+	0x13, 0x41, 0xf0, 0x26, 0x54, 0xf9, 0x41, 0x43, 0xdf, 0x1a, 0x52, 0xb4, 0xbc, 0xaa, 0xb1, 0x5c,
+	0xd0, 0x9f, 0x01, 0xb9, 0x6c, 0xf1, 0xe4, 0x13, 0x28, 0xd1, 0x73, 0xea, 0x32, 0x2e, 0x71, 0x2e,
+	0xb4, 0x7a, 0x14, 0x76, 0xa8, 0xcb, 0xfa, 0x1d, 0x2e, 0xaa, 0x7f, 0xbc, 0xda, 0x6a, 0x0b, 0xcc,
+	0xae, 0x37, 0x75, 0x18, 0x9d, 0xfa, 0x6c, 0x61, 0x48, 0x2a, 0xfd, 0x77, 0x39, 0x68, 0x29, 0xb6,
+	0x2a, 0xf8, 0x64, 0x09, 0x4f, 0xa9, 0x7c, 0x2e, 0x16, 0xe0, 0xbf, 0x99, 0x40, 0xbf, 0x0d, 0x30,
+	0xb2, 0x42, 0xf3, 0xa5, 0xe5, 0x32, 0x6a, 0x4b, 0xa9, 0x56, 0x47, 0x56, 0xf8, 0x33, 0x5c, 0xe0,
+	0xd9, 0x10, 0xdf, 0x9e, 0x85, 0xd4, 0x46, 0xf1, 0xe6, 0x8d, 0xf2, 0xc8, 0x0a, 0x9f, 0x87, 0xd4,
+	0x8e, 0xdd, 0xad, 0xfc, 0x36, 0x77, 0x4b, 0xca, 0xb3, 0x92, 0x92, 0x27, 0xe9, 0x42, 0xc5, 0x0f,
+	0x1c, 0x2f, 0x70, 0xd8, 0x42, 0xbe, 0x43, 0x34, 0xe7, 0x2f, 0x14, 0x62, 0x95, 0x25, 0x9f, 0x41,
+	0xce, 0xf8, 0xa3, 0xbb, 0x9e, 0x3b, 0xa4, 0xe8, 0xdd, 0x0a, 0x86, 0x98, 0xe8, 0xff, 0x8a, 0x59,
+	0xc5, 0x32, 0xec, 0xfe, 0x5f, 0x48, 0x51, 0xff, 0x25, 0xe6, 0xc6, 0x49, 0xe7, 0x4e, 0x8e, 0x61,
+	0x3d, 0xb2, 0x4e, 0x73, 0x86, 0x56, 0xab, 0xf4, 0xf3, 0xf5, 0x46, 0xdd, 0x3e, 0x4f, 0x2e, 0x87,
+	0xe4, 0x09, 0x5c, 0x4f, 0xf9, 0x96, 0x88, 0x61, 0xee, 0xb5, 0x2e, 0xe6, 0x6a, 0xd2, 0xc5, 0x28,
+	0x7e, 0x4b, 0x69, 0xe4, 0xdf, 0x4a, 0x1a, 0x04, 0x0a, 0x21, 0xa5, 0x36, 0xbe, 0x4d, 0xdd, 0xc0,
+	0xb1, 0xfe, 0x1d, 0x68, 0x2a, 0x11, 0x88, 0x50, 0x95, 0xf5, 0xce, 0xfa, 0xaf, 0x35, 0x68, 0xa5,
+	0x0e, 0x49, 0xb6, 0xa1, 0x28, 0xa2, 0xa5, 0x96, 0x28, 0x92, 0x51, 0x8a, 0xf2, 0x1e, 0x02, 0x40,
+	0x3e, 0x84, 0x0a, 0x95, 0x99, 0x64, 0x27, 0x97, 0x88, 0x92, 0x2a, 0xc1, 0x94, 0xf8, 0x08, 0x46,
+	0xbe, 0x07, 0xd5, 0x48, 0x9c, 0xa9, 0x2a, 0x22, 0x92, 0xbe, 0x24, 0x5a, 0x02, 0xf5, 0x07, 0x50,
+	0x8b, 0x7d, 0x9e, 0x7c, 0x0b, 0xaa, 0x53, 0x6b, 0x2e, 0x4b, 0x01, 0x91, 0x1c, 0x56, 0xa6, 0xd6,
+	0x1c, 0xab, 0x00, 0x72, 0x1d, 0xca, 0x7c, 0x73, 0x64, 0x89, 0xc7, 0xc8, 0x1b, 0xa5, 0xa9, 0x35,
+	0xff, 0xb1, 0x15, 0xea, 0x3b, 0xd0, 0x4c, 0x1e, 0x4b, 0x41, 0x55, 0xb8, 0x15, 0xd0, 0xc3, 0x11,
+	0xd5, 0xef, 0x42, 0x2b, 0x75, 0x1a, 0xa2, 0x43, 0xc3, 0x9f, 0x0d, 0xcc, 0x33, 0xba, 0x30, 0xf1,
+	0xb8, 0xa8, 0x3a, 0x55, 0xa3, 0xe6, 0xcf, 0x06, 0x9f, 0xd2, 0x05, 0xcf, 0x76, 0x43, 0xfd, 0x29,
+	0x34, 0x93, 0x49, 0x3a, 0xb7, 0xcd, 0xc0, 0x9b, 0xb9, 0x36, 0xf2, 0x2f, 0x1a, 0x62, 0xc2, 0xeb,
+	0xfc, 0x73, 0x4f, 0x68, 0x4b, 0x3c, 0x2b, 0x3f, 0xf5, 0x18, 0x8d, 0xa5, 0xf6, 0x02, 0xa3, 0x3b,
+	0x50, 0x44, 0x3d, 0xe0, 0xef, 0xc7, 0x71, 0x2a, 0xc0, 0xf3, 0x31, 0x79, 0x0c, 0x60, 0x31, 0x16,
+	0x38, 0x83, 0xd9, 0x92, 0x5d, 0xb3, 0x27, 0x9a, 0x2f, 0xbd, 0x4f, 0x4f, 0x4f, 0x2c, 0x27, 0xe8,
+	0xdf, 0x94, 0xfa, 0xb3, 0xb1, 0x44, 0xc6, 0x74, 0x28, 0x46, 0xaf, 0xff, 0xa9, 0x08, 0x25, 0x51,
+	0x9c, 0x90, 0x5e, 0xb2, 0xf4, 0xe5, 0x5c, 0xe5, 0x21, 0xc5, 0xaa, 0x3c, 0xa3, 0x02, 0x91, 0x3b,
+	0xe9, 0xfa, 0xb1, 0x5f, 0xbb, 0x78, 0xb5, 0x55, 0xc6, 0x58, 0x7c, 0xfc, 0x70, 0x59, 0x4c, 0xae,
+	0xaa, 0xb5, 0x54, 0xe5, 0x5a, 0x78, 0xe3, 0xca, 0xf5, 0x3a, 0x94, 0xdd, 0xd9, 0xd4, 0x64, 0xf3,
+	0x50, 0x7a, 0xa0, 0x92, 0x3b, 0x9b, 0x3e, 0x9b, 0xa3, 0x96, 0x30, 0x8f, 0x59, 0x13, 0xdc, 0x12,
+	0xfe, 0xa7, 0x82, 0x0b, 0x7c, 0xf3, 0x00, 0x1a, 0xb1, 0x94, 0xc5, 0xb1, 0x3b, 0xe5, 0xc4, 0x2d,
+	0x51, 0xdb, 0x8e, 0x1f, 0xca, 0x5b, 0xd6, 0xa2, 0x14, 0xe6, 0xd8, 0x26, 0xdb, 0xc9, 0x42, 0x0d,
+	0x33, 0x9d, 0x0a, 0x9a, 0x54, 0xac, 0x16, 0xe3, 0x79, 0x0e, 0x3f, 0x00, 0x37, 0x32, 0x01, 0xa9,
+	0x22, 0xa4, 0xc2, 0x17, 0x70, 0xf3, 0x1d, 0x68, 0x2d, 0x93, 0x05, 0x01, 0x01, 0xc1, 0x65, 0xb9,
+	0x8c, 0xc0, 0x0f, 0x60, 0xc3, 0xa5, 0x73, 0x66, 0xa6, 0xd1, 0x35, 0x44, 0x13, 0xbe, 0x77, 0x9a,
+	0xa4, 0xf8, 0x2e, 0x34, 0x97, 0xee, 0x09, 0xb1, 0x75, 0x51, 0x2e, 0x47, 0xab, 0x08, 0xbb, 0x01,
+	0x95, 0x28, 0x55, 0x6b, 0x20, 0xa0, 0x6c, 0x89, 0x0c, 0x2d, 0x4a, 0xfe, 0x02, 0x1a, 0xce, 0x26,
+	0x4c, 0x32, 0x69, 0x22, 0x06, 0x93, 0x3f, 0x43, 0xac, 0x23, 0xf6, 0x36, 0x34, 0x94, 0x75, 0x0b,
+	0x5c, 0x0b, 0x71, 0x75, 0xb5, 0x88, 0xa0, 0x1d, 0x68, 0xfb, 0x81, 0xe7, 0x7b, 0x21, 0x0d, 0x4c,
+	0xcb, 0xb6, 0x03, 0x1a, 0x86, 0x9d, 0xb6, 0xe0, 0xa7, 0xd6, 0x0f, 0xc5, 0x32, 0xe7, 0x17, 0x58,
+	0xae, 0xed, 0x4d, 0x4d, 0x77, 0x36, 0x1d, 0xd0, 0xa0, 0xb3, 0x2e, 0xf8, 0x89, 0xc5, 0x27, 0xb8,
+	0x46, 0x76, 0x81, 0x24, 0x40, 0xe2, 0xcb, 0x44, 0x24, 0x9c, 0x71, 0x24, 0x26, 0x9c, 0x1f, 0x42,
+	0x59, 0xa5, 0xb5, 0x1b, 0x50, 0xec, 0x47, 0xce, 0xad, 0x60, 0x88, 0x09, 0x0f, 0x77, 0x87, 0xbe,
+	0x2f, 0x9b, 0x38, 0x7c, 0xa8, 0xff, 0x1c, 0xca, 0x52, 0x07, 0x32, 0x4b, 0xfb, 0x1f, 0x42, 0xdd,
+	0xb7, 0x02, 0x2e, 0x99, 0x78, 0x81, 0xaf, 0x0a, 0xa7, 0x13, 0x2b, 0xe0, 0x1d, 0x9d, 0x44, 0x9d,
+	0x5f, 0x43, 0xbc, 0x58, 0xd2, 0xef, 0x41, 0x23, 0x81, 0xe1, 0xc7, 0x42, 0xd5, 0x54, 0x7e, 0x02,
+	0x27, 0xd1, 0x97, 0x73, 0xcb, 0x2f, 0xeb, 0xf7, 0xa1, 0x1a, 0x3d, 0x37, 0xcf, 0xef, 0x95, 0x34,
+	0x35, 0xf9, 0x82, 0x62, 0xca, 0x19, 0xfa, 0xde, 0x4b, 0x1a, 0x48, 0x33, 0x13, 0x13, 0xfd, 0x79,
+	0xcc, 0xaf, 0x89, 0xe0, 0x43, 0x76, 0xa1, 0x2c, 0xfd, 0x5a, 0x47, 0x4b, 0x74, 0x29, 0x4e, 0xd0,
+	0xb1, 0xa9, 0x2e, 0x85, 0x70, 0x73, 0x4b, 0xb6, 0xb9, 0x38, 0xdb, 0x09, 0x54, 0x94, 0xef, 0x4a,
+	0x3a, 0x78, 0xc1, 0xb1, 0x9d, 0x76, 0xf0, 0x92, 0xe9, 0x12, 0xc8, 0x15, 0x2e, 0x74, 0x46, 0x2e,
+	0xb5, 0xcd, 0xa5, 0x55, 0xe2, 0x37, 0x2a, 0x46, 0x4b, 0x6c, 0x3c, 0x56, 0x26, 0xa8, 0x7f, 0x00,
+	0x25, 0x71, 0xb6, 0x4c, 0x8f, 0x98, 0x15, 0xe5, 0xfe, 0xa8, 0x41, 0x45, 0xb9, 0xfe, 0x4c, 0xa2,
+	0xc4, 0xa1, 0x73, 0xdf, 0xf4, 0xd0, 0xff, 0x7b, 0x5f, 0xb6, 0x0b, 0x44, 0xb8, 0xac, 0x73, 0x8f,
+	0x39, 0xee, 0xc8, 0x14, 0xb2, 0x16, 0x6e, 0xad, 0x8d, 0x3b, 0xa7, 0xb8, 0x71, 0x82, 0x62, 0x1f,
+	0x40, 0xf3, 0x33, 0xcf, 0x9e, 0x4d, 0x96, 0x39, 0x4e, 0xb2, 0x62, 0xd1, 0xde, 0xb0, 0xcf, 0xa6,
+	0xd2, 0x88, 0xdc, 0x32, 0x8d, 0x78, 0xf7, 0x36, 0xd4, 0x62, 0x0d, 0x1d, 0x52, 0x86, 0xfc, 0x13,
+	0xfa, 0xb2, 0xbd, 0x46, 0x6a, 0xbc, 0x55, 0x8f, 0xe5, 0x79, 0x5b, 0xdb, 0xff, 0xa2, 0x08, 0xad,
+	0xc3, 0xfe, 0x83, 0xe3, 0x43, 0xdf, 0x9f, 0x38, 0x43, 0x0b, 0xeb, 0xb9, 0x3d, 0x28, 0x60, 0x49,
+	0x9b, 0xd1, 0xba, 0xef, 0x66, 0xf5, 0x56, 0xc8, 0x3e, 0x14, 0xb1, 0xb2, 0x25, 0x59, 0x1d, 0xfc,
+	0x6e, 0x66, 0x8b, 0x85, 0x7f, 0x44, 0xd4, 0xbe, 0x97, 0x1b, 0xf9, 0xdd, 0xac, 0x3e, 0x0b, 0xf9,
+	0x04, 0xaa, 0xcb, 0x92, 0x73, 0x55, 0x3b, 0xbf, 0xbb, 0xb2, 0xe3, 0xc2, 0xe9, 0x97, 0xc9, 0xf4,
+	0xaa, 0xe6, 0x77, 0x77, 0x65, 0x6b, 0x82, 0x1c, 0x40, 0x59, 0x15, 0x34, 0xd9, 0x0d, 0xf7, 0xee,
+	0x8a, 0x6e, 0x08, 0x17, 0x8f, 0xa8, 0x22, 0xb3, 0xfe, 0x15, 0xe8, 0x66, 0xb6, 0x6c, 0xc8, 0x5d,
+	0x28, 0xc9, 0xdc, 0x2f, 0xb3, 0x75, 0xde, 0xcd, 0xee, 0x69, 0xf0, 0x4b, 0x2e, 0xeb, 0xe8, 0x55,
+	0xff, 0x5c, 0x74, 0x57, 0xf6, 0x96, 0xc8, 0x21, 0x40, 0xac, 0x18, 0x5c, 0xf9, 0x97, 0x44, 0x77,
+	0x75, 0xcf, 0x88, 0xdc, 0x87, 0xca, 0xb2, 0x0f, 0x98, 0xfd, 0x57, 0x41, 0x77, 0x55, 0x1b, 0xa7,
+	0x7f, 0xf3, 0x9f, 0x7f, 0xdd, 0xd4, 0x7e, 0x73, 0xb1, 0xa9, 0x7d, 0x75, 0xb1, 0xa9, 0x7d, 0x7d,
+	0xb1, 0xa9, 0xfd, 0xe1, 0x62, 0x53, 0xfb, 0xcb, 0xc5, 0xa6, 0xf6, 0xdb, 0xbf, 0x6d, 0x6a, 0x83,
+	0x12, 0xda, 0xe1, 0x47, 0xff, 0x1e, 0x00, 0x5b, 0x73, 0xab, 0x1f, 0x49, 0x1b, 0x00, 0x00,
 }
 
 func (this *Request) Equal(that interface{}) bool {
@@ -4477,6 +4502,15 @@ func (this *ResponseCheckTx) Equal(that interface{}) bool {
 	if this.Codespace != that1.Codespace {
 		return false
 	}
+	if this.Priority != that1.Priority {
+		return false
+	}
+	if this.Sender != that1.Sender {
+		return false
+	}
+	if this.Nonce != that1.Nonce {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -7043,6 +7077,23 @@ func (m *ResponseCheckTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Nonce != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.Sender) > 0 {
+		i -= len(m.Sender)
+		copy(dAtA[i:], m.Sender)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Sender)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.Priority != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Priority))
+		i--
+		dAtA[i] = 0x48
+	}
 	if len(m.Codespace) > 0 {
 		i -= len(m.Codespace)
 		copy(dAtA[i:], m.Codespace)
@@ -8618,8 +8669,14 @@ func NewPopulatedResponseCheckTx(r randyTypes, easy bool) *ResponseCheckTx {
 		}
 	}
 	this.Codespace = string(randStringTypes(r))
+	this.Priority = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Priority *= -1
+	}
+	this.Sender = string(randStringTypes(r))
+	this.Nonce = uint64(uint64(r.Uint32()))
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedTypes(r, 9)
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 12)
 	}
 	return this
 }
@@ -9832,6 +9889,16 @@ func (m *ResponseCheckTx) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if m.Priority != 0 {
+		n += 1 + sovTypes(uint64(m.Priority))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Nonce != 0 {
+		n += 1 + sovTypes(uint64(m.Nonce))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -13817,6 +13884,76 @@ func (m *ResponseCheckTx) Unmarshal(dAtA []byte) error {
 			}
 			m.Codespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			m.Priority = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Priority |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
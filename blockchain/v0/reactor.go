@@ -313,11 +313,14 @@ FOR_LOOP:
 				// Try again quickly next loop.
 				didProcessCh <- struct{}{}
 			}
-			if bcR.verifier != nil {
-				if err := bcR.verifier.VerifyRandomData(first.RandomData, second.RandomData); err != nil {
-					bcR.poolRoutineHandleErr(err, first, second)
-					continue FOR_LOOP
-				}
+			// The random data of the second block is the signature of the random
+			// data of the first one followed by the seed returned by EndBlock for
+			// the first block, which is not known until the first block is applied.
+			// Hence we verify the first block against its predecessor and the seed
+			// stored in the state instead.
+			if err := bcR.verifyRandomData(state, first); err != nil {
+				bcR.poolRoutineHandleErr(err, first, second)
+				continue FOR_LOOP
 			}
 			firstParts := first.MakePartSet(types.BlockPartSizeBytes)
 			firstPartsHeader := firstParts.Header()
@@ -349,7 +352,9 @@ FOR_LOOP:
 				bcR.pool.PopRequest()
 
 				// TODO: batch saves so we dont persist to disk every block
-				bcR.store.SaveBlock(first, firstParts, second.LastCommit)
+				seenCommit := types.NewCommit(second.LastCommit.BlockID, second.LastCommit.Precommits)
+				seenCommit.AggregateSigners = bcR.blockExec.RandomDataSigners(state, first, seenCommit, bcR.verifier)
+				bcR.store.SaveBlock(first, firstParts, seenCommit)
 
 				// TODO: same thing for app - but we would need a way to
 				// get the hash without persisting the state
@@ -496,6 +501,23 @@ func (m *bcStatusResponseMessage) String() string {
 
 //---------------------------------------
 
+// verifyRandomData checks that the random data of the block is the threshold
+// signature of the random data of the previous block and the seed returned by
+// EndBlock for it. The random data is not part of the block hash, so it is not
+// protected by the commit.
+func (bcR *BlockchainReactor) verifyRandomData(state sm.State, block *types.Block) error {
+	prevRandomData := []byte(types.InitialRandomData)
+	if block.Height > 1 {
+		prevMeta := bcR.store.LoadBlockMeta(block.Height - 1)
+		if prevMeta == nil {
+			return fmt.Errorf("no block meta found for height %d", block.Height-1)
+		}
+		prevRandomData = prevMeta.Header.RandomData
+	}
+
+	return bcR.blockExec.VerifyRandomData(state, block, prevRandomData, bcR.verifier)
+}
+
 func (bcR *BlockchainReactor) poolRoutineHandleErr(err error, first, second *types.Block) {
 	bcR.Logger.Error("Error in validation", "err", err)
 	peerID := bcR.pool.RedoRequest(first.Height)
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"time"
 
+	dkgtypes "github.com/corestario/dkglib/lib/types"
 	amino "github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/behaviour"
 	"github.com/tendermint/tendermint/libs/log"
@@ -73,11 +74,19 @@ type BlockchainReactor struct {
 	eventsFromFSMCh chan bcFsmMessage
 
 	swReporter *behaviour.SwitchReporter
+
+	verifier dkgtypes.Verifier
+}
+
+type BlockChainReactorOption func(reactor *BlockchainReactor)
+
+func WithVerifier(verifier dkgtypes.Verifier) BlockChainReactorOption {
+	return func(r *BlockchainReactor) { r.verifier = verifier }
 }
 
 // NewBlockchainReactor returns new reactor instance.
 func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockStore,
-	fastSync bool) *BlockchainReactor {
+	fastSync bool, options ...BlockChainReactorOption) *BlockchainReactor {
 
 	if state.LastBlockHeight != store.Height() {
 		panic(fmt.Sprintf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
@@ -100,6 +109,9 @@ func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *st
 		eventsFromFSMCh:  eventsFromFSMCh,
 		errorsForFSMCh:   errorsForFSMCh,
 	}
+	for _, option := range options {
+		option(bcR)
+	}
 	fsm := NewFSM(startHeight, bcR)
 	bcR.fsm = fsm
 	bcR.BaseReactor = *p2p.NewBaseReactor("BlockchainReactor", bcR)
@@ -427,7 +439,16 @@ func (bcR *BlockchainReactor) processBlock() error {
 		return errBlockVerificationFailure
 	}
 
-	bcR.store.SaveBlock(first, firstParts, second.LastCommit)
+	err = bcR.verifyRandomData(first)
+	if err != nil {
+		bcR.Logger.Error("error during random data verification", "err", err,
+			"height", first.Height)
+		return errRandomDataVerificationFailure
+	}
+
+	seenCommit := types.NewCommit(second.LastCommit.BlockID, second.LastCommit.Precommits)
+	seenCommit.AggregateSigners = bcR.blockExec.RandomDataSigners(bcR.state, first, seenCommit, bcR.verifier)
+	bcR.store.SaveBlock(first, firstParts, seenCommit)
 
 	bcR.state, err = bcR.blockExec.ApplyBlock(bcR.state, firstID, first)
 	if err != nil {
@@ -437,6 +458,23 @@ func (bcR *BlockchainReactor) processBlock() error {
 	return nil
 }
 
+// verifyRandomData checks that the random data of the block is the threshold
+// signature of the random data of the previous block and the seed returned by
+// EndBlock for it. The random data is not part of the block hash, so it is not
+// protected by the commit.
+func (bcR *BlockchainReactor) verifyRandomData(block *types.Block) error {
+	prevRandomData := []byte(types.InitialRandomData)
+	if block.Height > 1 {
+		prevMeta := bcR.store.LoadBlockMeta(block.Height - 1)
+		if prevMeta == nil {
+			return fmt.Errorf("no block meta found for height %d", block.Height-1)
+		}
+		prevRandomData = prevMeta.Header.RandomData
+	}
+
+	return bcR.blockExec.VerifyRandomData(bcR.state, block, prevRandomData, bcR.verifier)
+}
+
 // Implements bcRNotifier
 // sendStatusRequest broadcasts `BlockStore` height.
 func (bcR *BlockchainReactor) sendStatusRequest() {
@@ -171,7 +171,7 @@ var (
 	errDuplicateBlock                  = errors.New("fast sync received duplicate block from peer")
 	errBlockVerificationFailure        = errors.New("fast sync block verification failure")              // xx
 	errSlowPeer                        = errors.New("fast sync peer is not sending us data fast enough") // xx
-
+	errRandomDataVerificationFailure   = errors.New("fast sync random data verification failure")
 )
 
 func init() {
@@ -270,7 +270,16 @@ func init() {
 				return waitForBlock, err
 
 			case processedBlockEv:
-				if data.err != nil {
+				if data.err == errRandomDataVerificationFailure {
+					// The random data is not covered by the commit in the second block,
+					// so only the peer that sent the first block is at fault.
+					first, _, _ := fsm.pool.FirstTwoBlocksAndPeers()
+					fsm.logger.Error("error processing block", "err", data.err,
+						"first", first.block.Height)
+					fsm.logger.Error("send peer error for", "peer", first.peer.ID)
+					fsm.toBcR.sendPeerError(data.err, first.peer.ID)
+					fsm.pool.RemovePeer(first.peer.ID, data.err)
+				} else if data.err != nil {
 					first, second, _ := fsm.pool.FirstTwoBlocksAndPeers()
 					fsm.logger.Error("error processing block", "err", data.err,
 						"first", first.block.Height, "second", second.block.Height)
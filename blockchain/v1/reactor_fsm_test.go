@@ -352,6 +352,46 @@ func TestFSMBlockVerificationFailure(t *testing.T) {
 	executeFSMTests(t, tests, false)
 }
 
+func TestFSMRandomDataVerificationFailure(t *testing.T) {
+	// process block failure, should remove P1 and keep the blocks received from P2
+	randomDataFailure := sProcessedBlockEv("waitForBlock", "waitForBlock", errRandomDataVerificationFailure)
+	randomDataFailure.wantNewBlocks = []int64{2, 3}
+
+	tests := []testFields{
+		{
+			name:               "random data verification failure",
+			startingHeight:     1,
+			maxRequestsPerPeer: 3,
+			steps: []fsmStepTestValues{
+				sStartFSMEv(),
+
+				// add P1 and get block 1 from it
+				sStatusEv("waitForPeer", "waitForBlock", "P1", 1, nil),
+				sMakeRequestsEv("waitForBlock", "waitForBlock", maxNumRequests),
+				sBlockRespEv("waitForBlock", "waitForBlock", "P1", 1, []int64{}),
+
+				// add P2 and get blocks 2-3 from it
+				sStatusEv("waitForBlock", "waitForBlock", "P2", 3, nil),
+				sMakeRequestsEv("waitForBlock", "waitForBlock", maxNumRequests),
+				sBlockRespEv("waitForBlock", "waitForBlock", "P2", 2, []int64{1}),
+				sBlockRespEv("waitForBlock", "waitForBlock", "P2", 3, []int64{1, 2}),
+
+				randomDataFailure,
+
+				// get block 1 from P2
+				sMakeRequestsEv("waitForBlock", "waitForBlock", maxNumRequests),
+				sBlockRespEv("waitForBlock", "waitForBlock", "P2", 1, []int64{2, 3}),
+
+				// finish after processing blocks 1 and 2
+				sProcessedBlockEv("waitForBlock", "waitForBlock", nil),
+				sProcessedBlockEv("waitForBlock", "finished", nil),
+			},
+		},
+	}
+
+	executeFSMTests(t, tests, false)
+}
+
 func TestFSMBadBlockFromPeer(t *testing.T) {
 	tests := []testFields{
 		{
@@ -163,7 +163,12 @@ func (state *pcState) handle(event Event) (Event, error) {
 			return pcBlockVerificationFailure{peerID: firstItem.peerID, height: first.Height}, nil
 		}
 
-		state.context.saveBlock(first, firstParts, second.LastCommit)
+		err = state.context.verifyRandomData(state.tdState, first)
+		if err != nil {
+			return pcBlockVerificationFailure{peerID: firstItem.peerID, height: first.Height}, nil
+		}
+
+		state.context.saveBlock(state.tdState, first, firstParts, second.LastCommit)
 
 		state.tdState, err = state.context.applyBlock(state.tdState, firstID, first)
 		if err != nil {
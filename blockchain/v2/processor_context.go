@@ -3,6 +3,8 @@ package v2
 import (
 	"fmt"
 
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+
 	"github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
@@ -11,22 +13,29 @@ import (
 type processorContext interface {
 	applyBlock(state state.State, blockID types.BlockID, block *types.Block) (state.State, error)
 	verifyCommit(chainID string, blockID types.BlockID, height int64, commit *types.Commit) error
-	saveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit)
+	verifyRandomData(state state.State, block *types.Block) error
+	saveBlock(state state.State, block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit)
 }
 
+// pContext is the processorContext of a node, which verifies the commits and
+// the random data of the blocks against its state and saves them to its store.
+// It is not used yet: the node does not support fast sync v2.
 // nolint:unused
 type pContext struct {
 	store    *store.BlockStore
 	executor *state.BlockExecutor
 	state    *state.State
+	verifier dkgtypes.Verifier
 }
 
 // nolint:unused,deadcode
-func newProcessorContext(st *store.BlockStore, ex *state.BlockExecutor, s *state.State) *pContext {
+func newProcessorContext(st *store.BlockStore, ex *state.BlockExecutor, s *state.State,
+	verifier dkgtypes.Verifier) *pContext {
 	return &pContext{
 		store:    st,
 		executor: ex,
 		state:    s,
+		verifier: verifier,
 	}
 }
 
@@ -38,19 +47,44 @@ func (pc *pContext) verifyCommit(chainID string, blockID types.BlockID, height i
 	return pc.state.Validators.VerifyCommit(chainID, blockID, height, commit)
 }
 
-func (pc *pContext) saveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
+// verifyRandomData checks that the random data of the block is the threshold
+// signature of the random data of the previous block and the seed returned by
+// EndBlock for it. The random data is not part of the block hash, so it is not
+// protected by the commit.
+func (pc *pContext) verifyRandomData(state state.State, block *types.Block) error {
+	prevRandomData := []byte(types.InitialRandomData)
+	if block.Height > 1 {
+		prevMeta := pc.store.LoadBlockMeta(block.Height - 1)
+		if prevMeta == nil {
+			return fmt.Errorf("no block meta found for height %d", block.Height-1)
+		}
+		prevRandomData = prevMeta.Header.RandomData
+	}
+
+	return pc.executor.VerifyRandomData(state, block, prevRandomData, pc.verifier)
+}
+
+// saveBlock saves the block with the signers of its random data, see
+// BlockExecutor.RandomDataSigners.
+func (pc *pContext) saveBlock(state state.State, block *types.Block, blockParts *types.PartSet,
+	seenCommit *types.Commit) {
+	seenCommit = types.NewCommit(seenCommit.BlockID, seenCommit.Precommits)
+	seenCommit.AggregateSigners = pc.executor.RandomDataSigners(state, block, seenCommit, pc.verifier)
 	pc.store.SaveBlock(block, blockParts, seenCommit)
 }
 
 type mockPContext struct {
 	applicationBL  []int64
 	verificationBL []int64
+	randomDataBL   []int64
 }
 
-func newMockProcessorContext(verificationBlackList []int64, applicationBlackList []int64) *mockPContext {
+func newMockProcessorContext(verificationBlackList []int64, applicationBlackList []int64,
+	randomDataBlackList []int64) *mockPContext {
 	return &mockPContext{
 		applicationBL:  applicationBlackList,
 		verificationBL: verificationBlackList,
+		randomDataBL:   randomDataBlackList,
 	}
 }
 
@@ -72,5 +106,15 @@ func (mpc *mockPContext) verifyCommit(chainID string, blockID types.BlockID, hei
 	return nil
 }
 
-func (mpc *mockPContext) saveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
+func (mpc *mockPContext) verifyRandomData(state state.State, block *types.Block) error {
+	for _, h := range mpc.randomDataBL {
+		if h == block.Height {
+			return fmt.Errorf("generic random data verification error")
+		}
+	}
+	return nil
+}
+
+func (mpc *mockPContext) saveBlock(state state.State, block *types.Block, blockParts *types.PartSet,
+	seenCommit *types.Commit) {
 }
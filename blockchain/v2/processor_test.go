@@ -22,6 +22,7 @@ type params struct {
 	blocksSynced int64
 	verBL        []int64
 	appBL        []int64
+	randBL       []int64
 	draining     bool
 }
 
@@ -34,7 +35,7 @@ func makePcBlock(height int64) *types.Block {
 func makeState(p *params) *pcState {
 	var (
 		tdState = tdState.State{}
-		context = newMockProcessorContext(p.verBL, p.appBL)
+		context = newMockProcessorContext(p.verBL, p.appBL, p.randBL)
 	)
 	state := newPcState(p.height, tdState, "test", context)
 
@@ -244,6 +245,16 @@ func TestPcProcessBlockFailures(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "blocks H+1 and H+2 present - H+1 random data verification fails ",
+			steps: []pcFsmMakeStateValues{
+				{
+					currentState: &params{items: []pcBlock{{"P1", 1}, {"P2", 2}}, randBL: []int64{1}}, event: pcProcessBlock{},
+					wantState:     &params{items: []pcBlock{{"P1", 1}, {"P2", 2}}, randBL: []int64{1}},
+					wantNextEvent: pcBlockVerificationFailure{peerID: "P1", height: 1},
+				},
+			},
+		},
 		{
 			name: "blocks H+1 and H+2 present - H+1 applyBlock fails ",
 			steps: []pcFsmMakeStateValues{
@@ -18,6 +18,8 @@ func main() {
 		chainID          = flag.String("chain-id", "mychain", "chain id")
 		privValKeyPath   = flag.String("priv-key", "", "priv val key file path")
 		privValStatePath = flag.String("priv-state", "", "priv val state file path")
+		blsKeyPath       = flag.String("bls-key-file", "", "BLS key share file path, in the format of bls_key_file")
+		dkgVerifierPath  = flag.String("dkg-verifier-file", "", "file path the key of the last DKG round is saved to")
 
 		logger = log.NewTMLogger(
 			log.NewSyncWriter(os.Stdout),
@@ -31,9 +33,25 @@ func main() {
 		"chainID", *chainID,
 		"privKeyPath", *privValKeyPath,
 		"privStatePath", *privValStatePath,
+		"blsKeyPath", *blsKeyPath,
+		"dkgVerifierPath", *dkgVerifierPath,
 	)
 
 	pv := privval.LoadFilePV(*privValKeyPath, *privValStatePath)
+	// The BLS key share of the genesis signs the random shares until the node
+	// sends the key share of a DKG round, which is saved to dkgVerifierPath.
+	if *blsKeyPath != "" {
+		if err := pv.LoadBLSShareFile(*blsKeyPath); err != nil {
+			logger.Error("Failed to load BLS key share", "path", *blsKeyPath, "err", err)
+			os.Exit(1)
+		}
+	}
+	if *dkgVerifierPath != "" {
+		if err := pv.LoadDKGVerifierFile(*dkgVerifierPath); err != nil {
+			logger.Error("Failed to load DKG verifier", "path", *dkgVerifierPath, "err", err)
+			os.Exit(1)
+		}
+	}
 
 	var dialer privval.SocketDialer
 	protocol, address := cmn.ProtocolAndAddress(*addr)
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"github.com/spf13/cobra"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+var (
+	blsThreshold int
+	blsNumShares int
+	blsOutputDir string
+)
+
+func init() {
+	GenBLSKeysCmd.Flags().IntVar(&blsThreshold, "threshold", 1,
+		"Number of shares required to recover the random data")
+	GenBLSKeysCmd.Flags().IntVar(&blsNumShares, "shares", 1,
+		"Number of shares, one for each validator")
+	GenBLSKeysCmd.Flags().StringVar(&blsOutputDir, "o", ".",
+		"Directory to store the shares in")
+}
+
+// GenBLSKeysCmd allows the generation of a t-of-n BLS threshold key set. It
+// writes the shares to bls_key_<i>.json files and prints the genesis fields of
+// the key set to the standard output.
+var GenBLSKeysCmd = &cobra.Command{
+	Use:   "gen_bls_keys",
+	Short: "Generate a t-of-n BLS threshold key set",
+	Long: `gen_bls_keys will create "shares" number of BLS key shares and store them in
+bls_key_0.json, bls_key_1.json, ... files. Each validator needs one of them as its
+bls_key_file. The printed master public key, threshold and number of shares go to
+the bls_master_pub_key, bls_threshold and bls_num_shares genesis fields.
+
+Example:
+
+	tendermint gen_bls_keys --threshold 3 --shares 4 --o ./bls_keys
+	`,
+	RunE: genBLSKeys,
+}
+
+// blsGenesisFields are the genesis fields describing a BLS key set.
+type blsGenesisFields struct {
+	BLSMasterPubKey string `json:"bls_master_pub_key"`
+	BLSThreshold    int    `json:"bls_threshold"`
+	BLSNumShares    int    `json:"bls_num_shares"`
+}
+
+func genBLSKeys(cmd *cobra.Command, args []string) error {
+	fields, err := writeBLSKeys(blsOutputDir, blsThreshold, blsNumShares)
+	if err != nil {
+		return err
+	}
+	jsbz, err := cdc.MarshalJSONIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsbz))
+	return nil
+}
+
+// writeBLSKeys generates a t-of-n BLS key set, stores its shares in dir and
+// returns the genesis fields describing it.
+func writeBLSKeys(dir string, t, n int) (*blsGenesisFields, error) {
+	keyring, err := blsShare.NewBLSKeyring(t, n)
+	if err != nil {
+		return nil, err
+	}
+	if err := cmn.EnsureDir(dir, nodeDirPerm); err != nil {
+		return nil, err
+	}
+	for id := 0; id < keyring.N; id++ {
+		blsKeyFile := filepath.Join(dir, fmt.Sprintf("bls_key_%d.json", id))
+		if cmn.FileExists(blsKeyFile) {
+			return nil, fmt.Errorf("BLS key at %s already exists", blsKeyFile)
+		}
+		if err := saveBLSShare(blsKeyFile, keyring.Shares[id]); err != nil {
+			return nil, err
+		}
+	}
+
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &blsGenesisFields{
+		BLSMasterPubKey: masterPubKey,
+		BLSThreshold:    keyring.T,
+		BLSNumShares:    keyring.N,
+	}, nil
+}
+
+// saveBLSShare persists the BLS key share to filePath in the format expected
+// by bls_key_file.
+func saveBLSShare(filePath string, share *blsShare.BLSShare) error {
+	shareJSON, err := blsShare.NewBLSShareJSON(share)
+	if err != nil {
+		return fmt.Errorf("failed to encode BLS key share: %v", err)
+	}
+	jsbz, err := json.Marshal(shareJSON)
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(filePath, jsbz, 0600)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBLSKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen_bls_keys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fields, err := writeBLSKeys(dir, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fields.BLSThreshold)
+	assert.Equal(t, 3, fields.BLSNumShares)
+	masterPubKey, err := blsShare.LoadPubKey(fields.BLSMasterPubKey, fields.BLSNumShares)
+	require.NoError(t, err)
+
+	// Existing keys are not overwritten.
+	_, err = writeBLSKeys(dir, 2, 3)
+	assert.Error(t, err)
+
+	// Any two of the shares recover random data that verifies with the master
+	// public key.
+	msg := []byte("random message")
+	verifiers := make([]*blsShare.BLSVerifier, 3)
+	sigs := make([]blsShare.BLSSigner, len(verifiers))
+	for id := range verifiers {
+		shareJSON, err := blsShare.LoadBLSShareJSON(filepath.Join(dir, fmt.Sprintf("bls_key_%d.json", id)))
+		require.NoError(t, err)
+		share, err := shareJSON.Deserialize()
+		require.NoError(t, err)
+		verifiers[id] = blsShare.NewBLSVerifier(masterPubKey, share, 2, 3)
+		sig, err := verifiers[id].Sign(msg)
+		require.NoError(t, err)
+		sigs[id] = &testBLSSigner{sig}
+	}
+	for i := range sigs {
+		randomData, err := verifiers[i].Recover(msg, append(append([]blsShare.BLSSigner{}, sigs[:i]...), sigs[i+1:]...))
+		require.NoError(t, err)
+		assert.NoError(t, verifiers[i].VerifyRandomData(msg, randomData))
+	}
+}
+
+type testBLSSigner struct {
+	blsSignature []byte
+}
+
+func (s *testBLSSigner) GetBLSSignature() []byte { return s.blsSignature }
+func (s *testBLSSigner) GetHash() []byte         { return []byte("hash") }
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"text/template"
@@ -24,14 +23,16 @@ var InitFilesCmd = &cobra.Command{
 }
 
 func initFiles(cmd *cobra.Command, args []string) error {
-	return initFilesWithConfig(config)
+	return initFilesWithConfig(config, !withoutGeneratedBLSKeys)
 }
 
 func InitFilesWithConfig(config *cfg.Config) error {
-	return initFilesWithConfig(config)
+	return initFilesWithConfig(config, !withoutGeneratedBLSKeys)
 }
 
-func initFilesWithConfig(config *cfg.Config) error {
+// initFilesWithConfig initialises the files of a node. If genBLSKey is true
+// and there is no BLS key yet, a 1-of-1 BLS key set is generated for it.
+func initFilesWithConfig(config *cfg.Config, genBLSKey bool) error {
 	// private validator
 	privValKeyFile := config.PrivValidatorKeyFile()
 	privValStateFile := config.PrivValidatorStateFile()
@@ -57,37 +58,21 @@ func initFilesWithConfig(config *cfg.Config) error {
 		logger.Info("Generated node key", "path", nodeKeyFile)
 	}
 
-	blsKeyring, err := blsShare.NewBLSKeyring(config.DKGOnChainConfig.BLSThreshold, config.DKGOnChainConfig.BLSNumShares)
-	if err != nil {
-		return fmt.Errorf("failed to run NewBLSKeyring: %w", err)
-	}
-
+	// A 1-of-1 BLS key set allows for single-node execution, e.g. $ tendermint node.
+	var blsKeyring *blsShare.BLSKeyring
 	blsKeyFile := config.BLSKeyFile()
 	if cmn.FileExists(blsKeyFile) {
-		logger.Info("Found node key", "path", blsKeyFile)
-	} else if !withoutGeneratedBLSKeys {
-		f, err := os.Create(blsKeyFile)
+		logger.Info("Found BLS key", "path", blsKeyFile)
+	} else if genBLSKey {
+		var err error
+		blsKeyring, err = blsShare.NewBLSKeyring(1, 1)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to generate BLS key: %v", err)
 		}
-		defer f.Close()
-
-		share, ok := blsKeyring.Shares[config.NodeID]
-		if !ok {
-			return fmt.Errorf("node id #%d is unexpected", config.NodeID)
-		}
-
-		shareJSON, err := blsShare.NewBLSShareJSON(share)
-		if err != nil {
-			return fmt.Errorf("failed to load LoadBLSShareJSON: %w", err)
-		}
-
-		err = json.NewEncoder(f).Encode(shareJSON)
-		if err != nil {
+		if err := saveBLSShare(blsKeyFile, blsKeyring.Shares[0]); err != nil {
 			return err
 		}
-
-		logger.Info("Generated node key", "path", blsKeyFile)
+		logger.Info("Generated BLS key", "path", blsKeyFile)
 	}
 
 	// genesis file
@@ -107,11 +92,16 @@ func initFilesWithConfig(config *cfg.Config) error {
 			Power:   10,
 		}}
 
-		// This keypair allows for single-node execution, e.g. $ tendermint node.
-		genDoc.BLSMasterPubKey = blsShare.DefaultBLSVerifierMasterPubKey
-		genDoc.BLSThreshold = 2
-		genDoc.BLSNumShares = 4
 		genDoc.DKGNumBlocks = 1000
+		if blsKeyring != nil {
+			masterPubKey, err := blsShare.DumpMasterPubKey(blsKeyring.MasterPubKey)
+			if err != nil {
+				return err
+			}
+			genDoc.BLSMasterPubKey = masterPubKey
+			genDoc.BLSThreshold = blsKeyring.T
+			genDoc.BLSNumShares = blsKeyring.N
+		}
 
 		if err := genDoc.SaveAs(genFile); err != nil {
 			return err
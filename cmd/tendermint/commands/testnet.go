@@ -36,6 +36,7 @@ var (
 
 	dkgNumBlocks            int64
 	withoutGeneratedBLSKeys bool
+	testnetBLSThreshold     int
 )
 
 const (
@@ -78,6 +79,8 @@ func init() {
 		"Randomize the moniker for each generated node")
 	TestnetFilesCmd.Flags().Int64Var(&dkgNumBlocks, "dkg-num-blocks", 10, "Number of blocks after which DKG begins")
 	TestnetFilesCmd.Flags().BoolVar(&withoutGeneratedBLSKeys, "without-bls-keys", false, "Testnet without pregenerated BSL keys")
+	TestnetFilesCmd.Flags().IntVar(&testnetBLSThreshold, "bls-threshold", 0,
+		"Number of BLS key shares required to recover the random data (0 means 2/3+1 of the validators)")
 }
 
 // TestnetFilesCmd allows initialisation of files for a Tendermint testnet.
@@ -122,8 +125,22 @@ func testnetFiles(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	config.DKGOnChainConfig.BLSThreshold = nValidators
-	config.DKGOnChainConfig.BLSNumShares = nValidators
+	// Every validator, including the dead ones, gets a share of the BLS key set.
+	var blsKeyring *blsShare.BLSKeyring
+	if !withoutGeneratedBLSKeys {
+		numShares := nValidators + nDeadValidators
+		threshold := testnetBLSThreshold
+		if threshold == 0 {
+			threshold = numShares/3*2 + 1
+		}
+		var err error
+		blsKeyring, err = blsShare.NewBLSKeyring(threshold, numShares)
+		if err != nil {
+			return fmt.Errorf("failed to generate BLS keys: %v", err)
+		}
+		config.DKGOnChainConfig.BLSThreshold = threshold
+		config.DKGOnChainConfig.BLSNumShares = numShares
+	}
 
 	genVals := make([]types.GenesisValidator, nValidators+nDeadValidators)
 
@@ -143,8 +160,13 @@ func testnetFiles(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		config.NodeID = i
-		if err := initFilesWithConfig(config); err != nil {
+		if blsKeyring != nil {
+			if err := saveBLSShare(config.BLSKeyFile(), blsKeyring.Shares[i]); err != nil {
+				_ = os.RemoveAll(outputDir)
+				return err
+			}
+		}
+		if err := initFilesWithConfig(config, false); err != nil {
 			return fmt.Errorf("failed to initFilesWithConfig: %v", err)
 		}
 
@@ -176,7 +198,7 @@ func testnetFiles(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		if err := initFilesWithConfig(config); err != nil {
+		if err := initFilesWithConfig(config, false); err != nil {
 			return fmt.Errorf("failed to initFilesWithConfig: %v", err)
 		}
 	}
@@ -187,11 +209,18 @@ func testnetFiles(cmd *cobra.Command, args []string) error {
 		ChainID:         "chain-" + cmn.RandStr(6),
 		ConsensusParams: types.DefaultConsensusParams(),
 		Validators:      genVals,
-		BLSMasterPubKey: blsShare.TestnetMasterPubKey,
-		BLSThreshold:    3,
-		BLSNumShares:    4,
 		DKGNumBlocks:    dkgNumBlocks,
 	}
+	if blsKeyring != nil {
+		masterPubKey, err := blsShare.DumpMasterPubKey(blsKeyring.MasterPubKey)
+		if err != nil {
+			_ = os.RemoveAll(outputDir)
+			return err
+		}
+		genDoc.BLSMasterPubKey = masterPubKey
+		genDoc.BLSThreshold = blsKeyring.T
+		genDoc.BLSNumShares = blsKeyring.N
+	}
 
 	// Write genesis file.
 	for i := 0; i < nValidators+nNonValidators+nDeadValidators; i++ {
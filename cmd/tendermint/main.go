@@ -32,6 +32,7 @@ func main() {
 		cmd.TestnetFilesCmd,
 		cmd.ShowNodeIDCmd,
 		cmd.GenNodeKeyCmd,
+		cmd.GenBLSKeysCmd,
 		cmd.VersionCmd)
 
 	// NOTE:
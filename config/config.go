@@ -39,6 +39,7 @@ var (
 	defaultPrivValKeyName   = "priv_validator_key.json"
 	defaultPrivValStateName = "priv_validator_state.json"
 	defaultBLSKeyName       = "bls_key.json"
+	defaultDKGVerifierName  = "dkg_verifier_key"
 
 	defaultNodeKeyName  = "node_key.json"
 	defaultAddrBookName = "addrbook.json"
@@ -48,6 +49,7 @@ var (
 	defaultPrivValKeyPath   = filepath.Join(defaultConfigDir, defaultPrivValKeyName)
 	defaultPrivValStatePath = filepath.Join(defaultDataDir, defaultPrivValStateName)
 	defaultBLSKeyPath       = filepath.Join(defaultConfigDir, defaultBLSKeyName)
+	defaultDKGVerifierPath  = filepath.Join(defaultDataDir, defaultDKGVerifierName)
 
 	defaultNodeKeyPath  = filepath.Join(defaultConfigDir, defaultNodeKeyName)
 	defaultAddrBookPath = filepath.Join(defaultConfigDir, defaultAddrBookName)
@@ -92,14 +94,15 @@ func DefaultConfig() *Config {
 // TestConfig returns a configuration that can be used for testing
 func TestConfig() *Config {
 	return &Config{
-		BaseConfig:      TestBaseConfig(),
-		RPC:             TestRPCConfig(),
-		P2P:             TestP2PConfig(),
-		Mempool:         TestMempoolConfig(),
-		FastSync:        TestFastSyncConfig(),
-		Consensus:       TestConsensusConfig(),
-		TxIndex:         TestTxIndexConfig(),
-		Instrumentation: TestInstrumentationConfig(),
+		BaseConfig:       TestBaseConfig(),
+		RPC:              TestRPCConfig(),
+		P2P:              TestP2PConfig(),
+		Mempool:          TestMempoolConfig(),
+		FastSync:         TestFastSyncConfig(),
+		Consensus:        TestConsensusConfig(),
+		TxIndex:          TestTxIndexConfig(),
+		Instrumentation:  TestInstrumentationConfig(),
+		DKGOnChainConfig: DefaultDKGOnChainConfig(),
 	}
 }
 
@@ -203,9 +206,16 @@ type BaseConfig struct { //nolint: maligned
 	// A JSON file containing the private key to use for p2p authenticated encryption
 	NodeKey string `mapstructure:"node_key_file"`
 
-	// A json path to bls key
+	// A json path to bls key. Leave empty if the key share is kept by the
+	// external PrivValidator process (see priv_validator_laddr), which then
+	// signs the random shares
 	BLSKey string `mapstructure:"bls_key_file"`
 
+	// Path to the file the key share produced by the last DKG round is saved to,
+	// encrypted with the private validator key. Not used with an external
+	// PrivValidator process, which is sent the key share and saves it itself
+	DKGVerifier string `mapstructure:"dkg_verifier_file"`
+
 	// Mechanism to connect to the ABCI application: socket | grpc
 	ABCI string `mapstructure:"abci"`
 
@@ -226,6 +236,7 @@ func DefaultBaseConfig() BaseConfig {
 		PrivValidatorKey:   defaultPrivValKeyPath,
 		PrivValidatorState: defaultPrivValStatePath,
 		BLSKey:             defaultBLSKeyPath,
+		DKGVerifier:        defaultDKGVerifierPath,
 		NodeKey:            defaultNodeKeyPath,
 		Moniker:            defaultMoniker,
 		ProxyApp:           "tcp://127.0.0.1:26658",
@@ -285,6 +296,11 @@ func (cfg BaseConfig) BLSKeyFile() string {
 	return rootify(cfg.BLSKey, cfg.RootDir)
 }
 
+// DKGVerifierFile returns the full path to the dkg_verifier_key file
+func (cfg BaseConfig) DKGVerifierFile() string {
+	return rootify(cfg.DKGVerifier, cfg.RootDir)
+}
+
 // DBDir returns the full path to the database directory
 func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
@@ -649,6 +665,7 @@ func DefaultFuzzConnConfig() *FuzzConnConfig {
 // MempoolConfig defines the configuration options for the Tendermint mempool
 type MempoolConfig struct {
 	RootDir     string `mapstructure:"home"`
+	Version     string `mapstructure:"version"`
 	Recheck     bool   `mapstructure:"recheck"`
 	Broadcast   bool   `mapstructure:"broadcast"`
 	WalPath     string `mapstructure:"wal_dir"`
@@ -656,11 +673,22 @@ type MempoolConfig struct {
 	MaxTxsBytes int64  `mapstructure:"max_txs_bytes"`
 	CacheSize   int    `mapstructure:"cache_size"`
 	MaxTxBytes  int    `mapstructure:"max_tx_bytes"`
+
+	// Limits of the txs of a sender, for the txs the app returns a sender
+	// for in CheckTx. No limit if 0.
+	MaxTxsPerSender      int   `mapstructure:"max_txs_per_sender"`
+	MaxTxsBytesPerSender int64 `mapstructure:"max_txs_bytes_per_sender"`
+
+	// Txs are dropped from the mempool after TTLDuration or TTLNumBlocks
+	// blocks. No limit if 0.
+	TTLDuration  time.Duration `mapstructure:"ttl_duration"`
+	TTLNumBlocks int64         `mapstructure:"ttl_num_blocks"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
 func DefaultMempoolConfig() *MempoolConfig {
 	return &MempoolConfig{
+		Version:   "v0",
 		Recheck:   true,
 		Broadcast: true,
 		WalPath:   "",
@@ -693,6 +721,11 @@ func (cfg *MempoolConfig) WalEnabled() bool {
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *MempoolConfig) ValidateBasic() error {
+	switch cfg.Version {
+	case "v0", "v1":
+	default:
+		return fmt.Errorf("unknown mempool version %s", cfg.Version)
+	}
 	if cfg.Size < 0 {
 		return errors.New("size can't be negative")
 	}
@@ -705,6 +738,18 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max_tx_bytes can't be negative")
 	}
+	if cfg.MaxTxsPerSender < 0 {
+		return errors.New("max_txs_per_sender can't be negative")
+	}
+	if cfg.MaxTxsBytesPerSender < 0 {
+		return errors.New("max_txs_bytes_per_sender can't be negative")
+	}
+	if cfg.TTLDuration < 0 {
+		return errors.New("ttl_duration can't be negative")
+	}
+	if cfg.TTLNumBlocks < 0 {
+		return errors.New("ttl_num_blocks can't be negative")
+	}
 	return nil
 }
 
@@ -743,6 +788,14 @@ func (cfg *FastSyncConfig) ValidateBasic() error {
 //-----------------------------------------------------------------------------
 // ConsensusConfig
 
+const (
+	// DKGTriggerInterval starts a new DKG round every dkg_num_blocks blocks.
+	DKGTriggerInterval = "interval"
+	// DKGTriggerValidatorSetChange also starts a new DKG round for the new
+	// validator set whenever the validator set changes.
+	DKGTriggerValidatorSetChange = "validator_set_change"
+)
+
 // ConsensusConfig defines the configuration for the Tendermint consensus service,
 // including timeouts and details about the WAL and the block structure.
 type ConsensusConfig struct {
@@ -773,6 +826,15 @@ type ConsensusConfig struct {
 	InitialDKGRoundTimeout time.Duration `mapstructure:"initial_dkg_round_timeout"`
 	// How much time we should wait until new initial DKG round
 	InitialDKGRoundRetryTimeout time.Duration `mapstructure:"initial_dkg_round_retry_timeout"`
+
+	// What starts a new DKG round, see DKGTriggerInterval and
+	// DKGTriggerValidatorSetChange
+	DKGTrigger string `mapstructure:"dkg_trigger"`
+
+	// Number of DKG messages accepted from a peer per second and the size of
+	// the bursts allowed
+	DKGPeerRateLimit float64 `mapstructure:"dkg_peer_rate_limit"`
+	DKGPeerBurst     int     `mapstructure:"dkg_peer_burst"`
 }
 
 // DefaultConsensusConfig returns a default configuration for the consensus service
@@ -793,6 +855,9 @@ func DefaultConsensusConfig() *ConsensusConfig {
 		PeerQueryMaj23SleepDuration: 2000 * time.Millisecond,
 		InitialDKGRoundTimeout:      5 * time.Second,
 		InitialDKGRoundRetryTimeout: 10 * time.Second,
+		DKGTrigger:                  DKGTriggerInterval,
+		DKGPeerRateLimit:            50,
+		DKGPeerBurst:                1000,
 	}
 }
 
@@ -886,12 +951,23 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.CreateEmptyBlocksInterval < 0 {
 		return errors.New("create_empty_blocks_interval can't be negative")
 	}
+	switch cfg.DKGTrigger {
+	case DKGTriggerInterval, DKGTriggerValidatorSetChange:
+	default:
+		return fmt.Errorf("unknown dkg_trigger %s", cfg.DKGTrigger)
+	}
 	if cfg.PeerGossipSleepDuration < 0 {
 		return errors.New("peer_gossip_sleep_duration can't be negative")
 	}
 	if cfg.PeerQueryMaj23SleepDuration < 0 {
 		return errors.New("peer_query_maj23_sleep_duration can't be negative")
 	}
+	if cfg.DKGPeerRateLimit < 0 {
+		return errors.New("dkg_peer_rate_limit can't be negative")
+	}
+	if cfg.DKGPeerBurst < 0 {
+		return errors.New("dkg_peer_burst can't be negative")
+	}
 	return nil
 }
 
@@ -113,6 +113,10 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"MaxTxsBytes",
 		"CacheSize",
 		"MaxTxBytes",
+		"MaxTxsPerSender",
+		"MaxTxsBytesPerSender",
+		"TTLDuration",
+		"TTLNumBlocks",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -120,6 +124,11 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.Version = "v1"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.Version = "v2"
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestFastSyncConfigValidateBasic(t *testing.T) {
@@ -149,6 +158,7 @@ func TestConsensusConfigValidateBasic(t *testing.T) {
 		"CreateEmptyBlocksInterval",
 		"PeerGossipSleepDuration",
 		"PeerQueryMaj23SleepDuration",
+		"DKGPeerBurst",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -156,6 +166,9 @@ func TestConsensusConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.DKGPeerRateLimit = -1
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestInstrumentationConfigValidateBasic(t *testing.T) {
@@ -129,6 +129,18 @@ priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node_key_file = "{{ js .BaseConfig.NodeKey }}"
 
+# Path to the JSON file containing the BLS key share of the random beacon.
+# Leave empty if the key share is kept by the external PrivValidator process
+# (see priv_validator_laddr), which then signs the random shares
+# (priv_val_server loads it with -bls-key-file)
+bls_key_file = "{{ js .BaseConfig.BLSKey }}"
+
+# Path to the file the key share produced by the last DKG round is saved to,
+# encrypted with the private validator key. Not used with an external
+# PrivValidator process, which is sent the key share of each DKG round and
+# saves it itself (priv_val_server saves it with -dkg-verifier-file)
+dkg_verifier_file = "{{ js .BaseConfig.DKGVerifier }}"
+
 # Mechanism to connect to the ABCI application: socket | grpc
 abci = "{{ .BaseConfig.ABCI }}"
 
@@ -288,8 +300,18 @@ dial_timeout = "{{ .P2P.DialTimeout }}"
 ##### mempool configuration options #####
 [mempool]
 
+# Mempool version to use:
+#   1) "v0" (default) - FIFO mempool, txs are reaped in order of arrival
+#   2) "v1" - priority mempool, txs are reaped in order of the priority returned
+#   by the app in CheckTx and the txs of the lowest priority are evicted when
+#   the mempool is full
+version = "{{ .Mempool.Version }}"
+
 recheck = {{ .Mempool.Recheck }}
 broadcast = {{ .Mempool.Broadcast }}
+
+# Directory of the mempool WAL, disabled if empty. The pending txs recorded in
+# the WAL are checked again when the node starts
 wal_dir = "{{ js .Mempool.WalPath }}"
 
 # Maximum number of transactions in the mempool
@@ -307,6 +329,18 @@ cache_size = {{ .Mempool.CacheSize }}
 # NOTE: the max size of a tx transmitted over the network is {max_tx_bytes} + {amino overhead}.
 max_tx_bytes = {{ .Mempool.MaxTxBytes }}
 
+# Limit the number and the total size of the txs of a single sender, for the
+# txs the app returns a sender for in CheckTx. The txs of a sender are reaped
+# and rechecked in order of the nonce returned by the app. 0 means no limit.
+max_txs_per_sender = {{ .Mempool.MaxTxsPerSender }}
+max_txs_bytes_per_sender = {{ .Mempool.MaxTxsBytesPerSender }}
+
+# Drop the txs which have been in the mempool for longer than ttl_duration or
+# for more than ttl_num_blocks blocks when a block is committed. Clients can
+# subscribe to the ExpiredTx event of their txs. 0 means no limit.
+ttl_duration = "{{ .Mempool.TTLDuration }}"
+ttl_num_blocks = {{ .Mempool.TTLNumBlocks }}
+
 ##### fast sync configuration options #####
 [fastsync]
 
@@ -342,6 +376,19 @@ peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
 initial_dkg_round_timeout = "{{ .Consensus.InitialDKGRoundTimeout }}"
 initial_dkg_round_retry_timeout = "{{ .Consensus.InitialDKGRoundRetryTimeout }}"
 
+# What starts a new DKG round
+# Options:
+#   1) "interval" (default) - a new round every dkg_num_blocks blocks
+#   2) "validator_set_change" - also a new round for the new validator set whenever
+#     the validator set changes. The current BLS key is used until the round is finished.
+dkg_trigger = "{{ .Consensus.DKGTrigger }}"
+
+# Number of DKG messages accepted from a peer per second and the size of the
+# bursts allowed. A DKG round takes a burst of a few messages per validator
+# from every peer, which also relays the messages of the other validators.
+dkg_peer_rate_limit = {{ .Consensus.DKGPeerRateLimit }}
+dkg_peer_burst = {{ .Consensus.DKGPeerBurst }}
+
 ##### transactions indexer configuration options #####
 [tx_index]
 
@@ -0,0 +1,218 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sort"
+
+	"github.com/corestario/dkglib/lib/alias"
+	"github.com/corestario/dkglib/lib/blsShare"
+	dkgDealer "github.com/corestario/dkglib/lib/dealer"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/events"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// DKGDealerWithKeys returns a constructor of the dealers of newDealer whose
+// verifiers are *types.BLSVerifier, i.e. carry the key of their DKG round, so
+// that the key can be committed to the chain, saved and the verifier restored
+// after a restart. The dealers fire types.EventDKGVerifierReady with their
+// verifier once it is ready, and types.EventDKGCorruptData with the messages
+// of the validators whose data is corrupt.
+func DKGDealerWithKeys(newDealer dkgDealer.DKGDealerConstructor) dkgDealer.DKGDealerConstructor {
+	return func(validators *types.ValidatorSet, pv types.PrivValidator, sendMsgCb func([]*alias.DKGData) error,
+		eventFirer events.Fireable, logger log.Logger, startRound int) dkgDealer.Dealer {
+		// the dealers index the key shares by address
+		participants := make([]types.Address, 0, validators.Size())
+		for _, val := range validators.Validators {
+			participants = append(participants, val.Address)
+		}
+		sort.Slice(participants, func(i, j int) bool {
+			return bytes.Compare(participants[i], participants[j]) < 0
+		})
+		return &keyDKGDealer{
+			Dealer:       newDealer(validators, pv, sendMsgCb, eventFirer, logger, startRound),
+			validators:   validators,
+			participants: participants,
+			commits:      make(map[string]*dkg.SecretCommits),
+			eventFirer:   eventFirer,
+			logger:       logger,
+		}
+	}
+}
+
+// keyDKGDealer records the secret commits of the dealers of a DKG round. The
+// master public key of the round is the sum of their polynomials, as every
+// dealer has to be qualified for the round to complete.
+type keyDKGDealer struct {
+	dkgDealer.Dealer
+
+	validators   *types.ValidatorSet
+	participants []types.Address
+	commits      map[string]*dkg.SecretCommits // by address of the dealer
+	verifier     *types.BLSVerifier            // once determined
+	eventFirer   events.Fireable
+	logger       log.Logger
+}
+
+// dkgCorruptData is the data of types.EventDKGCorruptData, the DKG message of
+// the validator with pubKey whose data is corrupt.
+type dkgCorruptData struct {
+	pubKey crypto.PubKey
+	msg    types.DKGMessage
+}
+
+// newDKGMessage returns the DKG message of msg, as signed by its validator.
+func newDKGMessage(msg *alias.DKGData) types.DKGMessage {
+	return types.DKGMessage{
+		Type:        int(msg.Type),
+		Addr:        msg.Addr,
+		RoundID:     msg.RoundID,
+		Data:        msg.Data,
+		ToIndex:     msg.ToIndex,
+		NumEntities: msg.NumEntities,
+		Signature:   msg.Signature,
+	}
+}
+
+// checkData returns an error if the message has more entities than there are
+// validators, so that it is dropped before the dealer allocates an entity for
+// each of them, and fires types.EventDKGCorruptData if the data of the message
+// is corrupt. The dealer fails the round on such a message, so it is reported
+// before the message is handed to the dealer. The signature of the message
+// has already been verified.
+func (d *keyDKGDealer) checkData(msg *alias.DKGData) error {
+	if msg.NumEntities < 0 || msg.NumEntities > d.validators.Size() {
+		return fmt.Errorf("invalid number of entities %d of DKG message from %v (validators: %d)",
+			msg.NumEntities, crypto.Address(msg.Addr), d.validators.Size())
+	}
+	dkgMsg := newDKGMessage(msg)
+	if dkgMsg.DataError() == nil {
+		return nil
+	}
+	_, val := d.validators.GetByAddress(msg.Addr)
+	if val == nil {
+		return nil
+	}
+	d.eventFirer.FireEvent(types.EventDKGCorruptData, dkgCorruptData{pubKey: val.PubKey, msg: dkgMsg})
+	return nil
+}
+
+// HandleDKGPubKey checks the data of the message and hands it to the dealer.
+func (d *keyDKGDealer) HandleDKGPubKey(msg *alias.DKGData) error {
+	if err := d.checkData(msg); err != nil {
+		return err
+	}
+	return d.Dealer.HandleDKGPubKey(msg)
+}
+
+// HandleDKGDeal checks the data of the message and hands it to the dealer.
+func (d *keyDKGDealer) HandleDKGDeal(msg *alias.DKGData) error {
+	if err := d.checkData(msg); err != nil {
+		return err
+	}
+	return d.Dealer.HandleDKGDeal(msg)
+}
+
+// HandleDKGResponse checks the data of the message and hands it to the
+// dealer.
+func (d *keyDKGDealer) HandleDKGResponse(msg *alias.DKGData) error {
+	if err := d.checkData(msg); err != nil {
+		return err
+	}
+	return d.Dealer.HandleDKGResponse(msg)
+}
+
+// HandleDKGCommit records the secret commits of the message once the dealer
+// accepted them.
+func (d *keyDKGDealer) HandleDKGCommit(msg *alias.DKGData) error {
+	if err := d.checkData(msg); err != nil {
+		return err
+	}
+	if err := d.Dealer.HandleDKGCommit(msg); err != nil {
+		return err
+	}
+	suite := bn256.NewSuiteG2()
+	commits := &dkg.SecretCommits{}
+	for i := 0; i < msg.NumEntities; i++ {
+		commits.Commitments = append(commits.Commitments, suite.Point())
+	}
+	if err := gob.NewDecoder(bytes.NewBuffer(msg.Data)).Decode(commits); err != nil {
+		return fmt.Errorf("failed to decode commit: %v", err)
+	}
+	d.commits[msg.GetAddrString()] = commits
+	return nil
+}
+
+// HandleDKGComplaint checks the number of entities of the message and hands
+// it to the dealer.
+func (d *keyDKGDealer) HandleDKGComplaint(msg *alias.DKGData) error {
+	if err := d.checkData(msg); err != nil {
+		return err
+	}
+	return d.Dealer.HandleDKGComplaint(msg)
+}
+
+// GetVerifier returns the verifier of the dealer along with the key of the
+// round. If the key can not be determined, e.g. because some commits had to be
+// reconstructed, it returns the verifier of the dealer as is.
+func (d *keyDKGDealer) GetVerifier() (dkgtypes.Verifier, error) {
+	if d.verifier != nil {
+		return d.verifier, nil
+	}
+	verifier, err := d.Dealer.GetVerifier()
+	if err != nil {
+		return verifier, err
+	}
+	v, ok := verifier.(*blsShare.BLSVerifier)
+	if !ok || v.Keypair == nil {
+		return verifier, nil
+	}
+	masterPubKey, err := d.masterPubKey(v.Keypair)
+	if err != nil {
+		d.logger.Error("Failed to determine the key of the DKG round", "round", d.GetState().GetRoundID(), "err", err)
+		return verifier, nil
+	}
+	// the threshold of the verifiers of dkglib
+	numShares := len(d.participants)
+	key, err := types.NewRandomBeaconKey(masterPubKey, (numShares/3)*2+1, numShares)
+	if err != nil {
+		d.logger.Error("Failed to determine the key of the DKG round", "round", d.GetState().GetRoundID(), "err", err)
+		return verifier, nil
+	}
+	d.verifier = &types.BLSVerifier{BLSVerifier: v, Key: key, Participants: d.participants}
+	d.eventFirer.FireEvent(types.EventDKGVerifierReady, d.verifier)
+	return d.verifier, nil
+}
+
+// masterPubKey returns the sum of the recorded polynomials, after checking
+// that it matches the key share of the validator.
+func (d *keyDKGDealer) masterPubKey(keypair *blsShare.BLSShare) (*share.PubPoly, error) {
+	if len(d.commits) != len(d.participants) {
+		return nil, fmt.Errorf("got the commits of %d dealers, expected %d", len(d.commits), len(d.participants))
+	}
+	suite := bn256.NewSuiteG2()
+	var masterPubKey *share.PubPoly
+	for _, commits := range d.commits {
+		poly := share.NewPubPoly(suite, nil, commits.Commitments)
+		if masterPubKey == nil {
+			masterPubKey = poly
+			continue
+		}
+		var err error
+		if masterPubKey, err = masterPubKey.Add(poly); err != nil {
+			return nil, err
+		}
+	}
+	if !masterPubKey.Eval(keypair.Priv.I).V.Equal(suite.Point().Mul(keypair.Priv.V, nil)) {
+		return nil, fmt.Errorf("master public key does not match the key share")
+	}
+	return masterPubKey, nil
+}
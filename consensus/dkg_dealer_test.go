@@ -0,0 +1,46 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/corestario/dkglib/lib/alias"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// Ensure the DKG messages of the evidence have the sign bytes of the messages
+// of dkglib, so their signatures can be verified.
+func TestDKGMessageSignBytes(t *testing.T) {
+	msg := &alias.DKGData{
+		Type:        alias.DKGCommits,
+		Addr:        []byte("validator_address___"),
+		RoundID:     3,
+		Data:        []byte("data"),
+		ToIndex:     2,
+		NumEntities: 5,
+		Signature:   []byte("signature"),
+	}
+	assert.Equal(t, msg.SignBytes(""), newDKGMessage(msg).SignBytes())
+}
+
+// Ensure the DKG messages with more entities than there are validators are
+// dropped before they are handed to the dealer, which allocates an entity for
+// each of them.
+func TestKeyDKGDealerNumEntities(t *testing.T) {
+	vals, _ := types.RandValidatorSet(4, 10)
+	// the dealer is not called
+	d := &keyDKGDealer{validators: vals}
+	for _, numEntities := range []int{-1, 5, 1 << 40} {
+		msg := &alias.DKGData{
+			Type:        alias.DKGCommits,
+			Addr:        vals.Validators[0].Address,
+			RoundID:     1,
+			Data:        []byte("commits"),
+			NumEntities: numEntities,
+		}
+		assert.Error(t, d.HandleDKGCommit(msg), "%d", numEntities)
+		msg.Type = alias.DKGComplaint
+		assert.Error(t, d.HandleDKGComplaint(msg), "%d", numEntities)
+	}
+}
@@ -0,0 +1,457 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestario/dkglib/lib/alias"
+	"github.com/corestario/dkglib/lib/blsShare"
+	dkgDealer "github.com/corestario/dkglib/lib/dealer"
+	dkgOffChain "github.com/corestario/dkglib/lib/offChain"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	tmevents "github.com/tendermint/tendermint/libs/events"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/privval"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+	dbm "github.com/tendermint/tm-db"
+)
+
+//----------------------------------------------
+// in-process testnets running a real DKG
+
+// dkgMsgFilter is applied to every DKG message a validator receives from the
+// validator that sent it. It returns the message to deliver, or nil to drop it.
+// The copies relayed by the other validators are delivered as they are, since
+// they were filtered when the relaying validator received them.
+type dkgMsgFilter func(from, to int, msg *alias.DKGData) *alias.DKGData
+
+// dkgNet is a testnet of validators that start without a verifier and run the
+// initial DKG round over an in-memory p2p network before making blocks.
+type dkgNet struct {
+	css        []*ConsensusState
+	privVals   []types.PrivValidator
+	reactors   []*ConsensusReactor
+	dkgRs      []*DKGReactor
+	eventBuses []*types.EventBus
+	randomSubs []types.Subscription
+	// the evidence added to the evidence pools of the validators
+	evidence chan types.Evidence
+
+	mtx    sync.Mutex
+	filter dkgMsgFilter
+	// the indexes of the validators by address and by the ID of their node
+	valIndexes  map[string]int
+	peerIndexes map[p2p.ID]int
+
+	cleanup cleanupFunc
+}
+
+// newDKGNet creates a testnet of nValidators validators with the off-chain DKG
+// and no initial verifier.
+func newDKGNet(t *testing.T, nValidators int, testName string, configOpts ...func(*cfg.Config)) *dkgNet {
+	privValDir, err := ioutil.TempDir("", testName)
+	require.NoError(t, err)
+	genDoc, privVals := dkgGenesisDoc(nValidators, privValDir)
+	net := &dkgNet{
+		css:         make([]*ConsensusState, nValidators),
+		privVals:    privVals,
+		valIndexes:  make(map[string]int, nValidators),
+		peerIndexes: make(map[p2p.ID]int, nValidators),
+		evidence:    make(chan types.Evidence, 100),
+	}
+	logger := consensusLogger()
+	configRootDirs := []string{privValDir}
+	for i := 0; i < nValidators; i++ {
+		stateDB := dbm.NewMemDB() // each state needs its own db
+		state, _ := sm.LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
+		thisConfig := ResetConfig(fmt.Sprintf("%s_%d", testName, i))
+		configRootDirs = append(configRootDirs, thisConfig.RootDir)
+		for _, opt := range configOpts {
+			opt(thisConfig)
+		}
+		ensureDir(filepath.Dir(thisConfig.Consensus.WalFile()), 0700) // dir for wal
+		app := newCounter()
+		vals := types.TM2PB.ValidatorUpdates(state.Validators)
+		app.InitChain(abci.RequestInitChain{Validators: vals})
+
+		// The DKG fires its events on the event switch of the consensus.
+		evsw := tmevents.NewEventSwitch()
+		dkg := dkgOffChain.NewOffChainDKG(evsw, genDoc.ChainID,
+			dkgOffChain.WithVerifier((*blsShare.BLSVerifier)(nil)),
+			dkgOffChain.WithLogger(logger.With("validator", i, "module", "dkg")),
+			dkgOffChain.WithPVKey(privVals[i]),
+			dkgOffChain.WithDKGDealerConstructor(DKGDealerWithKeys(dkgDealer.NewDKGDealer)),
+		)
+		// The private validator signs the random shares with the keys of the
+		// DKG rounds, as it does in a node.
+		filePV := privVals[i].(*privval.FilePV)
+		saveVerifier := func(verifier dkgtypes.Verifier, height int64) error {
+			key, err := privval.NewDKGVerifierKey(verifier, height)
+			if err != nil {
+				return err
+			}
+			return filePV.SetDKGVerifierKey(key)
+		}
+		net.css[i] = newConsensusStateWithConfigAndBlockStore(thisConfig, state, privVals[i], app, stateDB,
+			WithEVSW(evsw), WithDKG(dkg), WithVerifierSaver(saveVerifier))
+		net.css[i].evpool = dkgEvidencePool(net.evidence)
+		net.css[i].SetTimeoutTicker(NewTimeoutTicker())
+		net.css[i].SetLogger(logger.With("validator", i, "module", "consensus"))
+		net.valIndexes[string(privVals[i].GetPubKey().Address())] = i
+
+		sub, err := net.css[i].eventBus.Subscribe(context.Background(), testSubscriber, types.EventQueryRandomData,
+			100)
+		require.NoError(t, err)
+		net.randomSubs = append(net.randomSubs, sub)
+	}
+	net.cleanup = func() {
+		for _, dir := range configRootDirs {
+			os.RemoveAll(dir)
+		}
+	}
+	return net
+}
+
+// dkgGenesisDoc returns the genesis doc of a testnet of nValidators validators
+// without a BLS key and their FilePVs, sorted by address. The FilePVs save
+// their sign states to dir.
+func dkgGenesisDoc(nValidators int, dir string) (*types.GenesisDoc, []types.PrivValidator) {
+	validators := make([]types.GenesisValidator, nValidators)
+	privVals := make([]types.PrivValidator, nValidators)
+	for i := 0; i < nValidators; i++ {
+		privVal := privval.GenFilePV("", filepath.Join(dir, fmt.Sprintf("priv_validator_state_%d.json", i)))
+		validators[i] = types.GenesisValidator{
+			PubKey: privVal.GetPubKey(),
+			Power:  30,
+		}
+		privVals[i] = privVal
+	}
+	sort.Sort(types.PrivValidatorsByAddress(privVals))
+
+	return &types.GenesisDoc{
+		GenesisTime: tmtime.Now(),
+		ChainID:     config.ChainID(),
+		Validators:  validators,
+	}, privVals
+}
+
+// dkgEvidencePool records the evidence added to it.
+type dkgEvidencePool chan types.Evidence
+
+func (p dkgEvidencePool) AddEvidence(ev types.Evidence) error {
+	select {
+	case p <- ev:
+	default:
+	}
+	return nil
+}
+
+// setFilter sets the filter applied to the DKG messages from now on.
+func (net *dkgNet) setFilter(filter dkgMsgFilter) {
+	net.mtx.Lock()
+	net.filter = filter
+	net.mtx.Unlock()
+}
+
+// filterDKGMessage applies the filter of the testnet to a DKG message received
+// by validator to from the node src.
+func (net *dkgNet) filterDKGMessage(src p2p.ID, to int, msg *alias.DKGData) *alias.DKGData {
+	net.mtx.Lock()
+	filter := net.filter
+	peerIndex, ok := net.peerIndexes[src]
+	net.mtx.Unlock()
+	if filter == nil || !ok {
+		return msg
+	}
+	from, ok := net.valIndexes[string(msg.Addr)]
+	if !ok || from != peerIndex {
+		return msg
+	}
+	return filter(from, to, msg)
+}
+
+// start connects the validators and starts their consensus states, which run
+// the initial DKG round first.
+func (net *dkgNet) start(t *testing.T) {
+	n := len(net.css)
+	net.reactors = make([]*ConsensusReactor, n)
+	net.dkgRs = make([]*DKGReactor, n)
+	net.eventBuses = make([]*types.EventBus, n)
+	for i := 0; i < n; i++ {
+		net.reactors[i] = NewConsensusReactor(net.css[i], true) // so we dont start the consensus states
+		net.reactors[i].SetLogger(net.css[i].Logger)
+		net.eventBuses[i] = net.css[i].eventBus
+		net.reactors[i].SetEventBus(net.eventBuses[i])
+		net.dkgRs[i] = NewDKGReactor(net.css[i])
+		net.dkgRs[i].SetLogger(net.css[i].Logger)
+		if net.css[i].state.LastBlockHeight == 0 { //simulate handle initChain in handshake
+			sm.SaveState(net.css[i].blockExec.DB(), net.css[i].state)
+		}
+	}
+	switches := p2p.MakeConnectedSwitches(config.P2P, n, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("CONSENSUS", net.reactors[i])
+		s.AddReactor("DKG", &dkgNetReactor{DKGReactor: net.dkgRs[i], net: net, index: i})
+		s.SetLogger(net.css[i].Logger.With("module", "p2p"))
+		return s
+	}, p2p.Connect2Switches)
+	net.mtx.Lock()
+	for i, s := range switches {
+		net.peerIndexes[s.NodeInfo().ID()] = i
+	}
+	net.mtx.Unlock()
+
+	for i := 0; i < n; i++ {
+		s := net.reactors[i].conS.GetState()
+		net.reactors[i].SwitchToConsensus(s, 0)
+	}
+}
+
+// stop stops the testnet and removes its files.
+func (net *dkgNet) stop() {
+	if net.reactors != nil {
+		stopConsensusNet(log.TestingLogger(), net.reactors, net.eventBuses)
+	}
+	net.cleanup()
+}
+
+// waitForRandomData waits until every validator commits the blocks up to
+// height and checks that all of them have random data and agree on it. It
+// returns the random data by height.
+func (net *dkgNet) waitForRandomData(t *testing.T, height int64) map[int64]cmn.HexBytes {
+	randomData := make([]map[int64]cmn.HexBytes, len(net.css))
+	timeoutWaitGroup(t, len(net.css), func(i int) {
+		randomData[i] = make(map[int64]cmn.HexBytes)
+		for int64(len(randomData[i])) < height {
+			msg := <-net.randomSubs[i].Out()
+			event := msg.Data().(types.EventDataRandomData)
+			randomData[i][event.Height] = event.RandomData
+		}
+	}, net.css)
+
+	for h := int64(1); h <= height; h++ {
+		require.NotEmpty(t, randomData[0][h], "no random data at height %d", h)
+		for i := 1; i < len(net.css); i++ {
+			assert.Equal(t, randomData[0][h], randomData[i][h],
+				"validators 0 and %d disagree on the random data at height %d", i, h)
+		}
+	}
+	return randomData[0]
+}
+
+// dkgNetReactor passes the DKG messages received by a validator of a dkgNet
+// through the filter of the testnet.
+type dkgNetReactor struct {
+	*DKGReactor
+	net   *dkgNet
+	index int
+}
+
+func (r *dkgNetReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	if msg, err := decodeMsg(msgBytes); err == nil {
+		if dkgMsg, ok := msg.(*dkgtypes.DKGDataMessage); ok && dkgMsg.Data != nil {
+			data := r.net.filterDKGMessage(src.ID(), r.index, dkgMsg.Data)
+			if data == nil {
+				return
+			}
+			msgBytes = cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{Data: data})
+		}
+	}
+	r.DKGReactor.Receive(chID, src, msgBytes)
+}
+
+// dropDKGRound drops all the DKG messages of the given round sent by the
+// validator from.
+func dropDKGRound(roundID, from int) dkgMsgFilter {
+	return func(msgFrom, to int, msg *alias.DKGData) *alias.DKGData {
+		if msg.RoundID == roundID && msgFrom == from {
+			return nil
+		}
+		return msg
+	}
+}
+
+// corruptDKGRound corrupts the data of all the DKG messages of the given round
+// sent by the validator from. The messages are not signed again, so the
+// validators reject them.
+func corruptDKGRound(roundID, from int) dkgMsgFilter {
+	return func(msgFrom, to int, msg *alias.DKGData) *alias.DKGData {
+		if msg.RoundID != roundID || msgFrom != from {
+			return msg
+		}
+		corrupted := *msg
+		corrupted.Data = append([]byte{}, msg.Data...)
+		for i := range corrupted.Data {
+			corrupted.Data[i] ^= 0xff
+		}
+		return &corrupted
+	}
+}
+
+// byzantineDKGRound replaces the data of all the DKG messages of the given
+// round sent by the validator from with garbage signed by the validator.
+func byzantineDKGRound(roundID, from int, pv types.PrivValidator, chainID string) dkgMsgFilter {
+	return func(msgFrom, to int, msg *alias.DKGData) *alias.DKGData {
+		if msg.RoundID != roundID || msgFrom != from {
+			return msg
+		}
+		garbage := *msg
+		garbage.Data = cmn.RandBytes(len(msg.Data))
+		if err := pv.SignData(chainID, &garbage); err != nil {
+			panic(err)
+		}
+		return &garbage
+	}
+}
+
+//----------------------------------------------
+// tests
+
+func dkgNetConfig(c *cfg.Config) {
+	c.Consensus.InitialDKGRoundTimeout = 5 * time.Second
+	c.Consensus.InitialDKGRoundRetryTimeout = time.Second
+}
+
+// Ensure the validators run the initial DKG round and agree on the random data
+// of every block.
+func TestDKGNetRandomBeacon(t *testing.T) {
+	net := newDKGNet(t, 4, "consensus_dkg_net_test", dkgNetConfig)
+	defer net.stop()
+	net.start(t)
+
+	randomData := net.waitForRandomData(t, 3)
+	assert.NotEqual(t, randomData[1], randomData[2])
+	assert.NotEqual(t, randomData[2], randomData[3])
+
+	// the verifiers carry the key of the round
+	var key types.RandomBeaconKey
+	for i, cs := range net.css {
+		verifier, ok := cs.dkg.Verifier().(*types.BLSVerifier)
+		require.True(t, ok, "validator %d: verifier of type %T", i, cs.dkg.Verifier())
+		if i == 0 {
+			key = verifier.Key
+		}
+		assert.Equal(t, key, verifier.Key, "validator %d", i)
+		assert.Equal(t, 3, verifier.Key.Threshold)
+		assert.Equal(t, 4, verifier.Key.NumShares)
+	}
+	pubKey, err := key.Verifier(nil)
+	require.NoError(t, err)
+	assert.NoError(t, pubKey.VerifyRandomData(randomData[1], randomData[2]))
+
+	// the private validators sign the random shares with the key from the
+	// first height on
+	for i, privVal := range net.privVals {
+		dkgKey, err := privVal.(*privval.FilePV).DKGVerifierKey()
+		require.NoError(t, err)
+		require.NotNil(t, dkgKey, "validator %d", i)
+		assert.Equal(t, key, dkgKey.RandomBeaconKey(), "validator %d", i)
+		assert.EqualValues(t, 1, dkgKey.StartHeight, "validator %d", i)
+	}
+
+	// the first block commits the key, which every validator derives its
+	// random beacon epoch from
+	block := net.css[0].blockStore.LoadBlock(1)
+	require.NotNil(t, block.RandomBeaconKeyChange)
+	assert.Equal(t, key, block.RandomBeaconKeyChange.Key)
+	assert.EqualValues(t, key.Hash(), block.RandomBeaconKeyHash)
+	for i, cs := range net.css {
+		epoch, err := sm.LoadRandomBeaconEpoch(cs.blockExec.DB(), 1)
+		require.NoError(t, err, "validator %d", i)
+		assert.Equal(t, int64(1), epoch.Epoch, "validator %d", i)
+		assert.Equal(t, key, epoch.Key(), "validator %d", i)
+		assert.Len(t, epoch.Participants, 4, "validator %d", i)
+	}
+}
+
+// Ensure the validators retry the initial DKG round if some of its messages are
+// lost, corrupted in transit or forged by a byzantine validator, and that the
+// byzantine validator is reported to the evidence pool.
+func TestDKGNetFaultyRound(t *testing.T) {
+	testCases := []struct {
+		name      string
+		filter    func(net *dkgNet) dkgMsgFilter
+		byzantine int // the validator evidence is expected against, or -1
+	}{
+		{"dropped", func(net *dkgNet) dkgMsgFilter { return dropDKGRound(1, 3) }, -1},
+		{"corrupted", func(net *dkgNet) dkgMsgFilter { return corruptDKGRound(1, 2) }, -1},
+		{"byzantine", func(net *dkgNet) dkgMsgFilter {
+			return byzantineDKGRound(1, 1, net.privVals[1], net.css[1].state.ChainID)
+		}, 1},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			net := newDKGNet(t, 4, fmt.Sprintf("consensus_dkg_net_faulty_test_%d", i), dkgNetConfig)
+			defer net.stop()
+			failedSub, err := net.css[0].eventBus.Subscribe(context.Background(), testSubscriber,
+				types.EventQueryDKGRoundFailed)
+			require.NoError(t, err)
+			net.setFilter(tc.filter(net))
+			net.start(t)
+
+			net.waitForRandomData(t, 2)
+			select {
+			case msg := <-failedSub.Out():
+				assert.Equal(t, 1, msg.Data().(types.EventDataDKGRound).RoundID)
+			default:
+				t.Fatal("expected the first DKG round to fail")
+			}
+
+			if tc.byzantine < 0 {
+				assert.Len(t, net.evidence, 0)
+				return
+			}
+			require.NotEmpty(t, net.evidence)
+			for len(net.evidence) > 0 {
+				ev, ok := (<-net.evidence).(*types.DKGEvidenceCorruptData)
+				require.True(t, ok)
+				pubKey := net.privVals[tc.byzantine].GetPubKey()
+				assert.Nil(t, ev.ValidateBasic())
+				assert.Nil(t, ev.Verify(net.css[0].state.ChainID, pubKey))
+				assert.Equal(t, 1, ev.Message.RoundID)
+			}
+		})
+	}
+}
+
+// Ensure the replay rebuilds the verifier of a height from the random beacon
+// epoch committed to the chain and saved in the state database, and recovers the random data of the
+// stored block from its precommits.
+func TestDKGNetReplayRandomData(t *testing.T) {
+	net := newDKGNet(t, 4, "consensus_dkg_net_replay_test", dkgNetConfig)
+	defer net.stop()
+	cs := net.css[0]
+	chainID, stateDB := cs.state.ChainID, cs.blockExec.DB()
+	net.start(t)
+	net.waitForRandomData(t, 2)
+
+	pb := &playback{cs: cs}
+	vals, err := sm.LoadValidators(stateDB, 1)
+	require.NoError(t, err)
+	commit := cs.blockStore.LoadSeenCommit(1)
+	precommits := types.CommitToVoteSet(chainID, commit, vals)
+
+	status := pb.compareRandomData(1, commit.Round(), precommits)
+	assert.Contains(t, status, "epoch 1,")
+	assert.Contains(t, status, "(match)", status)
+
+	// the precommits of height 1 carry no valid share for height 2
+	status = pb.compareRandomData(2, commit.Round(), precommits)
+	assert.Contains(t, status, "0/3 valid shares, recovered none", status)
+}
@@ -0,0 +1,318 @@
+package consensus
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	dkgAlias "github.com/corestario/dkglib/lib/alias"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+	"github.com/pkg/errors"
+
+	tmevents "github.com/tendermint/tendermint/libs/events"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	DKGChannel = byte(0x24)
+
+	defaultDKGSeenCacheSize = 10000
+
+	dkgPeerRateLimiterKey = "DKGReactor.peerRateLimiter"
+)
+
+// DKGReactor gossips the messages of the off-chain DKG on a channel of its
+// own, with a lower priority than the consensus channels. The messages of this
+// node are broadcasted to its peers, and the messages received from a peer are
+// relayed to the other peers the first time they are seen, so they reach the
+// validators this node is not connected to. The messages received from a peer
+// are rate limited and duplicates are dropped before they are queued for the
+// DKG, so a flood of DKG messages can not starve the consensus. The queue is
+// drained by the DKG routine of the consensus state, which owns the DKG, apart
+// from the consensus messages; when it is full, messages are dropped instead of
+// blocking the peer and are neither seen nor relayed, so that a copy relayed
+// by another peer is accepted later.
+type DKGReactor struct {
+	p2p.BaseReactor
+
+	conS *ConsensusState
+
+	peerRate  float64
+	peerBurst int
+	seen      *dkgMessageCache
+
+	metrics *Metrics
+}
+
+type DKGReactorOption func(*DKGReactor)
+
+// NewDKGReactor returns a new DKGReactor for the DKG of the given
+// consensusState. The messages of a peer are rate limited with the
+// DKGPeerRateLimit and DKGPeerBurst of the consensus config.
+func NewDKGReactor(consensusState *ConsensusState, options ...DKGReactorOption) *DKGReactor {
+	dkgR := &DKGReactor{
+		conS:      consensusState,
+		peerRate:  consensusState.config.DKGPeerRateLimit,
+		peerBurst: consensusState.config.DKGPeerBurst,
+		seen:      newDKGMessageCache(defaultDKGSeenCacheSize),
+		metrics:   NopMetrics(),
+	}
+	dkgR.BaseReactor = *p2p.NewBaseReactor("DKGReactor", dkgR)
+
+	for _, option := range options {
+		option(dkgR)
+	}
+
+	return dkgR
+}
+
+// DKGReactorMetrics sets the metrics.
+func DKGReactorMetrics(metrics *Metrics) DKGReactorOption {
+	return func(dkgR *DKGReactor) { dkgR.metrics = metrics }
+}
+
+// OnStart implements BaseService by subscribing to the DKG messages of this
+// node, which are broadcasted to the peers.
+func (dkgR *DKGReactor) OnStart() error {
+	const subscriber = "dkg-reactor"
+	dkgR.conS.GetEventSwitch().AddListenerForEvent(subscriber, types.EventDKGData,
+		func(data tmevents.EventData) {
+			dkgR.broadcastDKGDataMessage(data.(*dkgAlias.DKGData))
+		})
+	return nil
+}
+
+// OnStop implements BaseService by unsubscribing from the DKG messages.
+func (dkgR *DKGReactor) OnStop() {
+	const subscriber = "dkg-reactor"
+	dkgR.conS.GetEventSwitch().RemoveListener(subscriber)
+}
+
+// GetChannels implements Reactor
+func (dkgR *DKGReactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  DKGChannel,
+			Priority:            1,
+			SendQueueCapacity:   100,
+			RecvMessageCapacity: maxMsgSize,
+		},
+	}
+}
+
+// InitPeer implements Reactor by creating the rate limiter of the peer.
+func (dkgR *DKGReactor) InitPeer(peer p2p.Peer) p2p.Peer {
+	peer.Set(dkgPeerRateLimiterKey, newDKGPeerRateLimiter(dkgR.peerRate, dkgR.peerBurst))
+	return peer
+}
+
+// Receive implements Reactor
+func (dkgR *DKGReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	if !dkgR.IsRunning() {
+		dkgR.Logger.Debug("Receive", "src", src, "chId", chID, "bytes", msgBytes)
+		return
+	}
+
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		dkgR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
+		dkgR.Switch.StopPeerForError(src, err)
+		return
+	}
+	dkgMsg, ok := msg.(*dkgtypes.DKGDataMessage)
+	if !ok {
+		dkgR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+		return
+	}
+	if dkgMsg.Data == nil {
+		err = errors.New("DKG message has no data")
+	} else {
+		err = dkgMsg.ValidateBasic()
+	}
+	if err != nil {
+		dkgR.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		dkgR.Switch.StopPeerForError(src, err)
+		return
+	}
+	dkgR.Logger.Debug("Receive", "src", src, "chId", chID, "msg", msg)
+
+	limiter, ok := src.Get(dkgPeerRateLimiterKey).(*dkgPeerRateLimiter)
+	if !ok {
+		panic(fmt.Sprintf("Peer %v has no DKG rate limiter", src))
+	}
+	if !limiter.Allow(time.Now()) {
+		dkgR.dropMessage("rate_limit", src, dkgMsg)
+		return
+	}
+	// Messages without data are placeholders counted by the DKG, e.g. the nil
+	// justifications, so identical ones are expected. Their copies are counted
+	// by peer instead, and a copy is new if the peer sent more of them than
+	// any other peer.
+	placeholder := len(dkgMsg.Data.Data) == 0
+	if !dkgR.seen.IsNew(msgBytes, src.ID(), placeholder) {
+		dkgR.dropMessage("duplicate", src, dkgMsg)
+		return
+	}
+
+	q := dkgR.conS.GetDKGMsgQueue()
+	if q == nil {
+		return
+	}
+	select {
+	case q <- dkgMsg:
+		// Only a queued message is seen and relayed, so that a copy from
+		// another peer is accepted if this one was dropped.
+		if dkgR.seen.Push(msgBytes, src.ID(), placeholder) {
+			dkgR.relayDKGDataMessage(src, msgBytes)
+		}
+	default:
+		dkgR.dropMessage("queue_full", src, dkgMsg)
+	}
+}
+
+func (dkgR *DKGReactor) dropMessage(reason string, src p2p.Peer, msg *dkgtypes.DKGDataMessage) {
+	dkgR.metrics.DKGMessagesDropped.With("reason", reason).Add(1)
+	dkgR.Logger.Debug("Dropping DKG message", "reason", reason, "peer", src, "msg", msg)
+}
+
+// broadcastDKGDataMessage sends a DKG message of this node to all the peers.
+// The message is seen, so that the copies relayed back to this node are
+// dropped.
+func (dkgR *DKGReactor) broadcastDKGDataMessage(data *dkgAlias.DKGData) {
+	msgBytes := cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{Data: data})
+	dkgR.seen.Push(msgBytes, "", len(data.Data) == 0)
+	dkgR.Switch.Broadcast(DKGChannel, msgBytes)
+}
+
+// relayDKGDataMessage sends a DKG message received from src to all the other
+// peers. It does not block, so a message is not relayed to a peer whose send
+// queue is full.
+func (dkgR *DKGReactor) relayDKGDataMessage(src p2p.Peer, msgBytes []byte) {
+	for _, peer := range dkgR.Switch.Peers().List() {
+		if peer.ID() != src.ID() {
+			peer.TrySend(DKGChannel, msgBytes)
+		}
+	}
+}
+
+// String returns a string representation of the DKGReactor.
+func (dkgR *DKGReactor) String() string {
+	return "DKGReactor"
+}
+
+//-----------------------------------------------------------------------------
+
+// dkgPeerRateLimiter is a token bucket limiting the DKG messages accepted from
+// a peer.
+type dkgPeerRateLimiter struct {
+	mtx    sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newDKGPeerRateLimiter(rate float64, burst int) *dkgPeerRateLimiter {
+	return &dkgPeerRateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token and returns true, or returns false if there is none
+// left at the given time.
+func (l *dkgPeerRateLimiter) Allow(now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// dkgMessageCache remembers the hashes of the last DKG messages received and,
+// for the placeholders, the number of copies received from every peer.
+type dkgMessageCache struct {
+	mtx  sync.Mutex
+	size int
+	map_ map[[sha256.Size]byte]*list.Element
+	list *list.List
+}
+
+type dkgMessageCacheEntry struct {
+	hash   [sha256.Size]byte
+	copies map[p2p.ID]int // the copies of a placeholder received by peer
+	max    int            // the most copies received from a peer
+}
+
+func newDKGMessageCache(cacheSize int) *dkgMessageCache {
+	return &dkgMessageCache{
+		size: cacheSize,
+		map_: make(map[[sha256.Size]byte]*list.Element, cacheSize),
+		list: list.New(),
+	}
+}
+
+// IsNew returns true if the given message is not in the cache or, if it is a
+// placeholder, if the copy received from the peer is one more than the copies
+// received from any peer.
+func (cache *dkgMessageCache) IsNew(msgBytes []byte, peerID p2p.ID, placeholder bool) bool {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	elem, exists := cache.map_[sha256.Sum256(msgBytes)]
+	if !exists {
+		return true
+	}
+	entry := elem.Value.(*dkgMessageCacheEntry)
+	return placeholder && entry.copies[peerID] >= entry.max
+}
+
+// Push adds a copy of the given message received from the peer to the cache
+// and returns true if it was new, as reported by IsNew.
+func (cache *dkgMessageCache) Push(msgBytes []byte, peerID p2p.ID, placeholder bool) bool {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	msgHash := sha256.Sum256(msgBytes)
+	if moved, exists := cache.map_[msgHash]; exists {
+		cache.list.MoveToBack(moved)
+		if !placeholder {
+			return false
+		}
+		entry := moved.Value.(*dkgMessageCacheEntry)
+		entry.copies[peerID]++
+		if entry.copies[peerID] <= entry.max {
+			return false
+		}
+		entry.max = entry.copies[peerID]
+		return true
+	}
+
+	if cache.list.Len() >= cache.size {
+		popped := cache.list.Front()
+		delete(cache.map_, popped.Value.(*dkgMessageCacheEntry).hash)
+		cache.list.Remove(popped)
+	}
+	entry := &dkgMessageCacheEntry{hash: msgHash, max: 1}
+	if placeholder {
+		entry.copies = map[p2p.ID]int{peerID: 1}
+	}
+	cache.map_[msgHash] = cache.list.PushBack(entry)
+	return true
+}
@@ -0,0 +1,205 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestario/dkglib/lib/alias"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/mock"
+)
+
+func TestDKGPeerRateLimiter(t *testing.T) {
+	start := time.Now()
+	limiter := newDKGPeerRateLimiter(2, 3)
+	limiter.last = start
+
+	// the burst is available at once
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(start), "message %d", i)
+	}
+	assert.False(t, limiter.Allow(start))
+
+	// the tokens come back at the given rate
+	assert.True(t, limiter.Allow(start.Add(500*time.Millisecond)))
+	assert.False(t, limiter.Allow(start.Add(500*time.Millisecond)))
+
+	// but never more than the burst
+	later := start.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(later), "message %d", i)
+	}
+	assert.False(t, limiter.Allow(later))
+}
+
+func TestDKGMessageCache(t *testing.T) {
+	cache := newDKGMessageCache(2)
+	assert.True(t, cache.Push([]byte("a"), "p1", false))
+	assert.True(t, cache.Push([]byte("b"), "p1", false))
+	assert.False(t, cache.Push([]byte("a"), "p2", false))
+	assert.False(t, cache.IsNew([]byte("a"), "p1", false))
+	assert.True(t, cache.IsNew([]byte("c"), "p1", false))
+
+	// "b" is the least recently seen message, so it is evicted first
+	assert.True(t, cache.Push([]byte("c"), "p1", false))
+	assert.True(t, cache.Push([]byte("b"), "p1", false))
+	assert.False(t, cache.Push([]byte("c"), "p1", false))
+}
+
+func TestDKGMessageCachePlaceholders(t *testing.T) {
+	cache := newDKGMessageCache(2)
+	// the copies of a placeholder sent by a peer are new
+	assert.True(t, cache.Push([]byte("nil"), "p1", true))
+	assert.True(t, cache.IsNew([]byte("nil"), "p1", true))
+	assert.True(t, cache.Push([]byte("nil"), "p1", true))
+
+	// as long as no peer sent more of them
+	assert.False(t, cache.IsNew([]byte("nil"), "p2", true))
+	assert.False(t, cache.Push([]byte("nil"), "p2", true))
+	assert.False(t, cache.Push([]byte("nil"), "p2", true))
+	assert.True(t, cache.IsNew([]byte("nil"), "p2", true))
+	assert.True(t, cache.Push([]byte("nil"), "p2", true))
+	assert.False(t, cache.IsNew([]byte("nil"), "p1", true))
+}
+
+// Ensure the DKGReactor drops the duplicated DKG messages and the messages
+// over the rate limit of a peer instead of queueing them for the DKG.
+func TestDKGReactorReceive(t *testing.T) {
+	net := newDKGNet(t, 1, "consensus_dkg_reactor_test")
+	defer net.cleanup()
+
+	net.css[0].config.DKGPeerRateLimit = 0
+	net.css[0].config.DKGPeerBurst = 3
+	dkgR := NewDKGReactor(net.css[0])
+	dkgR.SetLogger(net.css[0].Logger)
+	dkgR.SetSwitch(p2p.NewSwitch(config.P2P, nil))
+	require.NoError(t, dkgR.Start())
+	defer dkgR.Stop()
+
+	peer := mock.NewPeer(nil)
+	dkgR.InitPeer(peer)
+	msgBytes := func(roundID int) []byte {
+		return cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{
+			Data: &alias.DKGData{Type: alias.DKGDeal, RoundID: roundID, Data: []byte("deal")},
+		})
+	}
+
+	queue := net.css[0].GetDKGMsgQueue()
+	dkgR.Receive(DKGChannel, peer, msgBytes(1))
+	dkgR.Receive(DKGChannel, peer, msgBytes(1))
+	assert.Len(t, queue, 1, "duplicate message queued")
+
+	dkgR.Receive(DKGChannel, peer, msgBytes(2))
+	dkgR.Receive(DKGChannel, peer, msgBytes(3))
+	dkgR.Receive(DKGChannel, peer, msgBytes(4))
+	assert.Len(t, queue, 2, "message over the rate limit queued")
+
+	// another peer has a rate limit of its own
+	otherPeer := mock.NewPeer(nil)
+	dkgR.InitPeer(otherPeer)
+	dkgR.Receive(DKGChannel, otherPeer, msgBytes(4))
+	assert.Len(t, queue, 3)
+
+	// the DKG counts the messages without data, so they are not deduplicated
+	nilJustification := cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{
+		Data: &alias.DKGData{Type: alias.DKGJustification, RoundID: 1},
+	})
+	dkgR.Receive(DKGChannel, otherPeer, nilJustification)
+	dkgR.Receive(DKGChannel, otherPeer, nilJustification)
+	assert.Len(t, queue, 5)
+
+	// but the copies relayed by another peer are
+	thirdPeer := mock.NewPeer(nil)
+	dkgR.InitPeer(thirdPeer)
+	dkgR.Receive(DKGChannel, thirdPeer, nilJustification)
+	dkgR.Receive(DKGChannel, thirdPeer, nilJustification)
+	assert.Len(t, queue, 5, "relayed placeholder queued")
+}
+
+// Ensure a DKG message dropped because the queue of the DKG is full is not
+// seen, so that the copy sent by another peer is queued.
+func TestDKGReactorReceiveQueueFull(t *testing.T) {
+	net := newDKGNet(t, 1, "consensus_dkg_reactor_test")
+	defer net.cleanup()
+
+	dkgR := NewDKGReactor(net.css[0])
+	dkgR.SetLogger(net.css[0].Logger)
+	dkgR.SetSwitch(p2p.NewSwitch(config.P2P, nil))
+	require.NoError(t, dkgR.Start())
+	defer dkgR.Stop()
+
+	peer, otherPeer := mock.NewPeer(nil), mock.NewPeer(nil)
+	dkgR.InitPeer(peer)
+	dkgR.InitPeer(otherPeer)
+	msgBytes := cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{
+		Data: &alias.DKGData{Type: alias.DKGDeal, RoundID: 1, Data: []byte("deal")},
+	})
+
+	queue := net.css[0].GetDKGMsgQueue()
+	for len(queue) < cap(queue) {
+		queue <- &dkgtypes.DKGDataMessage{}
+	}
+	dkgR.Receive(DKGChannel, peer, msgBytes)
+	assert.Len(t, queue, cap(queue))
+
+	<-queue
+	dkgR.Receive(DKGChannel, otherPeer, msgBytes)
+	assert.Len(t, queue, cap(queue), "message from another peer dropped")
+	dkgR.Receive(DKGChannel, otherPeer, msgBytes)
+	<-queue
+	dkgR.Receive(DKGChannel, peer, msgBytes)
+	assert.Len(t, queue, cap(queue)-1, "duplicate message queued")
+}
+
+// Ensure the DKG messages reach the validators that are not connected to their
+// sender, relayed by the others, and are not relayed back to their sender.
+func TestDKGReactorRelay(t *testing.T) {
+	net := newDKGNet(t, 3, "consensus_dkg_reactor_relay_test")
+	defer net.cleanup()
+
+	dkgRs := make([]*DKGReactor, 3)
+	for i := range dkgRs {
+		dkgRs[i] = NewDKGReactor(net.css[i])
+		dkgRs[i].SetLogger(net.css[i].Logger)
+	}
+	// the validators are connected in a line, 0 - 1 - 2
+	switches := p2p.MakeConnectedSwitches(config.P2P, 3, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("DKG", dkgRs[i])
+		s.SetLogger(net.css[i].Logger.With("module", "p2p"))
+		return s
+	}, func(switches []*p2p.Switch, i, j int) {
+		if j == i+1 {
+			p2p.Connect2Switches(switches, i, j)
+		}
+	})
+	defer func() {
+		for _, s := range switches {
+			s.Stop()
+		}
+	}()
+
+	dkgRs[0].broadcastDKGDataMessage(&alias.DKGData{Type: alias.DKGDeal, RoundID: 1, Data: []byte("deal")})
+	nilJustification := &alias.DKGData{Type: alias.DKGJustification, RoundID: 1}
+	dkgRs[0].broadcastDKGDataMessage(nilJustification)
+	dkgRs[0].broadcastDKGDataMessage(nilJustification)
+
+	for _, i := range []int{1, 2} {
+		queue := net.css[i].GetDKGMsgQueue()
+		for j := 0; j < 3; j++ {
+			select {
+			case <-queue:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("validator %d: expected 3 DKG messages, got %d", i, j)
+			}
+		}
+	}
+	// give the echoes time to arrive
+	time.Sleep(100 * time.Millisecond)
+	for i, cs := range net.css {
+		assert.Len(t, cs.GetDKGMsgQueue(), 0, "validator %d", i)
+	}
+}
@@ -51,6 +51,28 @@ type Metrics struct {
 
 	// Number of blockparts transmitted by peer.
 	BlockParts metrics.Counter
+
+	// Number of heights at which the random data could not be recovered from the first +2/3 precommits.
+	RandomDataRecoveryFailures metrics.Counter
+	// Time between +2/3 precommits and the recovery of the random data.
+	RandomDataRecoverySeconds metrics.Gauge
+	// Number of valid random shares received at the last height.
+	RandomShares metrics.Gauge
+	// Number of invalid random shares received at the last height.
+	InvalidRandomShares metrics.Gauge
+	// Current key epoch of the random beacon.
+	RandomBeaconEpoch metrics.Gauge
+
+	// Number of started DKG rounds.
+	DKGRounds metrics.Counter
+	// Histogram of the durations of the completed DKG rounds.
+	DKGRoundDurationSeconds metrics.Histogram
+	// Number of failed DKG rounds.
+	DKGRoundFailures metrics.Counter
+	// Number of timeouts of the initial DKG round, each followed by a retry.
+	DKGRoundTimeouts metrics.Counter
+	// Number of DKG messages received from peers and dropped by reason.
+	DKGMessagesDropped metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -155,6 +177,69 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "block_parts",
 			Help:      "Number of blockparts transmitted by peer.",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
+
+		RandomDataRecoveryFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "random_data_recovery_failures",
+			Help:      "Number of heights at which the random data could not be recovered from the first +2/3 precommits.",
+		}, labels).With(labelsAndValues...),
+		RandomDataRecoverySeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "random_data_recovery_seconds",
+			Help:      "Time between +2/3 precommits and the recovery of the random data.",
+		}, labels).With(labelsAndValues...),
+		RandomShares: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "random_shares",
+			Help:      "Number of valid random shares received at the last height.",
+		}, labels).With(labelsAndValues...),
+		InvalidRandomShares: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "invalid_random_shares",
+			Help:      "Number of invalid random shares received at the last height.",
+		}, labels).With(labelsAndValues...),
+		RandomBeaconEpoch: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "random_beacon_epoch",
+			Help:      "Current key epoch of the random beacon.",
+		}, labels).With(labelsAndValues...),
+
+		DKGRounds: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dkg_rounds",
+			Help:      "Number of started DKG rounds.",
+		}, labels).With(labelsAndValues...),
+		DKGRoundDurationSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dkg_round_duration_seconds",
+			Help:      "Durations of the completed DKG rounds in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 10),
+		}, labels).With(labelsAndValues...),
+		DKGRoundFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dkg_round_failures",
+			Help:      "Number of failed DKG rounds.",
+		}, labels).With(labelsAndValues...),
+		DKGRoundTimeouts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dkg_round_timeouts",
+			Help:      "Number of timeouts of the initial DKG round, each followed by a retry.",
+		}, labels).With(labelsAndValues...),
+		DKGMessagesDropped: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dkg_messages_dropped",
+			Help:      "Number of DKG messages received from peers and dropped by reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
 	}
 }
 
@@ -180,5 +265,17 @@ func NopMetrics() *Metrics {
 		CommittedHeight: discard.NewGauge(),
 		FastSyncing:     discard.NewGauge(),
 		BlockParts:      discard.NewCounter(),
+
+		RandomDataRecoveryFailures: discard.NewCounter(),
+		RandomDataRecoverySeconds:  discard.NewGauge(),
+		RandomShares:               discard.NewGauge(),
+		InvalidRandomShares:        discard.NewGauge(),
+		RandomBeaconEpoch:          discard.NewGauge(),
+
+		DKGRounds:               discard.NewCounter(),
+		DKGRoundDurationSeconds: discard.NewHistogram(),
+		DKGRoundFailures:        discard.NewCounter(),
+		DKGRoundTimeouts:        discard.NewCounter(),
+		DKGMessagesDropped:      discard.NewCounter(),
 	}
 }
@@ -279,11 +279,6 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 				BlockID: msg.BlockID,
 				Votes:   ourVotes,
 			}))
-		case *dkgtypes.DKGDataMessage:
-			q := conR.conS.GetDKGMsgQueue()
-			if q != nil {
-				q <- msg
-			}
 		default:
 			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 		}
@@ -399,13 +394,6 @@ func (conR *ConsensusReactor) subscribeToBroadcastEvents() {
 		func(data tmevents.EventData) {
 			conR.broadcastHasVoteMessage(data.(*types.Vote))
 		})
-	conR.conS.GetEventSwitch().AddListenerForEvent(subscriber, types.EventDKGData,
-		func(data tmevents.EventData) {
-			if conR.conS.dkg != nil {
-				conR.broadcastDKGDataMessage(data.(*dkgAlias.DKGData))
-			}
-		})
-
 }
 
 func (conR *ConsensusReactor) unsubscribeFromBroadcastEvents() {
@@ -418,13 +406,6 @@ func (conR *ConsensusReactor) broadcastNewRoundStepMessage(rs *cstypes.RoundStat
 	conR.Switch.Broadcast(StateChannel, cdc.MustMarshalBinaryBare(nrsMsg))
 }
 
-// Broadcasts HasVoteMessage to peers that care.
-func (conR *ConsensusReactor) broadcastDKGDataMessage(data *dkgAlias.DKGData) {
-	if conR.conS.dkg != nil {
-		conR.Switch.Broadcast(StateChannel, cdc.MustMarshalBinaryBare(&dkgtypes.DKGDataMessage{Data: data}))
-	}
-}
-
 func (conR *ConsensusReactor) broadcastNewValidBlockMessage(rs *cstypes.RoundState) {
 	csMsg := &NewValidBlockMessage{
 		Height:           rs.Height,
@@ -722,7 +703,7 @@ func (conR *ConsensusReactor) gossipVotesForHeight(
 	}
 	// If there are precommits to send...
 	if prs.Step <= cstypes.RoundStepPrecommitWait && prs.Round != -1 && prs.Round <= rs.Round {
-		if ps.PickSendVote(rs.Votes.Precommits(prs.Round)) {
+		if ps.PickSendPrecommit(rs.Votes.Precommits(prs.Round), rs.Seed) {
 			logger.Debug("Picked rs.Precommits(prs.Round) to send", "round", prs.Round)
 			return true
 		}
@@ -1047,8 +1028,14 @@ func (ps *PeerState) SetHasProposalBlockPart(height int64, round int, index int)
 // PickSendVote picks a vote and sends it to the peer.
 // Returns true if vote was sent.
 func (ps *PeerState) PickSendVote(votes types.VoteSetReader) bool {
+	return ps.PickSendPrecommit(votes, nil)
+}
+
+// PickSendPrecommit is PickSendVote for the precommits of the current height,
+// which are sent with the seed their random shares are signed with.
+func (ps *PeerState) PickSendPrecommit(votes types.VoteSetReader, randomSeed []byte) bool {
 	if vote, ok := ps.PickVoteToSend(votes); ok {
-		msg := &VoteMessage{vote}
+		msg := &VoteMessage{Vote: vote, RandomSeed: randomSeed}
 		ps.logger.Debug("Sending vote message", "ps", ps, "vote", vote)
 		if ps.peer.Send(VoteChannel, cdc.MustMarshalBinaryBare(msg)) {
 			ps.SetHasVote(vote)
@@ -1590,6 +1577,10 @@ func (m *BlockPartMessage) String() string {
 // VoteMessage is sent when voting for a proposal (or lack thereof).
 type VoteMessage struct {
 	Vote *types.Vote
+	// RandomSeed is the seed the random share of a precommit of the current
+	// height of the sender is signed with. It is nil for the other votes,
+	// whose shares are signed with the seed of the state at their height.
+	RandomSeed []byte
 }
 
 // ValidateBasic performs basic validation.
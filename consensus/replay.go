@@ -444,6 +444,9 @@ func (h *Handshaker) replayBlocks(
 		if len(appHash) > 0 {
 			assertAppHashEqualsOneFromBlock(appHash, block)
 		}
+		if err := h.verifyRandomData(block); err != nil {
+			return nil, fmt.Errorf("invalid random data of block %d: %v", i, err)
+		}
 
 		appHash, err = sm.ExecCommitBlock(proxyApp.Consensus(), block, h.logger, h.stateDB)
 		if err != nil {
@@ -466,10 +469,49 @@ func (h *Handshaker) replayBlocks(
 	return appHash, nil
 }
 
+// verifyRandomData checks the random data of the stored block against the
+// random beacon epoch recorded for its height, if there is one.
+func (h *Handshaker) verifyRandomData(block *types.Block) error {
+	if _, err := sm.LoadRandomBeaconEpoch(h.stateDB, block.Height); err != nil {
+		if _, ok := err.(sm.ErrNoRandomBeaconEpochForHeight); ok {
+			return nil
+		}
+		return err
+	}
+
+	prevRandomData, seed, err := loadRandomMessageInputs(h.stateDB, h.store, block.Height)
+	if err != nil {
+		return err
+	}
+	return sm.VerifyRandomData(h.stateDB, block.Height, prevRandomData, seed, block.RandomData)
+}
+
+// loadRandomMessageInputs returns the random data of the stored block before
+// the given height and the seed the application returned for it, from which
+// the random message of the height is built.
+func loadRandomMessageInputs(stateDB dbm.DB, store sm.BlockStore, height int64) (prevRandomData, seed []byte,
+	err error) {
+	if height == 1 {
+		return []byte(types.InitialRandomData), nil, nil
+	}
+	meta := store.LoadBlockMeta(height - 1)
+	if meta == nil {
+		return nil, nil, fmt.Errorf("no stored block at height %d", height-1)
+	}
+	abciResponses, err := sm.LoadABCIResponses(stateDB, height-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return meta.Header.RandomData, abciResponses.EndBlock.GetSeed(), nil
+}
+
 // ApplyBlock on the proxyApp with the last block.
 func (h *Handshaker) replayBlock(state sm.State, height int64, proxyApp proxy.AppConnConsensus) (sm.State, error) {
 	block := h.store.LoadBlock(height)
 	meta := h.store.LoadBlockMeta(height)
+	if err := h.verifyRandomData(block); err != nil {
+		return sm.State{}, fmt.Errorf("invalid random data of block %d: %v", height, err)
+	}
 
 	blockExec := sm.NewBlockExecutor(h.stateDB, h.logger, proxyApp, mock.Mempool{}, sm.MockEvidencePool{})
 	blockExec.SetEventBus(h.eventBus)
@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -9,8 +10,12 @@ import (
 	"strconv"
 	"strings"
 
+	dkgtypes "github.com/corestario/dkglib/lib/types"
 	"github.com/pkg/errors"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+
 	cfg "github.com/tendermint/tendermint/config"
+	cstypes "github.com/tendermint/tendermint/consensus/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/mock"
@@ -85,6 +90,9 @@ func (cs *ConsensusState) ReplayFile(file string, console bool) error {
 		if err := pb.cs.readReplayMessage(msg, newStepSub); err != nil {
 			return err
 		}
+		if _, ok := msg.Msg.(types.EventDataRoundState); ok {
+			fmt.Println(pb.randomDataStatus())
+		}
 
 		if nextN > 0 {
 			nextN--
@@ -106,6 +114,10 @@ type playback struct {
 	// replays can be reset to beginning
 	fileName     string   // so we can close/reopen the file
 	genesisState sm.State // so the replay session knows where to restart from
+
+	// the random beacon epoch of the last height replayed and its verifier
+	randomEpoch    *sm.RandomBeaconEpoch
+	randomVerifier dkgtypes.Verifier
 }
 
 func newPlayback(fileName string, fp *os.File, cs *ConsensusState, genState sm.State) *playback {
@@ -267,12 +279,160 @@ func (pb *playback) replayConsoleLoop() int {
 					fmt.Println("Unknown option", tokens[1])
 				}
 			}
+		case "random":
+			// "random" -> print the random data recovered from the precommits
+			// and the random data of the stored block at the current height
+
+			fmt.Println(pb.randomDataStatus())
+
+		case "shares":
+			// "shares" -> print the random share of every validator in the
+			// precommits of the current round
+			// "shares N" -> print the random shares of round N
+
+			round := pb.cs.RoundState.Round
+			if len(tokens) > 1 {
+				i, err := strconv.Atoi(tokens[1])
+				if err != nil {
+					fmt.Println("shares takes an integer argument")
+					continue
+				}
+				round = i
+			}
+			pb.printRandomShares(round)
+
 		case "n":
 			fmt.Println(pb.count)
 		}
 	}
 }
 
+//--------------------------------------------------------------------------------
+// random beacon
+
+// loadRandomVerifier returns the random beacon epoch of the given height and a
+// verifier for it, rebuilt from the epoch saved in the state database, so the
+// random shares can be checked without the DKG of a running node.
+func (pb *playback) loadRandomVerifier(height int64) (*sm.RandomBeaconEpoch, dkgtypes.Verifier, error) {
+	epoch, err := sm.LoadRandomBeaconEpoch(pb.cs.blockExec.DB(), height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pb.randomEpoch == nil || pb.randomEpoch.Epoch != epoch.Epoch {
+		verifier, err := epoch.Verifier()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid master public key of random beacon epoch %d: %v", epoch.Epoch, err)
+		}
+		pb.randomEpoch, pb.randomVerifier = epoch, verifier
+	}
+	return pb.randomEpoch, pb.randomVerifier, nil
+}
+
+// loadRandomMessage returns the random message of the given height.
+func (pb *playback) loadRandomMessage(height int64) ([]byte, error) {
+	prevRandomData, seed, err := loadRandomMessageInputs(pb.cs.blockExec.DB(), pb.cs.blockStore, height)
+	if err != nil {
+		return nil, err
+	}
+	return types.MakeRandomMessage(prevRandomData, seed), nil
+}
+
+// randomDataStatus compares the random data recovered from the random shares
+// of the precommits replayed so far with the random data in the header of the
+// stored block at the current height.
+func (pb *playback) randomDataStatus() string {
+	rs := pb.cs.RoundState
+	round := rs.Round
+	if rs.Step == cstypes.RoundStepCommit {
+		round = rs.CommitRound
+	}
+	return pb.compareRandomData(rs.Height, round, rs.Votes.Precommits(round))
+}
+
+// compareRandomData compares the random data recovered from the random shares
+// of the given precommits with the random data of the stored block at height.
+func (pb *playback) compareRandomData(height int64, round int, precommits *types.VoteSet) string {
+	prefix := fmt.Sprintf("Random data %v/%v:", height, round)
+
+	epoch, verifier, err := pb.loadRandomVerifier(height)
+	if err != nil {
+		return fmt.Sprintf("%s %v", prefix, err)
+	}
+	msg, err := pb.loadRandomMessage(height)
+	if err != nil {
+		return fmt.Sprintf("%s %v", prefix, err)
+	}
+
+	// The shares are those for the stored block or, if there is none yet, for
+	// the block +2/3 precommitted.
+	header := "none"
+	blockID, _ := precommits.TwoThirdsMajority()
+	meta := pb.cs.blockStore.LoadBlockMeta(height)
+	if meta != nil {
+		header = fmt.Sprintf("%X", meta.Header.RandomData)
+		blockID = meta.BlockID
+	}
+	recovered := "none"
+	shares := validRandomShares(verifier, msg, precommits, blockID)
+	randomData, err := verifier.Recover(msg, shares)
+	if err == nil {
+		recovered = fmt.Sprintf("%X", randomData)
+	}
+
+	var match string
+	switch {
+	case randomData == nil || meta == nil:
+	case bytes.Equal(randomData, meta.Header.RandomData):
+		match = " (match)"
+	default:
+		match = " (MISMATCH)"
+	}
+	return fmt.Sprintf("%s epoch %d, %d/%d valid shares, recovered %s, header %s%s",
+		prefix, epoch.Epoch, len(shares), epoch.Threshold, recovered, header, match)
+}
+
+// printRandomShares prints the random share of every validator in the
+// precommits of the given round at the current height.
+func (pb *playback) printRandomShares(round int) {
+	rs := pb.cs.RoundState
+	_, verifier, err := pb.loadRandomVerifier(rs.Height)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	msg, err := pb.loadRandomMessage(rs.Height)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	precommits := rs.Votes.Precommits(round)
+	if precommits == nil {
+		fmt.Printf("No precommits for round %d\n", round)
+		return
+	}
+	for i := 0; i < precommits.Size(); i++ {
+		addr, _ := rs.Validators.GetByIndex(i)
+		vote := precommits.GetByIndex(i)
+		switch {
+		case vote == nil:
+			fmt.Printf("%d %X: no precommit\n", i, addr)
+		case len(vote.BlockID.Hash) == 0:
+			fmt.Printf("%d %X: precommit for nil\n", i, addr)
+		default:
+			status := "valid"
+			if err := verifier.VerifyRandomShare(vote.ValidatorAddress.String(), msg, vote.BLSSignature); err != nil {
+				status = fmt.Sprintf("invalid: %v", err)
+			}
+			index, err := tbls.SigShare(vote.BLSSignature).Index()
+			if err != nil {
+				index = -1
+			}
+			fmt.Printf("%d %X: share %d %X, %s\n", i, addr, index, cmn.Fingerprint(vote.BLSSignature), status)
+		}
+	}
+}
+
 //--------------------------------------------------------------------------------
 
 // convenience for replay mode
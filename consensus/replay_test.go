@@ -811,6 +811,11 @@ func TestHandshakePanicsIfAppReturnsWrongAppHash(t *testing.T) {
 	const appVersion = 0x0
 	stateDB, state, store := stateAndStore(config, privVal.GetPubKey(), appVersion)
 	genDoc, _ := sm.MakeGenesisDocFromFile(config.GenesisFile())
+	// The blocks carry no random data, so the chain starts without a random
+	// beacon key, which leaves their random data unverified.
+	state.RandomBeaconEpoch = sm.RandomBeaconEpoch{}
+	stateDB = dbm.NewMemDB()
+	sm.SaveState(stateDB, state)
 	state.LastValidators = state.Validators.Copy()
 	// mode = 0 for committing all the blocks
 	blocks := makeBlocks(3, &state, privVal)
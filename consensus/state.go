@@ -8,11 +8,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	dkgtypes "github.com/corestario/dkglib/lib/types"
 	"github.com/pkg/errors"
 	cfg "github.com/tendermint/tendermint/config"
 	cstypes "github.com/tendermint/tendermint/consensus/types"
 	types2 "github.com/tendermint/tendermint/consensus/types"
+	"github.com/tendermint/tendermint/crypto"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/events"
 	tmevents "github.com/tendermint/tendermint/libs/events"
@@ -23,6 +25,7 @@ import (
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
 //-----------------------------------------------------------------------------
@@ -41,6 +44,10 @@ var (
 	msgQueueSize = 1000
 )
 
+// dkgEventsID is the event switch listener ID used to publish the DKG round
+// events on the event bus.
+const dkgEventsID = "consensus-dkg-events"
+
 // msgs from the reactor which may update the state
 type msgInfo struct {
 	Msg    ConsensusMessage `json:"msg"`
@@ -139,10 +146,46 @@ type ConsensusState struct {
 	metrics *Metrics
 
 	dkg dkgtypes.DKG
-
-	// blsSeed can be set if it is present in an EndBlock response. It should be set to
-	// nil after use.
-	blsSeed []byte
+	// persists the verifier produced by a DKG round, may be nil
+	saveVerifier func(verifier dkgtypes.Verifier, height int64) error
+
+	// random data of the block being committed, recovered from the random
+	// shares of the precommits; nil until enough valid shares are received
+	randomData []byte
+	// the precommits randomData was recovered from
+	randomDataSigners *cmn.BitArray
+	// when the recovery of randomData was first attempted and the number of
+	// valid and invalid random shares received at the current height, used
+	// for metrics
+	randomDataRecoveryStart time.Time
+	validRandomShares       int
+	invalidRandomShares     int
+	// whether the recovery of randomData failed at the current height, so
+	// that the failure is only reported once per height
+	randomDataRecoveryFailed bool
+	// the first valid random share of every key share at the current height,
+	// by share index, to detect conflicting random shares
+	randomShares map[int]randomShare
+
+	// dkgMtx serializes the calls into the DKG, which is driven by both the
+	// receive routine and the DKG routine, and protects the fields below,
+	// which are also updated by the listeners of the DKG events.
+	dkgMtx sync.Mutex
+	// the height the DKG is at, which is the height of the consensus or, on
+	// the DKG routine, the height at which the DKG message was received
+	dkgHeight int64
+	// the last DKG round started, when it started and whether it is still
+	// running, used for the DKG round events and metrics
+	dkgRoundID        int
+	dkgRoundStartTime time.Time
+	dkgRoundActive    bool
+	// receives a value when the DKG routine swapped in the first verifier
+	dkgVerifierReady chan struct{}
+	// the verifiers of the DKG rounds of this node whose keys are not
+	// committed yet, in the order the rounds completed
+	dkgVerifiers []*types.BLSVerifier
+	// the random beacon epoch of the verifier of the DKG
+	dkgEpoch int64
 }
 
 // StateOption sets an optional parameter on the ConsensusState.
@@ -173,6 +216,7 @@ func NewConsensusState(
 		evpool:           evpool,
 		evsw:             tmevents.NewEventSwitch(),
 		metrics:          NopMetrics(),
+		dkgVerifierReady: make(chan struct{}, 1),
 	}
 	cs.BaseService = *cmn.NewBaseService(nil, "ConsensusState", cs)
 
@@ -183,6 +227,32 @@ func NewConsensusState(
 	for _, option := range options {
 		option(cs)
 	}
+	if cs.dkg != nil {
+		// The rounds are tracked from the start, so that updateToState knows
+		// about the rounds the DKG starts before SwitchToConsensus.
+		cs.evsw.AddListenerForEvent(dkgEventsID, types.EventDKGStart,
+			func(data tmevents.EventData) {
+				roundID, _ := data.(int)
+				cs.dkgRoundStarted(roundID)
+			})
+		cs.evsw.AddListenerForEvent(dkgEventsID, types.EventDKGSuccessful,
+			func(data tmevents.EventData) {
+				startHeight, _ := data.(int64)
+				cs.dkgRoundCompleted(startHeight)
+			})
+		cs.evsw.AddListenerForEvent(dkgEventsID, types.EventDKGVerifierReady,
+			func(data tmevents.EventData) {
+				if verifier, ok := data.(*types.BLSVerifier); ok {
+					cs.dkgVerifiers = append(cs.dkgVerifiers, verifier)
+				}
+			})
+		cs.evsw.AddListenerForEvent(dkgEventsID, types.EventDKGCorruptData,
+			func(data tmevents.EventData) {
+				if corrupt, ok := data.(dkgCorruptData); ok {
+					cs.addDKGEvidence(corrupt.pubKey, corrupt.msg)
+				}
+			})
+	}
 
 	cs.updateToState(state)
 
@@ -198,7 +268,9 @@ func NewConsensusState(
 
 func (cs *ConsensusState) SetVerifier(verifier dkgtypes.Verifier) {
 	if cs.dkg != nil {
+		cs.dkgMtx.Lock()
 		cs.dkg.SetVerifier(verifier)
+		cs.dkgMtx.Unlock()
 	}
 	return
 }
@@ -349,7 +421,10 @@ go run scripts/json2wal/main.go wal.json $WALFILE # rebuild the file without cor
 		}
 	}
 
-	// now start the receiveRoutine
+	// now start the receiveRoutine, and the dkgRoutine
+	if cs.dkg != nil {
+		go cs.dkgRoutine()
+	}
 	go cs.receiveRoutine(0)
 
 	// schedule the first round!
@@ -367,6 +442,9 @@ func (cs *ConsensusState) startRoutines(maxSteps int) {
 		cs.Logger.Error("Error starting timeout ticker", "err", err)
 		return
 	}
+	if cs.dkg != nil {
+		go cs.dkgRoutine()
+	}
 	go cs.receiveRoutine(maxSteps)
 }
 
@@ -408,9 +486,9 @@ func (cs *ConsensusState) OpenWAL(walFile string) (WAL, error) {
 // AddVote inputs a vote.
 func (cs *ConsensusState) AddVote(vote *types.Vote, peerID p2p.ID) (added bool, err error) {
 	if peerID == "" {
-		cs.internalMsgQueue <- msgInfo{&VoteMessage{vote}, ""}
+		cs.internalMsgQueue <- msgInfo{&VoteMessage{Vote: vote}, ""}
 	} else {
-		cs.peerMsgQueue <- msgInfo{&VoteMessage{vote}, peerID}
+		cs.peerMsgQueue <- msgInfo{&VoteMessage{Vote: vote}, peerID}
 	}
 
 	// TODO: wait for event?!
@@ -467,6 +545,10 @@ func (cs *ConsensusState) SetProposalAndBlock(
 func (cs *ConsensusState) updateHeight(height int64) {
 	cs.metrics.Height.Set(float64(height))
 	cs.Height = height
+
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	cs.dkgHeight = height
 	if cs.dkg != nil {
 		cs.dkg.CheckDKGTime(cs.Height, cs.Validators)
 	}
@@ -570,6 +652,7 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 
 	// RoundState fields
 	cs.updateHeight(height)
+	cs.syncDKGVerifier(state)
 	cs.updateRoundStep(0, cstypes.RoundStepNewHeight)
 	if cs.CommitTime.IsZero() {
 		// "Now" makes it easier to sync up dev nodes.
@@ -597,6 +680,23 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 	cs.LastCommit = lastPrecommits
 	cs.LastValidators = state.LastValidators
 	cs.TriggeredTimeoutPrecommit = false
+	cs.randomData = nil
+	cs.randomDataSigners = nil
+	cs.randomDataRecoveryStart = time.Time{}
+	cs.randomDataRecoveryFailed = false
+	cs.validRandomShares = 0
+	cs.invalidRandomShares = 0
+	cs.Seed = state.Seed
+	cs.randomShares = make(map[int]randomShare)
+
+	// The validator set changes at the new height, so the BLS shares of the
+	// current verifier may belong to validators that left the set. A round
+	// already running, e.g. started by CheckDKGTime at this height, is not
+	// superseded.
+	if cs.dkg != nil && cs.config.DKGTrigger == cfg.DKGTriggerValidatorSetChange &&
+		!cs.state.IsEmpty() && state.LastHeightValidatorsChanged == height {
+		cs.startDKGRound(height, validators)
+	}
 
 	cs.state = state
 
@@ -651,18 +751,16 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 		}
 	}()
 
-	var dkgMsgQ chan *dkgtypes.DKGDataMessage
-	if cs.dkg != nil {
-		dkgMsgQ = cs.dkg.MsgQueue()
-	}
-
-	for cs.dkg.Verifier().IsNil() {
+	for cs.dkgVerifierIsNil() {
 
 		if cs.Height < 1 {
 			cs.Logger.Error("nil verifier was found, but there's blocks in the chain")
 			panic("nil verifier was found, but there's blocks in the chain")
 		}
-		if err := cs.dkg.StartDKGRound(cs.Validators); err != nil {
+		cs.dkgMtx.Lock()
+		err := cs.dkg.StartDKGRound(cs.Validators)
+		cs.dkgMtx.Unlock()
+		if err != nil {
 			cs.Logger.Error("failed to start DKG round at chain initialization")
 			panic("failed to start DKG round at chain initialization")
 		}
@@ -674,28 +772,32 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 				<-retryTimeout.C
 			}
 
+			// The DKG messages are handled by the dkgRoutine, which swaps in
+			// the first verifier as soon as it is ready.
 			timeout := time.NewTimer(cs.config.InitialDKGRoundTimeout)
 			for {
 				select {
-				case msg, ok := <-dkgMsgQ:
-					if ok {
-						cs.dkg.HandleOffChainShare(msg, cs.Height, cs.Validators, cs.privValidator.GetPubKey())
-						cs.dkg.CheckDKGTime(-1, cs.Validators)
-						// This means that we got a verifier to work with, we can run the blockchain now.
-						if !cs.dkg.Verifier().IsNil() {
-							cs.Logger.Info("successfully run initial DKG round, verifier ready")
-							return
-						}
-						cs.Logger.Info("handled off-chain dkg message, verifier is not ready yet")
+				case <-cs.dkgVerifierReady:
+					// This means that we got a verifier to work with, we can run the blockchain now.
+					if !cs.dkgVerifierIsNil() {
+						cs.Logger.Info("successfully run initial DKG round, verifier ready")
+						cs.saveInitialVerifier()
+						return
 					}
 				case <-timeout.C:
 					cs.Logger.Info("initial DKG round timeout")
+					cs.metrics.DKGRoundTimeouts.Add(1)
+					cs.dkgMtx.Lock()
+					cs.dkgRoundFailed("timeout")
+					cs.dkgMtx.Unlock()
 					secondsToNextRound := time.Duration(cmn.RandInt63n(cs.config.InitialDKGRoundRetryTimeout.Milliseconds())) * time.Millisecond
 					cs.Logger.Info(fmt.Sprintf("waiting %f seconds to the next DKG round", secondsToNextRound.Seconds()))
 					retryTimeout.Reset(secondsToNextRound)
 				case <-retryTimeout.C:
 					// cs.dkg.Verifier is nil, so we start a new DKG round
 					return
+				case <-cs.Quit():
+					return
 				}
 			}
 		}()
@@ -713,10 +815,6 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 		var mi msgInfo
 
 		select {
-		case msg, ok := <-dkgMsgQ:
-			if ok && !cs.dkg.IsOnChain() {
-				cs.dkg.HandleOffChainShare(msg, cs.Height, cs.Validators, cs.privValidator.GetPubKey())
-			}
 		case <-cs.txNotifier.TxsAvailable():
 			cs.handleTxsAvailable()
 		case mi = <-cs.peerMsgQueue:
@@ -752,6 +850,60 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 	}
 }
 
+// dkgRoutine handles the messages of the off-chain DKG, received from peers
+// or sent by this node, on a goroutine of its own, so that a DKG round does
+// not hold up the consensus.
+func (cs *ConsensusState) dkgRoutine() {
+	msgQ := cs.dkg.MsgQueue()
+	for {
+		select {
+		case msg := <-msgQ:
+			cs.handleDKGMsg(msg)
+		case <-cs.Quit():
+			return
+		}
+	}
+}
+
+// handleDKGMsg hands the DKG message to the DKG, at the current height of the
+// consensus. Until the first DKG round is over, the verifier it produces is
+// swapped in at once, to start the chain.
+func (cs *ConsensusState) handleDKGMsg(msg *dkgtypes.DKGDataMessage) {
+	cs.mtx.RLock()
+	height, validators, privValidator := cs.Height, cs.Validators, cs.privValidator
+	cs.mtx.RUnlock()
+	if privValidator == nil {
+		return
+	}
+
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	if cs.dkg.IsOnChain() {
+		return
+	}
+	cs.dkgHeight = height
+	cs.dkg.HandleOffChainShare(msg, height, validators, privValidator.GetPubKey())
+	if !cs.dkg.Verifier().IsNil() {
+		return
+	}
+	cs.dkg.CheckDKGTime(-1, validators)
+	if !cs.dkg.Verifier().IsNil() {
+		select {
+		case cs.dkgVerifierReady <- struct{}{}:
+		default:
+		}
+		return
+	}
+	cs.Logger.Info("handled off-chain dkg message, verifier is not ready yet")
+}
+
+// dkgVerifierIsNil returns true if the DKG has no verifier yet.
+func (cs *ConsensusState) dkgVerifierIsNil() bool {
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	return cs.dkg.Verifier().IsNil()
+}
+
 // state transitions on complete-proposal, 2/3-any, 2/3-one
 func (cs *ConsensusState) handleMsg(mi msgInfo) {
 	cs.mtx.Lock()
@@ -788,7 +940,7 @@ func (cs *ConsensusState) handleMsg(mi msgInfo) {
 	case *VoteMessage:
 		// attempt to add the vote and dupeout the validator if its a duplicate signature
 		// if the vote gives us a 2/3-any or 2/3-one, we transition
-		added, err = cs.tryAddVote(msg.Vote, peerID)
+		added, err = cs.tryAddVote(msg.Vote, msg.RandomSeed, peerID)
 		if added {
 			cs.statsMsgQueue <- mi
 		}
@@ -1108,7 +1260,7 @@ func (cs *ConsensusState) createProposalBlock() (block *types.Block, blockParts
 	}
 
 	proposerAddr := cs.privValidator.GetPubKey().Address()
-	return cs.blockExec.CreateProposalBlock(cs.Height, cs.state, commit, proposerAddr)
+	return cs.blockExec.CreateProposalBlock(cs.Height, cs.state, commit, cs.randomBeaconKeyChange(), proposerAddr)
 }
 
 // Enter: `timeoutPropose` after entering Propose.
@@ -1168,6 +1320,14 @@ func (cs *ConsensusState) defaultDoPrevote(height int64, round int) {
 		return
 	}
 
+	// The validators must be able to sign the random data with the key the
+	// block commits, so only keys of DKG rounds of this node are accepted.
+	if err := cs.checkRandomBeaconKeyChange(cs.ProposalBlock.RandomBeaconKeyChange); err != nil {
+		logger.Error("enterPrevote: ProposalBlock commits an unknown random beacon key", "err", err)
+		cs.signAddVote(types.PrevoteType, nil, types.PartSetHeader{})
+		return
+	}
+
 	// Prevote cs.ProposalBlock
 	// NOTE: the proposal signature is validated when it is received,
 	// and the proposal block parts are validated as they are received (against the merkle hash in the proposal)
@@ -1377,20 +1537,7 @@ func (cs *ConsensusState) enterCommit(height int64, commitRound int) {
 	}
 
 	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
-		randomData, err := cs.dkg.Verifier().Recover(
-			append(cs.getPreviousBlock().RandomData, cs.blsSeed...),
-			precommits.GetVotes(),
-		)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to recover random data from votes: %v", err))
-		}
-		cs.Logger.Info("Generated random data", "rand_data", randomData)
-		// TODO @oopcode: check if this is a possible situation.
-		if cs.ProposalBlock != nil {
-			cs.ProposalBlock.Header.SetRandomData(randomData)
-			cs.blsSeed = nil
-		}
-
+		cs.tryRecoverRandomData(height, commitRound)
 	}
 
 	// The Locked* fields no longer matter.
@@ -1448,6 +1595,12 @@ func (cs *ConsensusState) tryFinalizeCommit(height int64) {
 			blockID.Hash)
 		return
 	}
+	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() && cs.randomData == nil {
+		// The random data is recovered as soon as a precommit with a missing
+		// valid random share arrives (see addVote).
+		logger.Info("Attempt to finalize failed. We don't have the random data.")
+		return
+	}
 
 	//	go
 	cs.finalizeCommit(height)
@@ -1483,8 +1636,9 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 
 	prevBlock := cs.getPreviousBlock()
 	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
+		block.Header.SetRandomData(cs.randomData)
 		if err := cs.dkg.Verifier().VerifyRandomData(
-			append(prevBlock.Header.RandomData, cs.blsSeed...),
+			types.MakeRandomMessage(prevBlock.Header.RandomData, cs.state.Seed),
 			block.Header.RandomData,
 		); err != nil {
 			panic(fmt.Sprintf("Cannot finalizeCommit, ProposalBlock has invalid random value: %v", err))
@@ -1502,6 +1656,11 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 		// but may differ from the LastCommit included in the next block
 		precommits := cs.Votes.Precommits(cs.CommitRound)
 		seenCommit := precommits.MakeCommit()
+		seenCommit.AggregateSigners = cs.randomDataSigners
+		if seenCommit.AggregateSigners == nil && cs.dkg != nil {
+			// The random data of the block was not recovered by this node.
+			seenCommit.AggregateSigners = cs.blockExec.RandomDataSigners(cs.state, block, seenCommit, cs.dkg.Verifier())
+		}
 		cs.blockStore.SaveBlock(block, blockParts, seenCommit)
 	} else {
 		// Happens during replay if we already saved the block but didn't commit
@@ -1534,20 +1693,6 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// Create a copy of the state for staging and an event cache for txs.
 	stateCopy := cs.state.Copy()
 
-	if cs.dkg != nil {
-		//dkgLosers := cs.dkg.GetLosers()
-		//for _, dkgLoser := range dkgLosers {
-		//	// TODO: Currently we lack a lot of relevant information about the failed node,
-		//	// TODO: including the height at which we had a misbehavior. We should address
-		//	// TODO: this as soon as possible.
-		//	if err := cs.evpool.AddEvidence(&DKGEvidenceMissingData{
-		//		pubkey: dkgLoser.PubKey,
-		//	}); err != nil {
-		//		panic(fmt.Sprintf("failed to add dkg evidence for validator %s: %v", dkgLoser.Address.String(), err))
-		//	}
-		//}
-	}
-
 	// Execute and commit the block, update and save the state, and update the mempool.
 	// NOTE The block.AppHash wont reflect these txs until the next block.
 	var err error
@@ -1564,14 +1709,19 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 		return
 	}
 
-	cs.blsSeed = stateCopy.Seed
-
 	if cs.dkg != nil {
 		if cs.dkg.IsOnChain() {
 			go cs.dkg.NewBlockNotify()
 		}
 	}
 
+	if len(block.RandomData) > 0 {
+		cs.eventBus.PublishEventRandomData(types.EventDataRandomData{
+			Height:     block.Height,
+			RandomData: block.RandomData,
+		})
+	}
+
 	fail.Fail() // XXX
 
 	// must be called before we update state
@@ -1592,6 +1742,89 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// * cs.StartTime is set to when we will start round0.
 }
 
+// startDKGRound starts a new DKG round for the given validators, unless a
+// round is already running, e.g. one started by CheckDKGTime at this height.
+// The current verifier stays active until the round is finished.
+func (cs *ConsensusState) startDKGRound(height int64, validators *types.ValidatorSet) {
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	if cs.dkgRoundActive {
+		cs.Logger.Info("Validator set changed, DKG round already running", "height", height, "round", cs.dkgRoundID)
+		return
+	}
+	cs.Logger.Info("Validator set changed, starting a new DKG round", "height", height)
+	if err := cs.dkg.StartDKGRound(validators); err != nil {
+		cs.Logger.Error("Failed to start a DKG round", "height", height, "err", err)
+		cs.dkgRoundFailed(err.Error())
+	}
+}
+
+// dkgRoundStarted publishes the start of a DKG round. A round still running
+// is abandoned by the DKG and reported as failed.
+func (cs *ConsensusState) dkgRoundStarted(roundID int) {
+	cs.dkgRoundFailed(fmt.Sprintf("superseded by round %d", roundID))
+	cs.dkgRoundID, cs.dkgRoundStartTime, cs.dkgRoundActive = roundID, time.Now(), true
+	cs.metrics.DKGRounds.Add(1)
+	// the DKG may start a round before the eventBus is set
+	if cs.eventBus == nil {
+		return
+	}
+	cs.eventBus.PublishEventDKGRoundStarted(types.EventDataDKGRound{
+		RoundID: roundID,
+		Height:  cs.dkgHeight,
+	})
+}
+
+// dkgRoundCompleted publishes the completion of the running DKG round, whose
+// key is used from startHeight on.
+func (cs *ConsensusState) dkgRoundCompleted(startHeight int64) {
+	if !cs.dkgRoundActive {
+		return
+	}
+	cs.dkgRoundActive = false
+	cs.metrics.DKGRoundDurationSeconds.Observe(time.Since(cs.dkgRoundStartTime).Seconds())
+	if cs.eventBus == nil {
+		return
+	}
+	cs.eventBus.PublishEventDKGRoundCompleted(types.EventDataDKGRound{
+		RoundID:     cs.dkgRoundID,
+		Height:      cs.dkgHeight,
+		StartHeight: startHeight,
+	})
+}
+
+// dkgRoundFailed publishes the failure of the running DKG round, if any.
+func (cs *ConsensusState) dkgRoundFailed(reason string) {
+	if !cs.dkgRoundActive {
+		return
+	}
+	cs.dkgRoundActive = false
+	cs.metrics.DKGRoundFailures.Add(1)
+	if cs.eventBus == nil {
+		return
+	}
+	cs.eventBus.PublishEventDKGRoundFailed(types.EventDataDKGRound{
+		RoundID: cs.dkgRoundID,
+		Height:  cs.dkgHeight,
+		Reason:  reason,
+	})
+}
+
+// addDKGEvidence adds evidence against the validator with pubKey, which sent
+// the DKG message msg with corrupt data, to the evidence pool, so it is
+// included into one of the next blocks and delivered to the application in
+// BeginBlock. It is called with dkgMtx held.
+func (cs *ConsensusState) addDKGEvidence(pubKey crypto.PubKey, msg types.DKGMessage) {
+	ev := types.NewDKGEvidenceCorruptData(pubKey, cs.dkgHeight, msg)
+	if err := ev.ValidateBasic(); err != nil {
+		cs.Logger.Error("Invalid DKG evidence", "evidence", ev, "err", err)
+		return
+	}
+	if err := cs.evpool.AddEvidence(ev); err != nil {
+		cs.Logger.Error("Failed to add DKG evidence", "evidence", ev, "err", err)
+	}
+}
+
 func (cs *ConsensusState) recordMetrics(height int64, block *types.Block) {
 	cs.metrics.Validators.Set(float64(cs.Validators.Size()))
 	cs.metrics.ValidatorsPower.Set(float64(cs.Validators.TotalVotingPower()))
@@ -1630,6 +1863,13 @@ func (cs *ConsensusState) recordMetrics(height int64, block *types.Block) {
 	cs.metrics.TotalTxs.Set(float64(block.TotalTxs))
 	cs.metrics.CommittedHeight.Set(float64(block.Height))
 
+	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
+		cs.metrics.RandomShares.Set(float64(cs.validRandomShares))
+		cs.metrics.InvalidRandomShares.Set(float64(cs.invalidRandomShares))
+	}
+	if epoch, err := sm.LoadRandomBeaconEpoch(cs.blockExec.DB(), height); err == nil {
+		cs.metrics.RandomBeaconEpoch.Set(float64(epoch.Epoch))
+	}
 }
 
 //-----------------------------------------------------------------------------
@@ -1740,9 +1980,11 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 	return added, nil
 }
 
-// Attempt to add the vote. if its a duplicate signature, dupeout the validator
-func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, error) {
-	added, err := cs.addVote(vote, peerID)
+// Attempt to add the vote. if its a duplicate signature, dupeout the validator.
+// randomSeed is the seed the random share of a precommit is signed with, nil
+// for the seed of the state.
+func (cs *ConsensusState) tryAddVote(vote *types.Vote, randomSeed []byte, peerID p2p.ID) (bool, error) {
+	added, err := cs.addVote(vote, randomSeed, peerID)
 	if err != nil {
 		// If the vote height is off, we'll just ignore it,
 		// But if it's a conflicting sig, add it to the cs.evpool.
@@ -1778,6 +2020,7 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, err
 
 func (cs *ConsensusState) addVote(
 	vote *types.Vote,
+	randomSeed []byte,
 	peerID p2p.ID) (added bool, err error) {
 	cs.Logger.Debug(
 		"addVote",
@@ -1823,17 +2066,9 @@ func (cs *ConsensusState) addVote(
 
 	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
 		if vote.Type == types.PrecommitType {
-			var (
-				prevBlockData = cs.getPreviousBlock().RandomData
-				validatorAddr = vote.ValidatorAddress.String()
-			)
-			if err := cs.dkg.Verifier().VerifyRandomShare(
-				validatorAddr,
-				append(prevBlockData, cs.blsSeed...),
-				vote.BLSSignature,
-			); err != nil {
-				return false, fmt.Errorf("random share authenticy check failed: %v, validator %v, prevBlockData %v, vote.BLSSignature %v",
-					err, validatorAddr, prevBlockData, vote.BLSSignature)
+			if err := cs.verifyRandomShare(vote, randomSeed); err != nil {
+				cs.invalidRandomShares++
+				return false, err
 			}
 		}
 	}
@@ -1850,6 +2085,10 @@ func (cs *ConsensusState) addVote(
 	}
 	cs.evsw.FireEvent(types.EventVote, vote)
 
+	if vote.Type == types.PrecommitType && cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
+		cs.validRandomShares++
+	}
+
 	switch vote.Type {
 	case types.PrevoteType:
 		prevotes := cs.Votes.Prevotes(vote.Round)
@@ -1924,6 +2163,14 @@ func (cs *ConsensusState) addVote(
 		precommits := cs.Votes.Precommits(vote.Round)
 		cs.Logger.Info("Added to precommit", "vote", vote, "precommits", precommits.StringShort())
 
+		// The commit may be waiting for a valid random share to recover the
+		// random data.
+		if cs.Step == cstypes.RoundStepCommit && cs.CommitRound == vote.Round &&
+			cs.dkg != nil && !cs.dkg.Verifier().IsNil() && cs.randomData == nil {
+			cs.tryRecoverRandomData(height, vote.Round)
+			cs.tryFinalizeCommit(height)
+		}
+
 		blockID, ok := precommits.TwoThirdsMajority()
 		if ok {
 			// Executed as TwoThirdsMajority could be from a higher round
@@ -2008,8 +2255,8 @@ func (cs *ConsensusState) signAddVote(type_ types.SignedMsgType, hash []byte, he
 	if cs.dkg != nil && !cs.dkg.Verifier().IsNil() {
 		var randomData []byte
 		if type_ == types.PrecommitType {
-			randomData, err = cs.dkg.Verifier().Sign(
-				append(cs.getPreviousBlock().Header.RandomData, cs.blsSeed...),
+			randomData, err = cs.signRandomShare(
+				types.MakeRandomMessage(cs.getPreviousBlock().Header.RandomData, cs.state.Seed),
 			)
 			if err != nil || len(randomData) == 0 {
 				cs.Logger.Error("Error signing vote", "height", cs.Height, "round", cs.Round, "err", err,
@@ -2024,7 +2271,7 @@ func (cs *ConsensusState) signAddVote(type_ types.SignedMsgType, hash []byte, he
 	}
 
 	if err == nil {
-		cs.sendInternalMessage(msgInfo{&VoteMessage{vote}, ""})
+		cs.sendInternalMessage(msgInfo{&VoteMessage{Vote: vote}, ""})
 		cs.Logger.Info("Signed and pushed vote", "height", cs.Height, "round", cs.Round, "vote", vote, "err", err)
 		return vote
 	}
@@ -2034,6 +2281,105 @@ func (cs *ConsensusState) signAddVote(type_ types.SignedMsgType, hash []byte, he
 	return nil
 }
 
+// randomShare is a random share and the seed it is signed with.
+type randomShare struct {
+	seed  []byte
+	share []byte
+}
+
+// verifyRandomShare returns an error if the random share of the precommit is
+// not valid for the random message of the current height. A share signed with
+// a seed other than the one of the state is an error too, but is first checked
+// for a conflict with the other shares of the validator at the height.
+func (cs *ConsensusState) verifyRandomShare(vote *types.Vote, seed []byte) error {
+	if seed == nil {
+		seed = cs.state.Seed
+	}
+	var (
+		prevBlockData = cs.getPreviousBlock().RandomData
+		validatorAddr = vote.ValidatorAddress.String()
+	)
+	if err := cs.dkg.Verifier().VerifyRandomShare(
+		validatorAddr,
+		types.MakeRandomMessage(prevBlockData, seed),
+		vote.BLSSignature,
+	); err != nil {
+		return fmt.Errorf("random share authenticy check failed: %v, validator %v, prevBlockData %v, vote.BLSSignature %v",
+			err, validatorAddr, prevBlockData, vote.BLSSignature)
+	}
+	cs.checkConflictingRandomShares(vote, prevBlockData, seed)
+	if !bytes.Equal(seed, cs.state.Seed) {
+		return fmt.Errorf("random share of validator %v signed with seed %X, expected %X",
+			validatorAddr, seed, cs.state.Seed)
+	}
+	return nil
+}
+
+// checkConflictingRandomShares adds evidence to the evidence pool if the
+// valid random share of the precommit and the first valid share of the same
+// key share at the height are signed with different seeds.
+func (cs *ConsensusState) checkConflictingRandomShares(vote *types.Vote, prevRandomData, seed []byte) {
+	index, err := tbls.SigShare(vote.BLSSignature).Index()
+	if err != nil {
+		return
+	}
+	first, ok := cs.randomShares[index]
+	if !ok {
+		cs.randomShares[index] = randomShare{seed: seed, share: vote.BLSSignature}
+		return
+	}
+	if bytes.Equal(first.seed, seed) {
+		return
+	}
+
+	_, val := cs.Validators.GetByIndex(vote.ValidatorIndex)
+	if val == nil || !bytes.Equal(val.Address, vote.ValidatorAddress) {
+		return
+	}
+	if cs.privValidator != nil && bytes.Equal(val.Address, cs.privValidator.GetPubKey().Address()) {
+		cs.Logger.Error("Found conflicting random shares from ourselves. Did you unsafe_reset a validator?",
+			"height", vote.Height, "round", vote.Round)
+		return
+	}
+	ev := types.NewConflictingRandomSharesEvidence(val.PubKey, vote.Height, prevRandomData,
+		first.seed, first.share, seed, vote.BLSSignature)
+	if err := ev.ValidateBasic(); err != nil {
+		cs.Logger.Error("Invalid conflicting random shares evidence", "evidence", ev, "err", err)
+		return
+	}
+	if err := cs.evpool.AddEvidence(ev); err != nil {
+		cs.Logger.Error("Failed to add conflicting random shares evidence", "evidence", ev, "err", err)
+	}
+}
+
+// signRandomShare returns the share of the random data of the current height.
+// It is signed by the private validator if it implements
+// types.RandomShareSigner, as FilePV and remote signers do: it holds the BLS
+// key share and refuses to sign conflicting shares. Otherwise, e.g. for a
+// types.MockPV, it is signed by the verifier, which offers no such protection.
+func (cs *ConsensusState) signRandomShare(msg []byte) ([]byte, error) {
+	if signer, ok := cs.privValidator.(types.RandomShareSigner); ok {
+		return signer.SignRandomShare(cs.state.ChainID, cs.Height, cs.Round, msg)
+	}
+	verifier := cs.dkg.Verifier()
+	if !hasKeyShare(verifier) {
+		return nil, errors.New("neither the private validator nor the verifier can sign random shares")
+	}
+	return verifier.Sign(msg)
+}
+
+// hasKeyShare returns false if the verifier is known not to hold a BLS key
+// share.
+func hasKeyShare(verifier dkgtypes.Verifier) bool {
+	switch v := verifier.(type) {
+	case *types.BLSVerifier:
+		return v.Keypair != nil
+	case *blsShare.BLSVerifier:
+		return v.Keypair != nil
+	}
+	return true
+}
+
 //---------------------------------------------------------
 
 func CompareHRS(h1 int64, r1 int, s1 cstypes.RoundStepType, h2 int64, r2 int, s2 cstypes.RoundStepType) int {
@@ -2116,6 +2462,189 @@ func (cs *ConsensusState) getPreviousBlock() *types.Block {
 	return prevBlock
 }
 
+// tryRecoverRandomData recovers the random data of the block being committed
+// from the random shares of the precommits of the given round. A failure is
+// not fatal: it is reported once per height and the commit waits for more
+// precommits.
+func (cs *ConsensusState) tryRecoverRandomData(height int64, commitRound int) {
+	if cs.randomData != nil {
+		return
+	}
+	if cs.randomDataRecoveryStart.IsZero() {
+		cs.randomDataRecoveryStart = time.Now()
+	}
+
+	msg := types.MakeRandomMessage(cs.getPreviousBlock().RandomData, cs.state.Seed)
+	precommits := cs.Votes.Precommits(commitRound)
+	blockID, _ := precommits.TwoThirdsMajority()
+	shares := validRandomShares(cs.dkg.Verifier(), msg, precommits, blockID)
+	randomData, err := cs.dkg.Verifier().Recover(msg, shares)
+	if err != nil {
+		if cs.randomDataRecoveryFailed {
+			cs.Logger.Debug("Still failed to recover random data from precommits",
+				"height", height, "commitRound", commitRound, "shares", len(shares), "err", err)
+			return
+		}
+		cs.Logger.Error("Failed to recover random data from precommits, waiting for more",
+			"height", height, "commitRound", commitRound, "shares", len(shares), "err", err)
+		cs.randomDataRecoveryFailed = true
+		cs.metrics.RandomDataRecoveryFailures.Add(1)
+		cs.eventBus.PublishEventRandomDataRecoveryFailed(cs.RoundStateEvent())
+		cs.evsw.FireEvent(types.EventRandomDataRecoveryFailed, &cs.RoundState)
+		return
+	}
+
+	cs.Logger.Info("Recovered random data", "height", height, "randomData", randomData)
+	cs.metrics.RandomDataRecoverySeconds.Set(time.Since(cs.randomDataRecoveryStart).Seconds())
+	cs.randomData = randomData
+	cs.randomDataSigners = cmn.NewBitArray(precommits.Size())
+	for _, share := range shares {
+		cs.randomDataSigners.SetIndex(share.(*types.Vote).ValidatorIndex, true)
+	}
+}
+
+// validRandomShares returns the precommits for blockID which carry a valid
+// random share for msg. Invalid shares and shares with an index that was
+// already seen are skipped, so a byzantine validator can not prevent the
+// random data from being recovered. Precommits for other blocks are skipped
+// too: the signers of the random data are saved with the commit of blockID,
+// see state.randomDataSigners.
+func validRandomShares(verifier dkgtypes.Verifier, msg []byte, precommits *types.VoteSet,
+	blockID types.BlockID) []blsShare.BLSSigner {
+	var (
+		shares = make([]blsShare.BLSSigner, 0, precommits.Size())
+		seen   = make(map[int]bool)
+	)
+	for i := 0; i < precommits.Size(); i++ {
+		vote := precommits.GetByIndex(i)
+		if vote == nil || len(vote.BlockID.Hash) == 0 || !vote.BlockID.Equals(blockID) {
+			continue
+		}
+		index, err := tbls.SigShare(vote.BLSSignature).Index()
+		if err != nil || seen[index] {
+			continue
+		}
+		if err := verifier.VerifyRandomShare(vote.ValidatorAddress.String(), msg, vote.BLSSignature); err != nil {
+			continue
+		}
+		seen[index] = true
+		shares = append(shares, vote)
+	}
+	return shares
+}
+
+// randomBeaconKeyChange returns the change to the key of the last DKG round of
+// this node which is not committed yet, if any, to be proposed. If the chain
+// has no key yet, the key must be the one the random data is signed with.
+func (cs *ConsensusState) randomBeaconKeyChange() *types.RandomBeaconKeyChange {
+	if cs.dkg == nil {
+		return nil
+	}
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	if len(cs.dkgVerifiers) == 0 {
+		return nil
+	}
+	verifier := cs.dkgVerifiers[len(cs.dkgVerifiers)-1]
+	if cs.state.RandomBeaconEpoch.Key().IsEmpty() && cs.dkg.Verifier() != dkgtypes.Verifier(verifier) {
+		return nil
+	}
+	return verifier.KeyChange()
+}
+
+// checkRandomBeaconKeyChange returns an error unless the key change, which may
+// be nil, could have been proposed by this node, see randomBeaconKeyChange.
+func (cs *ConsensusState) checkRandomBeaconKeyChange(change *types.RandomBeaconKeyChange) error {
+	if change == nil {
+		return nil
+	}
+	if cs.dkg == nil {
+		return errors.New("no DKG")
+	}
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+	for _, verifier := range cs.dkgVerifiers {
+		if !bytes.Equal(verifier.KeyChange().Hash(), change.Hash()) {
+			continue
+		}
+		if cs.state.RandomBeaconEpoch.Key().IsEmpty() && cs.dkg.Verifier() != dkgtypes.Verifier(verifier) {
+			return fmt.Errorf("key %X is not the key of the verifier", change.Key.Hash())
+		}
+		return nil
+	}
+	return fmt.Errorf("key %X is not the key of a DKG round of this node", change.Key.Hash())
+}
+
+// syncDKGVerifier makes the DKG sign and verify the random data with the key
+// of the random beacon epoch of the state. The verifier of the DKG round of
+// the key is used if this node took part in it, otherwise the node can only
+// verify the random data. The verifier of a new epoch is saved, so that it can
+// be reloaded instead of running a new DKG round after a restart.
+func (cs *ConsensusState) syncDKGVerifier(state sm.State) {
+	epoch := state.RandomBeaconEpoch
+	key := epoch.Key()
+	if cs.dkg == nil || key.IsEmpty() {
+		return
+	}
+	cs.dkgMtx.Lock()
+	defer cs.dkgMtx.Unlock()
+
+	// the verifiers of this and of older DKG rounds must not be proposed again
+	var known *types.BLSVerifier
+	for i, v := range cs.dkgVerifiers {
+		if bytes.Equal(v.Key.Hash(), key.Hash()) {
+			known, cs.dkgVerifiers = v, cs.dkgVerifiers[i+1:]
+			break
+		}
+	}
+	verifier, ok := cs.dkg.Verifier().(*types.BLSVerifier)
+	if !ok || verifier.IsNil() || !bytes.Equal(verifier.Key.Hash(), key.Hash()) {
+		verifier = known
+		if verifier == nil {
+			cs.Logger.Info("Not a participant of the random beacon epoch, the random data is only verified",
+				"epoch", epoch.Epoch)
+			var err error
+			if verifier, err = key.Verifier(nil); err != nil {
+				// the key is validated before it is committed
+				panic(fmt.Sprintf("Invalid key of random beacon epoch %d: %v", epoch.Epoch, err))
+			}
+		}
+		cs.dkg.SetVerifier(verifier)
+	}
+
+	// the verifier of the epoch of the state the node starts with is already saved
+	if cs.state.IsEmpty() || epoch.Epoch <= cs.dkgEpoch {
+		cs.dkgEpoch = epoch.Epoch
+		return
+	}
+	cs.dkgEpoch = epoch.Epoch
+	if cs.saveVerifier == nil || verifier.Keypair == nil {
+		return
+	}
+	if err := cs.saveVerifier(verifier, epoch.StartHeight); err != nil {
+		cs.Logger.Error("Failed to save DKG verifier", "epoch", epoch.Epoch, "err", err)
+		return
+	}
+	cs.Logger.Info("Saved DKG verifier", "epoch", epoch.Epoch, "height", epoch.StartHeight)
+}
+
+// saveInitialVerifier gives the verifier of the initial DKG round to the
+// private validator. Its key is used from the current height on, before the
+// block committing it is made.
+func (cs *ConsensusState) saveInitialVerifier() {
+	cs.dkgMtx.Lock()
+	verifier, ok := cs.dkg.Verifier().(*types.BLSVerifier)
+	cs.dkgMtx.Unlock()
+	if cs.saveVerifier == nil || !ok || verifier.IsNil() || verifier.Keypair == nil {
+		return
+	}
+	if err := cs.saveVerifier(verifier, cs.Height); err != nil {
+		cs.Logger.Error("Failed to save DKG verifier", "height", cs.Height, "err", err)
+		return
+	}
+	cs.Logger.Info("Saved DKG verifier", "height", cs.Height)
+}
+
 func WithDKG(dkg dkgtypes.DKG) StateOption {
 	return func(cs *ConsensusState) { cs.dkg = dkg }
 }
@@ -2123,3 +2652,9 @@ func WithDKG(dkg dkgtypes.DKG) StateOption {
 func WithEVSW(evsw tmevents.EventSwitch) StateOption {
 	return func(cs *ConsensusState) { cs.evsw = evsw }
 }
+
+// WithVerifierSaver sets the function used to persist the verifier produced
+// by a DKG round together with the first height it is used at.
+func WithVerifierSaver(save func(verifier dkgtypes.Verifier, height int64) error) StateOption {
+	return func(cs *ConsensusState) { cs.saveVerifier = save }
+}
@@ -4,17 +4,24 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	cfg "github.com/tendermint/tendermint/config"
 	cstypes "github.com/tendermint/tendermint/consensus/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	p2pmock "github.com/tendermint/tendermint/p2p/mock"
+	"github.com/tendermint/tendermint/privval"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -1607,7 +1614,7 @@ func TestStateOutputVoteStats(t *testing.T) {
 
 	vote := signVote(vss[1], types.PrecommitType, []byte("test"), types.PartSetHeader{})
 
-	voteMessage := &VoteMessage{vote}
+	voteMessage := &VoteMessage{Vote: vote}
 	cs.handleMsg(msgInfo{voteMessage, peer.ID()})
 
 	statsMessage := <-cs.statsMsgQueue
@@ -1615,13 +1622,13 @@ func TestStateOutputVoteStats(t *testing.T) {
 	require.Equal(t, peer.ID(), statsMessage.PeerID, "")
 
 	// sending the same part from different peer
-	cs.handleMsg(msgInfo{&VoteMessage{vote}, "peer2"})
+	cs.handleMsg(msgInfo{&VoteMessage{Vote: vote}, "peer2"})
 
 	// sending the vote for the bigger height
 	incrementHeight(vss[1])
 	vote = signVote(vss[1], types.PrecommitType, []byte("test"), types.PartSetHeader{})
 
-	cs.handleMsg(msgInfo{&VoteMessage{vote}, peer.ID()})
+	cs.handleMsg(msgInfo{&VoteMessage{Vote: vote}, peer.ID()})
 
 	select {
 	case <-cs.statsMsgQueue:
@@ -1631,6 +1638,301 @@ func TestStateOutputVoteStats(t *testing.T) {
 
 }
 
+func TestStateValidRandomShares(t *testing.T) {
+	valSet, privVals := types.RandValidatorSet(4, 1)
+	verifier := blsShare.NewTestBLSVerifier("test")
+	msg := types.MakeRandomMessage([]byte(types.InitialRandomData), nil)
+
+	share, err := verifier.Sign(msg)
+	require.NoError(t, err)
+	badShare := append([]byte{}, share...)
+	badShare[len(badShare)-1] ^= 0xff
+
+	precommits := types.NewVoteSet(config.ChainID(), 1, 0, types.PrecommitType, valSet)
+	blsSignatures := [][]byte{badShare, share, share, share}
+	for i, privVal := range privVals {
+		vs := NewValidatorStub(privVal, i)
+		vs.Height = 1
+		hash := []byte("test")
+		if i == 3 {
+			hash = nil
+		}
+		vote := signVote(vs, types.PrecommitType, hash, types.PartSetHeader{})
+		vote.BLSSignature = blsSignatures[i]
+		added, err := precommits.AddVote(vote)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// The invalid share comes first and makes the recovery from all
+	// precommits fail.
+	_, err = verifier.Recover(msg, precommits.GetVotes())
+	require.Error(t, err)
+
+	// Only one of the valid shares for the block is left.
+	blockID := types.BlockID{Hash: []byte("test")}
+	shares := validRandomShares(verifier, msg, precommits, blockID)
+	require.Len(t, shares, 1)
+	assert.Equal(t, precommits.GetByIndex(1), shares[0])
+
+	randomData, err := verifier.Recover(msg, shares)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomData(msg, randomData))
+}
+
+func TestStateValidRandomSharesForBlock(t *testing.T) {
+	valSet, privVals := types.RandValidatorSet(4, 1)
+	msg := types.MakeRandomMessage([]byte(types.InitialRandomData), nil)
+	blockID := types.BlockID{Hash: []byte("test")}
+
+	// The first validator precommits another block with a valid share.
+	precommits := types.NewVoteSet(config.ChainID(), 1, 0, types.PrecommitType, valSet)
+	var verifier *blsShare.BLSVerifier
+	for i, privVal := range privVals {
+		verifier = blsShare.NewTestBLSVerifierByID("TestStateValidRandomSharesForBlock", i, 2, 4)
+		share, err := verifier.Sign(msg)
+		require.NoError(t, err)
+
+		vs := NewValidatorStub(privVal, i)
+		vs.Height = 1
+		hash := blockID.Hash
+		if i == 0 {
+			hash = []byte("other")
+		}
+		vote := signVote(vs, types.PrecommitType, hash, types.PartSetHeader{})
+		vote.BLSSignature = share
+		added, err := precommits.AddVote(vote)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Only the shares of the precommits for the block count, so the signers
+	// of the random data can be saved with its commit.
+	shares := validRandomShares(verifier, msg, precommits, blockID)
+	require.Len(t, shares, 3)
+	for _, share := range shares {
+		assert.True(t, share.(*types.Vote).BlockID.Equals(blockID))
+	}
+	randomData, err := verifier.Recover(msg, shares)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomData(msg, randomData))
+}
+
+func TestStateRandomDataRecoveryFailedOnce(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	cs.dkg = &verifierDKG{verifier: blsShare.NewTestBLSVerifier("test")}
+	failures := generic.NewCounter("random_data_recovery_failures")
+	cs.metrics.RandomDataRecoveryFailures = failures
+	failedCh := subscribe(cs.eventBus, types.EventQueryRandomDataRecoveryFailed)
+
+	// There are no precommits to recover the random data from, however many
+	// times it is attempted at the height.
+	for i := 0; i < 3; i++ {
+		cs.tryRecoverRandomData(cs.Height, 0)
+	}
+	assert.EqualValues(t, 1, failures.Value())
+	<-failedCh
+	select {
+	case msg := <-failedCh:
+		t.Fatalf("unexpected random data recovery event %v", msg.Data())
+	default:
+	}
+
+	// The failure is reported again at the next height.
+	state := cs.state.Copy()
+	state.LastBlockHeight++
+	cs.updateToState(state)
+	assert.False(t, cs.randomDataRecoveryFailed)
+}
+
+func TestStateDKGOnValidatorSetChange(t *testing.T) {
+	testCases := []struct {
+		trigger string
+		rounds  int
+	}{
+		{cfg.DKGTriggerInterval, 0},
+		{cfg.DKGTriggerValidatorSetChange, 1},
+	}
+	for _, tc := range testCases {
+		cs, _ := randConsensusState(1)
+		cs.config.DKGTrigger = tc.trigger
+		dkg := &roundCountingDKG{}
+		cs.dkg = dkg
+
+		// The validator set is unchanged at height 2.
+		state := cs.state.Copy()
+		state.LastBlockHeight++
+		cs.updateToState(state)
+		assert.Equal(t, 0, dkg.rounds, tc.trigger)
+
+		// The validator set changes at height 3.
+		state = state.Copy()
+		state.LastBlockHeight++
+		state.LastHeightValidatorsChanged = state.LastBlockHeight + 1
+		cs.updateToState(state)
+		assert.Equal(t, tc.rounds, dkg.rounds, tc.trigger)
+
+		// No round is started while another one is running, e.g. one started
+		// by the interval trigger at the same height.
+		cs.dkgRoundActive = true
+		state = state.Copy()
+		state.LastBlockHeight++
+		state.LastHeightValidatorsChanged = state.LastBlockHeight + 1
+		cs.updateToState(state)
+		assert.Equal(t, tc.rounds, dkg.rounds, tc.trigger)
+	}
+}
+
+func TestStateDKGRoundEvents(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	dkgRounds, dkgRoundFailures := generic.NewCounter("dkg_rounds"), generic.NewCounter("dkg_round_failures")
+	cs.metrics.DKGRounds, cs.metrics.DKGRoundFailures = dkgRounds, dkgRoundFailures
+	startedCh := subscribe(cs.eventBus, types.EventQueryDKGRoundStarted)
+	completedCh := subscribe(cs.eventBus, types.EventQueryDKGRoundCompleted)
+	failedCh := subscribe(cs.eventBus, types.EventQueryDKGRoundFailed)
+
+	ensureDKGRoundEvent := func(ch <-chan tmpubsub.Message, expected types.EventDataDKGRound) {
+		select {
+		case msg := <-ch:
+			assert.Equal(t, expected, msg.Data())
+		case <-time.After(ensureTimeout):
+			t.Fatalf("Timeout expired while waiting for %v", expected)
+		}
+	}
+
+	cs.dkgRoundStarted(1)
+	ensureDKGRoundEvent(startedCh, types.EventDataDKGRound{RoundID: 1, Height: cs.Height})
+	cs.dkgRoundCompleted(10)
+	ensureDKGRoundEvent(completedCh, types.EventDataDKGRound{RoundID: 1, Height: cs.Height, StartHeight: 10})
+
+	// A completed round can not fail, a round still running when the next one
+	// is started fails.
+	cs.dkgRoundFailed("timeout")
+	cs.dkgRoundStarted(2)
+	ensureDKGRoundEvent(startedCh, types.EventDataDKGRound{RoundID: 2, Height: cs.Height})
+	cs.dkgRoundStarted(3)
+	ensureDKGRoundEvent(failedCh, types.EventDataDKGRound{
+		RoundID: 2, Height: cs.Height, Reason: "superseded by round 3"})
+	ensureDKGRoundEvent(startedCh, types.EventDataDKGRound{RoundID: 3, Height: cs.Height})
+	cs.dkgRoundFailed("timeout")
+	ensureDKGRoundEvent(failedCh, types.EventDataDKGRound{RoundID: 3, Height: cs.Height, Reason: "timeout"})
+	assert.EqualValues(t, 3, dkgRounds.Value())
+	assert.EqualValues(t, 2, dkgRoundFailures.Value())
+
+	select {
+	case msg := <-failedCh:
+		t.Fatalf("unexpected DKG round event %v", msg.Data())
+	case msg := <-completedCh:
+		t.Fatalf("unexpected DKG round event %v", msg.Data())
+	default:
+	}
+}
+
+func TestStateSignRandomShare(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	keyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	msg := []byte("random message")
+
+	// A private validator which can not sign random shares, like the MockPV,
+	// leaves them to the verifier, if it has a key share.
+	cs.dkg = &verifierDKG{verifier: blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, 1, 1)}
+	_, err = cs.signRandomShare(msg)
+	assert.Error(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[0], 1, 1)
+	cs.dkg = &verifierDKG{verifier: verifier}
+	share, err := cs.signRandomShare(msg)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+
+	// A private validator which can sign them always does, even if the
+	// verifier has a key share.
+	tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+	require.NoError(t, err)
+	defer os.Remove(tempStateFile.Name())
+	filePV := privval.GenFilePV("", tempStateFile.Name())
+	cs.SetPrivValidator(filePV)
+	_, err = cs.signRandomShare(msg)
+	assert.Error(t, err)
+	filePV.SetBLSShare(keyring.Shares[0])
+	share, err = cs.signRandomShare(msg)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+
+	// The private validator refuses to sign another message at the height.
+	_, err = cs.signRandomShare([]byte("other random message"))
+	assert.Error(t, err)
+}
+
+func TestStateConflictingRandomShares(t *testing.T) {
+	cs, vss := randConsensusState(2)
+	keyring, err := blsShare.NewBLSKeyring(2, 2)
+	require.NoError(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[0], 2, 2)
+	cs.dkg = &verifierDKG{verifier: verifier}
+	evidence := make(chan types.Evidence, 1)
+	cs.evpool = dkgEvidencePool(evidence)
+
+	// the precommits of the other validator, whose random shares are signed
+	// with the given seed
+	vs := vss[1]
+	signer := blsShare.NewBLSVerifier(nil, keyring.Shares[1], 0, 0)
+	precommit := func(seed []byte) *types.Vote {
+		vote := signVote(vs, types.PrecommitType, nil, types.PartSetHeader{})
+		vote.BLSSignature, err = signer.Sign(types.MakeRandomMessage([]byte(types.InitialRandomData), seed))
+		require.NoError(t, err)
+		return vote
+	}
+
+	// A share signed with the seed of the state is added.
+	added, err := cs.tryAddVote(precommit(cs.state.Seed), nil, "peer")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// A share signed with another seed is rejected, and conflicts with the
+	// first share.
+	otherSeed := []byte("other seed")
+	added, err = cs.tryAddVote(precommit(otherSeed), otherSeed, "peer")
+	assert.Error(t, err)
+	assert.False(t, added)
+	require.Len(t, evidence, 1)
+	ev, ok := (<-evidence).(*types.ConflictingRandomSharesEvidence)
+	require.True(t, ok)
+	assert.Nil(t, ev.ValidateBasic())
+	assert.Nil(t, ev.Verify(cs.state.ChainID, vs.GetPubKey()))
+	assert.Nil(t, ev.VerifyShares(verifier))
+	assert.EqualValues(t, cs.Height, ev.Height())
+
+	// A share that is not signed with the seed sent along is only rejected.
+	added, err = cs.tryAddVote(precommit(otherSeed), []byte("third seed"), "peer")
+	assert.Error(t, err)
+	assert.False(t, added)
+	assert.Len(t, evidence, 0)
+}
+
+// verifierDKG is a DKG with a fixed verifier.
+type verifierDKG struct {
+	dkgtypes.DKG
+	verifier dkgtypes.Verifier
+}
+
+func (dkg *verifierDKG) Verifier() dkgtypes.Verifier { return dkg.verifier }
+
+func (dkg *verifierDKG) CheckDKGTime(height int64, validators *types.ValidatorSet) {}
+
+// roundCountingDKG counts the started DKG rounds.
+type roundCountingDKG struct {
+	dkgtypes.DKG
+	rounds int
+}
+
+func (dkg *roundCountingDKG) CheckDKGTime(height int64, validators *types.ValidatorSet) {}
+
+func (dkg *roundCountingDKG) StartDKGRound(validators *types.ValidatorSet) error {
+	dkg.rounds++
+	return nil
+}
+
 // subscribe subscribes test client to the given query and returns a channel with cap = 1.
 func subscribe(eventBus *types.EventBus, q tmpubsub.Query) <-chan tmpubsub.Message {
 	sub, err := eventBus.Subscribe(context.Background(), testSubscriber, q)
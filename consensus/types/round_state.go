@@ -93,6 +93,8 @@ type RoundState struct {
 	LastCommit                *types.VoteSet      `json:"last_commit"`  // Last precommits at Height-1
 	LastValidators            *types.ValidatorSet `json:"last_validators"`
 	TriggeredTimeoutPrecommit bool                `json:"triggered_timeout_precommit"`
+	// Seed the random shares of the precommits at Height are signed with
+	Seed []byte `json:"seed"`
 }
 
 // Compressed version of the RoundState for use in RPC
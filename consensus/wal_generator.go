@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	dkgOffChain "github.com/corestario/dkglib/lib/offChain"
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
@@ -76,8 +77,23 @@ func WALGenerateNBlocks(t *testing.T, wr io.Writer, numBlocks int) (err error) {
 	evpool := sm.MockEvidencePool{}
 	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(), mempool, evpool)
 
+	// the key share of the validator comes from the genesis key set
+	keypair, err := genDoc.BLSShare.Deserialize()
+	if err != nil {
+		return errors.Wrap(err, "failed to load keypair")
+	}
+	masterPubKey, err := blsShare.LoadPubKey(genDoc.BLSMasterPubKey, genDoc.BLSNumShares)
+	if err != nil {
+		return errors.Wrap(err, "failed to load master public key from genesis")
+	}
+	verifier, err := types.NewBLSVerifier(masterPubKey, keypair, genDoc.BLSThreshold, genDoc.BLSNumShares)
+	if err != nil {
+		return err
+	}
+	privValidator.SetBLSShare(keypair)
+
 	evsw := events.NewEventSwitch()
-	dkg := dkgOffChain.NewOffChainDKG(evsw, "localchain", dkgOffChain.WithVerifier(dkgOffChain.GetVerifier(1, 1)("wal_generator", 0)), dkgOffChain.WithLogger(logger.With("dkg")))
+	dkg := dkgOffChain.NewOffChainDKG(evsw, "localchain", dkgOffChain.WithVerifier(verifier), dkgOffChain.WithLogger(logger.With("dkg")))
 
 	consensusState := NewConsensusState(config.Consensus, state.Copy(), blockExec, blockStore, mempool, evpool, WithDKG(dkg), WithEVSW(evsw))
 	consensusState.SetLogger(logger)
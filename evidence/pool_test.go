@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
@@ -21,13 +23,15 @@ func TestMain(m *testing.M) {
 }
 
 func initializeValidatorState(valAddr []byte, height int64) dbm.DB {
+	return initializeStateWithValidator(&types.Validator{Address: valAddr}, height)
+}
+
+func initializeStateWithValidator(val *types.Validator, height int64) dbm.DB {
 	stateDB := dbm.NewMemDB()
 
 	// create validator set and state
 	valSet := &types.ValidatorSet{
-		Validators: []*types.Validator{
-			{Address: valAddr},
-		},
+		Validators: []*types.Validator{val},
 	}
 	state := sm.State{
 		LastBlockHeight:             0,
@@ -85,6 +89,38 @@ func TestEvidencePool(t *testing.T) {
 	assert.Equal(t, 1, pool.evidenceList.Len())
 }
 
+func TestEvidencePoolDKGEvidence(t *testing.T) {
+	privKey := ed25519.GenPrivKey()
+	pubKey := privKey.PubKey()
+	height := int64(5)
+	stateDB := initializeStateWithValidator(types.NewValidator(pubKey, 10), height)
+	evidenceDB := dbm.NewMemDB()
+	pool := NewEvidencePool(stateDB, evidenceDB)
+
+	msg := types.DKGMessage{Type: types.DKGMessageTypePubKey, Addr: pubKey.Address(), RoundID: 1, Data: []byte("corrupt")}
+	sig, err := privKey.Sign(msg.SignBytes())
+	require.NoError(t, err)
+	msg.Signature = sig
+
+	// evidence against somebody who is not a validator
+	otherKey := ed25519.GenPrivKey()
+	otherMsg := msg
+	otherMsg.Addr = otherKey.PubKey().Address()
+	otherMsg.Signature, err = otherKey.Sign(otherMsg.SignBytes())
+	require.NoError(t, err)
+	assert.NotNil(t, pool.AddEvidence(types.NewDKGEvidenceCorruptData(otherKey.PubKey(), height, otherMsg)))
+
+	// forged message
+	forged := msg
+	forged.RoundID = 2
+	assert.NotNil(t, pool.AddEvidence(types.NewDKGEvidenceCorruptData(pubKey, height, forged)))
+
+	ev := types.NewDKGEvidenceCorruptData(pubKey, height, msg)
+	assert.Nil(t, pool.AddEvidence(ev))
+	assert.Equal(t, 1, pool.evidenceList.Len())
+	assert.Equal(t, []types.Evidence{ev}, pool.PendingEvidence(-1))
+}
+
 func TestEvidencePoolIsCommitted(t *testing.T) {
 	// Initialization:
 	valAddr := []byte("validator_address")
@@ -115,6 +115,62 @@ func (p *Provider) getValidatorSet(chainID string, height int64) (valset *types.
 	return
 }
 
+// Implements SeedProvider.
+func (p *Provider) Seed(chainID string, height int64) ([]byte, error) {
+	if chainID != p.chainID {
+		return nil, fmt.Errorf("expected chainID %s, got %s", p.chainID, chainID)
+	}
+	if height < 1 {
+		return nil, fmt.Errorf("expected height >= 1, got height %v", height)
+	}
+	res, err := p.Client.BlockResults(&height)
+	if err != nil {
+		return nil, err
+	}
+	if res.Results == nil || res.Results.EndBlock == nil {
+		return nil, nil
+	}
+	return res.Results.EndBlock.Seed, nil
+}
+
+// Implements RandomBeaconKeyProvider.
+func (p *Provider) RandomBeaconKey(chainID string, height int64) (types.RandomBeaconKey, error) {
+	if chainID != p.chainID {
+		return types.RandomBeaconKey{}, fmt.Errorf("expected chainID %s, got %s", p.chainID, chainID)
+	}
+	if height < 1 {
+		return types.RandomBeaconKey{}, fmt.Errorf("expected height >= 1, got height %v", height)
+	}
+	// The epochs are returned highest first, a page at a time.
+	var maxEpoch int64
+	for {
+		res, err := p.Client.RandomEpochs(0, maxEpoch)
+		if err != nil {
+			return types.RandomBeaconKey{}, err
+		}
+		for _, epoch := range res.Epochs {
+			// The source must not send missing epochs, or epochs out of order.
+			if epoch == nil {
+				return types.RandomBeaconKey{}, fmt.Errorf("missing random beacon epoch in the response")
+			}
+			if maxEpoch > 0 && epoch.Epoch > maxEpoch {
+				return types.RandomBeaconKey{}, fmt.Errorf("expected random beacon epoch <= %d, got %d",
+					maxEpoch, epoch.Epoch)
+			}
+			if epoch.StartHeight <= height {
+				return epoch.Key(), nil
+			}
+			if epoch.Epoch <= 1 {
+				return types.RandomBeaconKey{}, fmt.Errorf("no random beacon epoch for height %d", height)
+			}
+			maxEpoch = epoch.Epoch - 1
+		}
+		if len(res.Epochs) == 0 {
+			return types.RandomBeaconKey{}, fmt.Errorf("no random beacon epoch for height %d", height)
+		}
+	}
+}
+
 // This does no validation.
 func (p *Provider) fillFullCommit(signedHeader types.SignedHeader) (fc lite.FullCommit, err error) {
 
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	sm "github.com/tendermint/tendermint/state"
+)
+
+// epochsClient returns the given epochs from RandomEpochs.
+type epochsClient struct {
+	SignStatusClient
+	epochs []*sm.RandomBeaconEpoch
+}
+
+func (c epochsClient) RandomEpochs(minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error) {
+	return &ctypes.ResultRandomEpochs{LastEpoch: 2, Epochs: c.epochs}, nil
+}
+
+func TestProviderRandomBeaconKey(t *testing.T) {
+	epochs := []*sm.RandomBeaconEpoch{
+		{Epoch: 2, StartHeight: 10, MasterPubKey: "key2"},
+		{Epoch: 1, StartHeight: 1, MasterPubKey: "key1"},
+	}
+	p := NewProvider("test-chain", epochsClient{epochs: epochs}).(*Provider)
+	key, err := p.RandomBeaconKey("test-chain", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "key2", key.MasterPubKey)
+	key, err = p.RandomBeaconKey("test-chain", 9)
+	require.NoError(t, err)
+	assert.Equal(t, "key1", key.MasterPubKey)
+
+	// A source that sends missing epochs or epochs out of order fails.
+	for i, epochs := range [][]*sm.RandomBeaconEpoch{
+		{nil},
+		{epochs[0], nil},
+		{{Epoch: 1, StartHeight: 10}, {Epoch: 2, StartHeight: 1}},
+		{{Epoch: 2, StartHeight: 10}, {Epoch: 3, StartHeight: 1}},
+	} {
+		p := NewProvider("test-chain", epochsClient{epochs: epochs}).(*Provider)
+		_, err := p.RandomBeaconKey("test-chain", 5)
+		assert.Error(t, err, "#%d", i)
+	}
+}
@@ -31,6 +31,13 @@ type DynamicVerifier struct {
 	// New info, like a node rpc, or other import method.
 	source Provider
 
+	// Optional, checks header random data against the BLS master public key.
+	// It is replaced by a verifier of the key of a later random beacon epoch
+	// when the source provides that key.
+	randomMtx             sync.Mutex
+	randomVerifier        *types.BLSVerifier
+	skipUnknownRandomKeys bool
+
 	// pending map to synchronize concurrent verification requests
 	mtx                  sync.Mutex
 	pendingVerifications map[int64]chan struct{}
@@ -59,6 +66,38 @@ func (dv *DynamicVerifier) SetLogger(logger log.Logger) {
 	dv.source.SetLogger(logger)
 }
 
+// SetRandomVerifier enables verification of Header.RandomData. Each header's
+// random data must then be a valid aggregate signature of the previous
+// header's random data (plus seed, if the source is a SeedProvider) by the
+// random beacon key of the header. The keys other than the one of the
+// verifier are fetched from the source, which must be a
+// RandomBeaconKeyProvider, or the headers of those keys fail verification with
+// ErrInvalidRandomData, see SetSkipUnknownRandomBeaconKeys. Since the previous
+// header must be trusted, the headers are then verified one after the other
+// rather than by bisection.
+// See NewRandomVerifier.
+func (dv *DynamicVerifier) SetRandomVerifier(verifier *types.BLSVerifier) {
+	dv.randomMtx.Lock()
+	defer dv.randomMtx.Unlock()
+	dv.randomVerifier = verifier
+}
+
+// SetSkipUnknownRandomBeaconKeys makes the verifier skip the random data of
+// the headers whose random beacon key is not the one of the random verifier
+// when the source is not a RandomBeaconKeyProvider, instead of failing them.
+// The random data of such headers is then not verified at all.
+func (dv *DynamicVerifier) SetSkipUnknownRandomBeaconKeys(skip bool) {
+	dv.randomMtx.Lock()
+	defer dv.randomMtx.Unlock()
+	dv.skipUnknownRandomKeys = skip
+}
+
+func (dv *DynamicVerifier) getRandomVerifier() *types.BLSVerifier {
+	dv.randomMtx.Lock()
+	defer dv.randomMtx.Unlock()
+	return dv.randomVerifier
+}
+
 // Implements Verifier.
 func (dv *DynamicVerifier) ChainID() string {
 	return dv.chainID
@@ -96,7 +135,9 @@ func (dv *DynamicVerifier) Verify(shdr types.SignedHeader) error {
 	if err == nil {
 		// If loading trust commit successfully, and trust commit equal to shdr, then don't verify it,
 		// just return nil.
-		if bytes.Equal(trustedFCSameHeight.SignedHeader.Hash(), shdr.Hash()) {
+		// NOTE: RandomData is not part of the header hash, so compare it too.
+		if bytes.Equal(trustedFCSameHeight.SignedHeader.Hash(), shdr.Hash()) &&
+			(dv.getRandomVerifier() == nil || bytes.Equal(trustedFCSameHeight.SignedHeader.RandomData, shdr.RandomData)) {
 			dv.logger.Info(fmt.Sprintf("Load full commit at height %d from cache, there is not need to verify.", shdr.Height))
 			return nil
 		}
@@ -152,6 +193,11 @@ func (dv *DynamicVerifier) Verify(shdr types.SignedHeader) error {
 		return err
 	}
 
+	// RandomData is not covered by the commit signatures, verify it separately.
+	if err := dv.verifyRandomData(shdr); err != nil {
+		return err
+	}
+
 	// By now, the SignedHeader is fully validated and we're synced up to
 	// SignedHeader.Height - 1. To sync to SignedHeader.Height, we need
 	// the validator set at SignedHeader.Height + 1 so we can verify the
@@ -183,8 +229,129 @@ func (dv *DynamicVerifier) Verify(shdr types.SignedHeader) error {
 	return dv.trusted.SaveFullCommit(nfc)
 }
 
+// verifyRandomData checks that shdr.RandomData is the aggregate signature of
+// the previous header's random data followed by the seed returned by the
+// application at the previous height. It does nothing if no random verifier
+// is set or the random beacon key of the header is not known, see
+// randomVerifierOf.
+//
+// The random data of the previous header must be trusted, or old random data
+// could be replayed along with the random data it follows. The seed is taken
+// from the source: the validators sign a single seed after the random data of
+// a height.
+func (dv *DynamicVerifier) verifyRandomData(shdr types.SignedHeader) error {
+	verifier, err := dv.randomVerifierOf(shdr.Header)
+	if err != nil || verifier == nil {
+		return err
+	}
+
+	prevRandomData := []byte(types.InitialRandomData)
+	var seed []byte
+	if shdr.Height > 1 {
+		prevHeight := shdr.Height - 1
+		prevRandomData, err = dv.trustedRandomData(prevHeight)
+		if err != nil {
+			return err
+		}
+
+		if seeds, ok := dv.source.(SeedProvider); ok {
+			seed, err = seeds.Seed(dv.chainID, prevHeight)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	msg := types.MakeRandomMessage(prevRandomData, seed)
+	if err := verifier.VerifyRandomData(msg, shdr.RandomData); err != nil {
+		return lerr.ErrInvalidRandomData(shdr.Height, err)
+	}
+	return nil
+}
+
+// trustedRandomData returns the random data of the trusted header at height h.
+// If h is not trusted yet, the headers from the latest trusted one up to h are
+// verified and saved one after the other, since the random data of a header
+// can only be verified with the random data of the header before it.
+func (dv *DynamicVerifier) trustedRandomData(h int64) ([]byte, error) {
+	for {
+		trustedFC, err := dv.trusted.LatestFullCommit(dv.chainID, 1, h)
+		if err != nil {
+			return nil, err
+		}
+		if trustedFC.Height() == h {
+			return trustedFC.SignedHeader.RandomData, nil
+		}
+
+		next := trustedFC.Height() + 1
+		sourceFC, err := dv.source.LatestFullCommit(dv.chainID, next, next)
+		if err != nil {
+			return nil, err
+		}
+		if sourceFC.Height() != next {
+			return nil, lerr.ErrCommitNotFound()
+		}
+		if err := sourceFC.ValidateFull(dv.chainID); err != nil {
+			return nil, err
+		}
+		if err := dv.verifyAndSave(trustedFC, sourceFC); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// randomVerifierOf returns a verifier of the random beacon key the header
+// commits to, or nil if there is none: no random verifier is set, the chain
+// had no key at the height, or the key is not the one of the random verifier,
+// the source is not a RandomBeaconKeyProvider and such keys are skipped. If
+// they are not, it returns ErrInvalidRandomData instead. A key from the source
+// replaces the one of the random verifier, since the later headers are
+// usually signed with it as well.
+func (dv *DynamicVerifier) randomVerifierOf(h *types.Header) (*types.BLSVerifier, error) {
+	verifier := dv.getRandomVerifier()
+	if verifier == nil || bytes.Equal(h.RandomBeaconKeyHash, verifier.Key.Hash()) {
+		return verifier, nil
+	}
+	if len(h.RandomBeaconKeyHash) == 0 {
+		dv.logger.Info("Skipping random data of a header without random beacon key", "height", h.Height)
+		return nil, nil
+	}
+	keys, ok := dv.source.(RandomBeaconKeyProvider)
+	if !ok {
+		dv.randomMtx.Lock()
+		skip := dv.skipUnknownRandomKeys
+		dv.randomMtx.Unlock()
+		if skip {
+			dv.logger.Info("Skipping random data of an unknown random beacon key",
+				"height", h.Height, "key", h.RandomBeaconKeyHash)
+			return nil, nil
+		}
+		return nil, lerr.ErrInvalidRandomData(h.Height,
+			fmt.Errorf("the source can not provide the random beacon key %X of the header", h.RandomBeaconKeyHash))
+	}
+
+	// The key is covered by the commit signatures through its hash.
+	key, err := keys.RandomBeaconKey(dv.chainID, h.Height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(key.Hash(), h.RandomBeaconKeyHash) {
+		return nil, lerr.ErrInvalidRandomData(h.Height,
+			fmt.Errorf("random beacon key %X of the source is not the key of the header %X",
+				key.Hash(), h.RandomBeaconKeyHash))
+	}
+	verifier, err = key.Verifier(nil)
+	if err != nil {
+		return nil, lerr.ErrInvalidRandomData(h.Height, err)
+	}
+	dv.SetRandomVerifier(verifier)
+	return verifier, nil
+}
+
 // verifyAndSave will verify if this is a valid source full commit given the
 // best match trusted full commit, and if good, persist to dv.trusted.
+// The random data of sourceFC is verified too, which requires the headers
+// before it to be trusted, see trustedRandomData.
 // Returns ErrTooMuchChange when >2/3 of trustedFC did not sign sourceFC.
 // Panics if trustedFC.Height() >= sourceFC.Height().
 func (dv *DynamicVerifier) verifyAndSave(trustedFC, sourceFC FullCommit) error {
@@ -199,6 +366,9 @@ func (dv *DynamicVerifier) verifyAndSave(trustedFC, sourceFC FullCommit) error {
 	if err != nil {
 		return err
 	}
+	if err := dv.verifyRandomData(sourceFC.SignedHeader); err != nil {
+		return err
+	}
 
 	return dv.trusted.SaveFullCommit(sourceFC)
 }
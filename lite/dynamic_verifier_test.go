@@ -8,7 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	bls "github.com/corestario/dkglib/lib/blsShare"
+
 	log "github.com/tendermint/tendermint/libs/log"
+	lerr "github.com/tendermint/tendermint/lite/errors"
 	"github.com/tendermint/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
 )
@@ -126,6 +129,132 @@ func TestDynamicVerify(t *testing.T) {
 
 }
 
+func TestDynamicVerifyRandomData(t *testing.T) {
+	source := &randomKeyProvider{
+		PersistentProvider: NewDBProvider("source", dbm.NewMemDB()),
+		keys:               make(map[int64]types.RandomBeaconKey),
+	}
+
+	chainID := "dynamic-verifier-random"
+	keys := genPrivKeys(5)
+	vals := keys.ToValidators(10, 0)
+	verifiers := make([]*types.BLSVerifier, 2)
+	for i := range verifiers {
+		keyring, err := bls.NewBLSKeyring(1, 1)
+		require.NoError(t, err)
+		verifiers[i], err = types.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[0], keyring.T, keyring.N)
+		require.NoError(t, err)
+	}
+
+	// Chain the random data the same way consensus does. The key changes
+	// after the first three heights.
+	count := 6
+	fcz := make([]FullCommit, count)
+	prevRandomData := []byte(types.InitialRandomData)
+	for i := 0; i < count; i++ {
+		verifier := verifiers[i/3]
+		fcz[i] = makeFullCommit(int64(i), keys, vals, vals, chainID)
+		header := fcz[i].SignedHeader.Header
+		header.RandomBeaconKeyHash = verifier.Key.Hash()
+		fcz[i].SignedHeader.Commit = keys.signHeader(header, 0, len(keys))
+		share, err := verifier.Sign(prevRandomData)
+		require.NoError(t, err)
+		vote := &types.Vote{BlockID: fcz[i].SignedHeader.Commit.BlockID, BLSSignature: share}
+		randomData, err := verifier.Recover(prevRandomData, []bls.BLSSigner{vote})
+		require.NoError(t, err)
+		header.SetRandomData(randomData)
+		require.NoError(t, source.SaveFullCommit(fcz[i]))
+		source.keys[header.Height] = verifier.Key
+		prevRandomData = randomData
+	}
+	tamper := func(i, j int) types.SignedHeader {
+		header := *fcz[i].SignedHeader.Header
+		header.SetRandomData(fcz[j].SignedHeader.RandomData)
+		return types.SignedHeader{Header: &header, Commit: fcz[i].SignedHeader.Commit}
+	}
+	newVerifier := func(source Provider) *DynamicVerifier {
+		trust := NewDBProvider("trust", dbm.NewMemDB())
+		require.NoError(t, trust.SaveFullCommit(fcz[0]))
+		ver := NewDynamicVerifier(chainID, trust, source)
+		ver.SetLogger(log.TestingLogger())
+		genesisVerifier, err := verifiers[0].Key.Verifier(nil)
+		require.NoError(t, err)
+		ver.SetRandomVerifier(genesisVerifier)
+		return ver
+	}
+
+	// The commit signatures do not cover the random data,
+	// so a header with substituted random data is only caught by the verifier.
+	ver := newVerifier(source)
+	err := ver.Verify(tamper(2, 1))
+	require.Error(t, err)
+	assert.True(t, lerr.IsErrInvalidRandomData(err), "%+v", err)
+	require.NoError(t, ver.Verify(fcz[2].SignedHeader))
+
+	// The random data of the previous header must be trusted: a source can not
+	// replay old random data along with the random data it follows.
+	replaySource := &randomKeyProvider{
+		PersistentProvider: NewDBProvider("replay", dbm.NewMemDB()),
+		keys:               source.keys,
+	}
+	for i := range fcz {
+		fc := fcz[i]
+		if i == 1 {
+			fc.SignedHeader = tamper(1, 0)
+		}
+		require.NoError(t, replaySource.SaveFullCommit(fc))
+	}
+	replayed := newVerifier(replaySource)
+	err = replayed.Verify(tamper(2, 1))
+	require.Error(t, err)
+	assert.True(t, lerr.IsErrInvalidRandomData(err), "%+v", err)
+	_, err = replayed.trusted.LatestFullCommit(chainID, fcz[1].Height(), fcz[1].Height())
+	assert.True(t, lerr.IsErrCommitNotFound(err), "%+v", err)
+
+	// The headers between the trusted one and the verified one are verified
+	// and trusted too.
+	ver = newVerifier(source)
+	require.NoError(t, ver.Verify(fcz[2].SignedHeader))
+	trusted, err := ver.trusted.LatestFullCommit(chainID, fcz[1].Height(), fcz[1].Height())
+	require.NoError(t, err)
+	assert.Equal(t, fcz[1].SignedHeader.RandomData, trusted.SignedHeader.RandomData)
+
+	// The key of a later epoch comes from the source and must be the one the
+	// header commits to.
+	err = ver.Verify(tamper(4, 3))
+	require.Error(t, err)
+	assert.True(t, lerr.IsErrInvalidRandomData(err), "%+v", err)
+	require.NoError(t, ver.Verify(fcz[4].SignedHeader))
+
+	source.keys[fcz[5].Height()] = verifiers[0].Key
+	err = ver.Verify(fcz[5].SignedHeader)
+	require.NoError(t, err, "the key of the verifier is used without asking the source")
+	ver = newVerifier(source)
+	err = ver.Verify(fcz[5].SignedHeader)
+	require.Error(t, err)
+	assert.True(t, lerr.IsErrInvalidRandomData(err), "%+v", err)
+
+	// Without the key, the random data of a later epoch can not be verified,
+	// unless the verifier explicitly skips it.
+	ver = newVerifier(source.PersistentProvider)
+	err = ver.Verify(tamper(4, 3))
+	require.Error(t, err)
+	assert.True(t, lerr.IsErrInvalidRandomData(err), "%+v", err)
+	ver = newVerifier(source.PersistentProvider)
+	ver.SetSkipUnknownRandomBeaconKeys(true)
+	require.NoError(t, ver.Verify(tamper(4, 3)))
+}
+
+// randomKeyProvider is a RandomBeaconKeyProvider of the keys by height.
+type randomKeyProvider struct {
+	PersistentProvider
+	keys map[int64]types.RandomBeaconKey
+}
+
+func (p *randomKeyProvider) RandomBeaconKey(chainID string, height int64) (types.RandomBeaconKey, error) {
+	return p.keys[height], nil
+}
+
 func makeFullCommit(height int64, keys privKeys, vals, nextVals *types.ValidatorSet, chainID string) FullCommit {
 	height += 1
 	consHash := []byte("special-params")
@@ -35,6 +35,16 @@ func (e errUnknownValidators) Error() string {
 		e.chainID, e.height)
 }
 
+type errInvalidRandomData struct {
+	height int64
+	reason error
+}
+
+func (e errInvalidRandomData) Error() string {
+	return fmt.Sprintf("Random data at height %d is invalid: %v",
+		e.height, e.reason)
+}
+
 type errEmptyTree struct{}
 
 func (e errEmptyTree) Error() string {
@@ -86,6 +96,20 @@ func IsErrUnknownValidators(err error) bool {
 	return ok
 }
 
+//-----------------
+// ErrInvalidRandomData
+
+// ErrInvalidRandomData indicates that the random data of a header is not the
+// aggregate signature of the previous random data.
+func ErrInvalidRandomData(height int64, reason error) error {
+	return errors.Wrap(errInvalidRandomData{height, reason}, "")
+}
+
+func IsErrInvalidRandomData(err error) bool {
+	_, ok := errors.Cause(err).(errInvalidRandomData)
+	return ok
+}
+
 //-----------------
 // ErrEmptyTree
 
@@ -30,3 +30,25 @@ type PersistentProvider interface {
 	// SaveFullCommit saves a FullCommit (without verification).
 	SaveFullCommit(fc FullCommit) error
 }
+
+// A provider that can also return the seed the application returned in
+// EndBlock. The seed at height H-1 is appended to the random data at H-1
+// to form the message whose aggregate signature is the random data at H.
+// Examples: client.Provider.
+type SeedProvider interface {
+
+	// Get the seed returned by EndBlock at height.
+	// Height must be >= 1.
+	Seed(chainID string, height int64) ([]byte, error)
+}
+
+// A provider that can also return the random beacon key the random data at a
+// height is signed with. The key does not have to be trusted: it must match
+// Header.RandomBeaconKeyHash, which is covered by the commit signatures.
+// Examples: client.Provider.
+type RandomBeaconKeyProvider interface {
+
+	// Get the random beacon key of the epoch height belongs to.
+	// Height must be >= 1.
+	RandomBeaconKey(chainID string, height int64) (types.RandomBeaconKey, error)
+}
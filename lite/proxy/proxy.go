@@ -75,6 +75,7 @@ func RPCRoutes(c rpcclient.Client) map[string]*rpcserver.RPCFunc {
 		"genesis":    rpcserver.NewRPCFunc(makeGenesisFunc(c), ""),
 		"block":      rpcserver.NewRPCFunc(makeBlockFunc(c), "height"),
 		"commit":     rpcserver.NewRPCFunc(makeCommitFunc(c), "height"),
+		"random":     rpcserver.NewRPCFunc(makeRandomFunc(c), "height"),
 		"tx":         rpcserver.NewRPCFunc(makeTxFunc(c), "hash,prove"),
 		"validators": rpcserver.NewRPCFunc(makeValidatorsFunc(c), "height"),
 
@@ -123,6 +124,12 @@ func makeCommitFunc(c rpcclient.Client) func(ctx *rpctypes.Context, height *int6
 	}
 }
 
+func makeRandomFunc(c rpcclient.Client) func(ctx *rpctypes.Context, height *int64) (*ctypes.ResultRandom, error) {
+	return func(ctx *rpctypes.Context, height *int64) (*ctypes.ResultRandom, error) {
+		return c.Random(height)
+	}
+}
+
 func makeTxFunc(c rpcclient.Client) func(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	return func(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
 		return c.Tx(hash, prove)
@@ -6,6 +6,7 @@ import (
 	log "github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/lite"
 	lclient "github.com/tendermint/tendermint/lite/client"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	dbm "github.com/tendermint/tm-db"
 )
 
@@ -30,6 +31,24 @@ func NewVerifier(
 	cert := lite.NewDynamicVerifier(chainID, trust, source)
 	cert.SetLogger(logger) // Sets logger recursively.
 
+	// If the genesis is available and has a BLS master public key,
+	// verify the random data of every header as well. The keys of later
+	// random beacon epochs are fetched from the source.
+	// TODO: like the full commit @ height 1, the genesis comes from the source.
+	if hc, ok := client.(rpcclient.HistoryClient); ok {
+		res, err := hc.Genesis()
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching genesis")
+		}
+		if res.Genesis.BLSMasterPubKey != "" {
+			randomVerifier, err := lite.NewRandomVerifier(res.Genesis)
+			if err != nil {
+				return nil, errors.Wrap(err, "constructing random verifier")
+			}
+			cert.SetRandomVerifier(randomVerifier)
+		}
+	}
+
 	// TODO: Make this more secure, e.g. make it interactive in the console?
 	_, err := trust.LatestFullCommit(chainID, 1, 1<<63-1)
 	if err != nil {
@@ -1,7 +1,9 @@
 package proxy
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -149,6 +151,24 @@ func (w Wrapper) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return res, err
 }
 
+// Random returns the random value at a given height and checks it against the
+// certified header. The header random data is verified by the lite verifier.
+func (w Wrapper) Random(height *int64) (*ctypes.ResultRandom, error) {
+	res, err := w.Client.Random(height)
+	if err != nil {
+		return nil, err
+	}
+	// get a checkpoint to verify from
+	resCommit, err := w.Commit(&res.Height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(res.RandomData, resCommit.SignedHeader.RandomData) {
+		return nil, errors.New("random data doesn't match header")
+	}
+	return res, nil
+}
+
 func (w Wrapper) RegisterOpDecoder(typ string, dec merkle.OpDecoder) {
 	w.prt.RegisterOpDecoder(typ, dec)
 }
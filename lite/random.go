@@ -0,0 +1,27 @@
+package lite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// NewRandomVerifier returns a verifier for header random data that checks
+// aggregate signatures against the BLS master public key from the genesis.
+// It holds no key share, so it can verify random data but not sign it.
+func NewRandomVerifier(genDoc *types.GenesisDoc) (*types.BLSVerifier, error) {
+	if genDoc.BLSMasterPubKey == "" {
+		return nil, errors.New("genesis has no BLS master public key")
+	}
+	key := types.RandomBeaconKey{
+		MasterPubKey: genDoc.BLSMasterPubKey,
+		Threshold:    genDoc.BLSThreshold,
+		NumShares:    genDoc.BLSNumShares,
+	}
+	verifier, err := key.Verifier(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load master public key from genesis: %v", err)
+	}
+	return verifier, nil
+}
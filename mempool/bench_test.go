@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
+	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/proxy"
 )
 
@@ -39,6 +40,52 @@ func BenchmarkCheckTx(b *testing.B) {
 	}
 }
 
+// BenchmarkBroadcastTxs gossips txs to peers which do not know about
+// MempoolAnnounceChannel, and reports the bytes sent per tx.
+func BenchmarkBroadcastTxs(b *testing.B) {
+	benchmarkGossipTxs(b, MempoolChannel)
+}
+
+// BenchmarkAnnounceTxs gossips txs to peers which know about
+// MempoolAnnounceChannel, and reports the bytes sent per tx. Each tx is then
+// requested by a single peer, which is sent the tx in addition.
+func BenchmarkAnnounceTxs(b *testing.B) {
+	benchmarkGossipTxs(b, MempoolChannel, MempoolAnnounceChannel)
+}
+
+func benchmarkGossipTxs(b *testing.B, channels ...byte) {
+	const (
+		numPeers = 50
+		txSize   = 250
+	)
+	peers := make([]*recordingPeer, numPeers)
+	for i := range peers {
+		peers[i] = newRecordingPeer(channels...)
+		peers[i].countOnly = true
+	}
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.Size = b.N
+	config.Mempool.MaxTxsBytes = int64(b.N * txSize)
+	memR, cleanup := newReactorWithPeers(config, peers...)
+	defer cleanup()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tx := make([]byte, txSize)
+		binary.BigEndian.PutUint64(tx, uint64(i))
+		memR.mempool.CheckTx(tx, nil, TxInfo{})
+	}
+	waitForNumTxs(b.N, peers...)
+	b.StopTimer()
+
+	var sentBytes int
+	for _, peer := range peers {
+		_, peerSentBytes := peer.sent()
+		sentBytes += peerSentBytes
+	}
+	b.ReportMetric(float64(sentBytes)/float64(b.N), "B/tx")
+}
+
 func BenchmarkCacheInsertTime(b *testing.B) {
 	cache := newMapTxCache(b.N)
 	txs := make([][]byte, b.N)
@@ -48,15 +48,17 @@ type CListMempool struct {
 	postCheck    PostCheckFunc
 
 	// Track whether we're rechecking txs.
-	// These are not protected by a mutex and are expected to be mutated
+	// This is not protected by a mutex and is expected to be mutated
 	// in serial (ie. by abci responses which are called in serial).
-	recheckCursor *clist.CElement // next expected response
-	recheckEnd    *clist.CElement // re-checking stops here
+	recheckQueue []*mempoolTx // txs of the expected responses, in order
 
 	// Map for quick access to txs to record sender in CheckTx.
 	// txsMap: txKey -> CElement
 	txsMap sync.Map
 
+	// Limits of the txs of every sender.
+	lanes *senderLanes
+
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
 	cache txCache
@@ -66,7 +68,8 @@ type CListMempool struct {
 
 	logger log.Logger
 
-	metrics *Metrics
+	metrics  *Metrics
+	eventBus types.MempoolEventPublisher
 }
 
 var _ Mempool = &CListMempool{}
@@ -82,15 +85,15 @@ func NewCListMempool(
 	options ...CListMempoolOption,
 ) *CListMempool {
 	mempool := &CListMempool{
-		config:        config,
-		proxyAppConn:  proxyAppConn,
-		txs:           clist.New(),
-		height:        height,
-		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
-		logger:        log.NewNopLogger(),
-		metrics:       NopMetrics(),
+		config:       config,
+		proxyAppConn: proxyAppConn,
+		txs:          clist.New(),
+		height:       height,
+		rechecking:   0,
+		lanes:        newSenderLanes(config.MaxTxsPerSender, config.MaxTxsBytesPerSender),
+		logger:       log.NewNopLogger(),
+		metrics:      NopMetrics(),
+		eventBus:     types.NopEventBus{},
 	}
 	if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
@@ -131,6 +134,13 @@ func WithMetrics(metrics *Metrics) CListMempoolOption {
 	return func(mem *CListMempool) { mem.metrics = metrics }
 }
 
+// WithEventBus sets the event bus the expired txs are published to.
+func WithEventBus(eventBus types.MempoolEventPublisher) CListMempoolOption {
+	return func(mem *CListMempool) { mem.eventBus = eventBus }
+}
+
+// InitWAL runs CheckTx on the txs of the WAL left by the previous run of the
+// node before opening it.
 // *panics* if can't create directory or open file.
 // *not thread safe*
 func (mem *CListMempool) InitWAL() {
@@ -139,7 +149,7 @@ func (mem *CListMempool) InitWAL() {
 	if err != nil {
 		panic(errors.Wrap(err, "Error ensuring WAL dir"))
 	}
-	af, err := auto.OpenAutoFile(walDir + "/wal")
+	af, err := replayWAL(mem, walDir+"/wal", mem.config, mem.logger)
 	if err != nil {
 		panic(errors.Wrap(err, "Error opening WAL file"))
 	}
@@ -188,7 +198,9 @@ func (mem *CListMempool) Flush() {
 	}
 
 	mem.txsMap = sync.Map{}
+	mem.lanes.Reset()
 	_ = atomic.SwapInt64(&mem.txsBytes, 0)
+	writeWAL(mem.wal, mem.logger, walFlushRecord, nil)
 }
 
 // TxsFront returns the first transaction in the ordered list for peer
@@ -205,6 +217,25 @@ func (mem *CListMempool) TxsWaitChan() <-chan struct{} {
 	return mem.txs.WaitChan()
 }
 
+// TxByKey returns the tx of the given key, if it is in the mempool.
+func (mem *CListMempool) TxByKey(key [sha256.Size]byte) (types.Tx, bool) {
+	if e, ok := mem.txsMap.Load(key); ok {
+		return e.(*clist.CElement).Value.(*mempoolTx).tx, true
+	}
+	return nil, false
+}
+
+// SeenTx returns true if the tx of the given key is in the mempool or in the
+// cache. A tx still in the mempool records the peer as one of its senders, so
+// that it is not sent back to the peer.
+func (mem *CListMempool) SeenTx(key [sha256.Size]byte, senderID uint16) bool {
+	if e, ok := mem.txsMap.Load(key); ok {
+		e.(*clist.CElement).Value.(*mempoolTx).senders.LoadOrStore(senderID, true)
+		return true
+	}
+	return mem.cache.Has(key)
+}
+
 // It blocks if we're waiting on Update() or Reap().
 // cb: A callback from the CheckTx command.
 //     It gets called from another goroutine.
@@ -259,17 +290,7 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo Tx
 	// END CACHE
 
 	// WAL
-	if mem.wal != nil {
-		// TODO: Notify administrators when WAL fails
-		_, err := mem.wal.Write([]byte(tx))
-		if err != nil {
-			mem.logger.Error("Error writing to WAL", "err", err)
-		}
-		_, err = mem.wal.Write([]byte("\n"))
-		if err != nil {
-			mem.logger.Error("Error writing to WAL", "err", err)
-		}
-	}
+	writeWAL(mem.wal, mem.logger, walTxRecord, tx)
 	// END WAL
 
 	// NOTE: proxyAppConn may error if tx buffer is full
@@ -291,7 +312,7 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo Tx
 // so the request specific callback can do the work.
 // When rechecking, we don't need the peerID, so the recheck callback happens here.
 func (mem *CListMempool) globalCb(req *abci.Request, res *abci.Response) {
-	if mem.recheckCursor == nil {
+	if len(mem.recheckQueue) == 0 {
 		return
 	}
 
@@ -318,9 +339,9 @@ func (mem *CListMempool) reqResCb(
 	externalCb func(*abci.Response),
 ) func(res *abci.Response) {
 	return func(res *abci.Response) {
-		if mem.recheckCursor != nil {
+		if len(mem.recheckQueue) > 0 {
 			// this should never happen
-			panic("recheck cursor is not nil in reqResCb")
+			panic("recheck queue is not empty in reqResCb")
 		}
 
 		mem.resCbFirstTime(tx, peerID, peerP2PID, res)
@@ -348,10 +369,13 @@ func (mem *CListMempool) addTx(memTx *mempoolTx) {
 //  - Update (lock held) if tx was committed
 // 	- resCbRecheck (lock not held) if tx was invalidated
 func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
+	key := txKey(tx)
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
-	mem.txsMap.Delete(txKey(tx))
+	mem.txsMap.Delete(key)
+	mem.lanes.Remove(elem.Value.(*mempoolTx))
 	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
+	writeWAL(mem.wal, mem.logger, walRemovedTxRecord, key[:])
 
 	if removeFromCache {
 		mem.cache.Remove(tx)
@@ -378,8 +402,18 @@ func (mem *CListMempool) resCbFirstTime(
 			memTx := &mempoolTx{
 				height:    mem.height,
 				gasWanted: r.CheckTx.GasWanted,
+				sender:    r.CheckTx.Sender,
+				nonce:     r.CheckTx.Nonce,
+				timestamp: time.Now(),
 				tx:        tx,
 			}
+			if err := mem.lanes.Add(memTx); err != nil {
+				mem.logger.Info("Rejected transaction", "tx", txID(tx), "peerID", peerP2PID, "err", err)
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (the sender might have room later)
+				mem.cache.Remove(tx)
+				return
+			}
 			memTx.senders.Store(peerID, true)
 			mem.addTx(memTx)
 			mem.logger.Info("Added good transaction",
@@ -410,7 +444,7 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
 		tx := req.GetCheckTx().Tx
-		memTx := mem.recheckCursor.Value.(*mempoolTx)
+		memTx := mem.recheckQueue[0]
 		if !bytes.Equal(tx, memTx.tx) {
 			panic(fmt.Sprintf(
 				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
@@ -427,15 +461,14 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			// Tx became invalidated due to newly committed block.
 			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", r, "err", postCheckErr)
 			// NOTE: we remove tx from the cache because it might be good later
-			mem.removeTx(tx, mem.recheckCursor, true)
-		}
-		if mem.recheckCursor == mem.recheckEnd {
-			mem.recheckCursor = nil
-		} else {
-			mem.recheckCursor = mem.recheckCursor.Next()
+			if e, ok := mem.txsMap.Load(txKey(tx)); ok {
+				mem.removeTx(tx, e.(*clist.CElement), true)
+			}
 		}
-		if mem.recheckCursor == nil {
+		mem.recheckQueue = mem.recheckQueue[1:]
+		if len(mem.recheckQueue) == 0 {
 			// Done!
+			mem.recheckQueue = nil
 			atomic.StoreInt32(&mem.rechecking, 0)
 			mem.logger.Info("Done rechecking txs")
 
@@ -481,9 +514,9 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	// TODO: we will get a performance boost if we have a good estimate of avg
 	// size per tx, and set the initial capacity based off of that.
 	// txs := make([]types.Tx, 0, cmn.MinInt(mem.txs.Len(), max/mem.avgTxSize))
-	txs := make([]types.Tx, 0, mem.txs.Len())
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		memTx := e.Value.(*mempoolTx)
+	memTxs := mem.txsByNonce()
+	txs := make([]types.Tx, 0, len(memTxs))
+	for _, memTx := range memTxs {
 		// Check total size requirement
 		aminoOverhead := types.ComputeAminoOverhead(memTx.tx, 1)
 		if maxBytes > -1 && totalBytes+int64(len(memTx.tx))+aminoOverhead > maxBytes {
@@ -517,14 +550,24 @@ func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
 		time.Sleep(time.Millisecond * 10)
 	}
 
-	txs := make([]types.Tx, 0, cmn.MinInt(mem.txs.Len(), max))
-	for e := mem.txs.Front(); e != nil && len(txs) <= max; e = e.Next() {
-		memTx := e.Value.(*mempoolTx)
-		txs = append(txs, memTx.tx)
+	memTxs := mem.txsByNonce()
+	txs := make([]types.Tx, 0, cmn.MinInt(len(memTxs), max))
+	for i := 0; i < len(memTxs) && len(txs) <= max; i++ {
+		txs = append(txs, memTxs[i].tx)
 	}
 	return txs
 }
 
+// txsByNonce returns the txs in order of arrival, except for the txs of
+// every sender, which are in order of nonce.
+func (mem *CListMempool) txsByNonce() []*mempoolTx {
+	memTxs := make([]*mempoolTx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTxs = append(memTxs, e.Value.(*mempoolTx))
+	}
+	return orderByNonce(memTxs)
+}
+
 func (mem *CListMempool) Update(
 	height int64,
 	txs types.Txs,
@@ -567,6 +610,8 @@ func (mem *CListMempool) Update(
 		}
 	}
 
+	mem.purgeExpiredTxs(height)
+
 	// Either recheck non-committed txs to see if they became invalid
 	// or just notify there're some txs left.
 	if mem.Size() > 0 {
@@ -574,8 +619,9 @@ func (mem *CListMempool) Update(
 			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
 			mem.recheckTxs()
 			// At this point, mem.txs are being rechecked.
-			// mem.recheckCursor re-scans mem.txs and possibly removes some txs.
-			// Before mem.Reap(), we should wait for mem.recheckCursor to be nil.
+			// mem.recheckQueue holds the txs of the pending responses, which
+			// possibly remove some txs.
+			// Before mem.Reap(), we should wait for mem.recheckQueue to be empty.
 		} else {
 			mem.notifyTxsAvailable()
 		}
@@ -587,19 +633,42 @@ func (mem *CListMempool) Update(
 	return nil
 }
 
+// purgeExpiredTxs removes the txs which have been in the mempool for longer
+// than the TTL of the config at the given height.
+func (mem *CListMempool) purgeExpiredTxs(height int64) {
+	if mem.config.TTLNumBlocks == 0 && mem.config.TTLDuration == 0 {
+		return
+	}
+
+	now := time.Now()
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		if memTx.isExpired(height, now, mem.config) {
+			// NOTE: we remove tx from the cache so it can be resubmitted
+			mem.removeTx(memTx.tx, e, true)
+			mem.logger.Info("Expired transaction", "tx", txID(memTx.tx), "height", memTx.Height())
+			mem.metrics.ExpiredTxs.Add(1)
+			err := mem.eventBus.PublishEventExpiredTx(types.EventDataExpiredTx{Height: height, Tx: memTx.tx})
+			if err != nil {
+				mem.logger.Error("Error publishing expired tx", "err", err)
+			}
+		}
+	}
+}
+
 func (mem *CListMempool) recheckTxs() {
 	if mem.Size() == 0 {
 		panic("recheckTxs is called, but the mempool is empty")
 	}
 
 	atomic.StoreInt32(&mem.rechecking, 1)
-	mem.recheckCursor = mem.txs.Front()
-	mem.recheckEnd = mem.txs.Back()
+	// The txs of a sender are rechecked in order of nonce, so that the app
+	// does not reject the follow-ups of a tx checked after them.
+	mem.recheckQueue = mem.txsByNonce()
 
 	// Push txs to proxyAppConn
 	// NOTE: globalCb may be called concurrently.
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		memTx := e.Value.(*mempoolTx)
+	for _, memTx := range mem.recheckQueue {
 		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
 			Tx:   memTx.tx,
 			Type: abci.CheckTxType_Recheck,
@@ -613,9 +682,13 @@ func (mem *CListMempool) recheckTxs() {
 
 // mempoolTx is a transaction that successfully ran
 type mempoolTx struct {
-	height    int64    // height that this tx had been validated in
-	gasWanted int64    // amount of gas this tx states it will require
-	tx        types.Tx //
+	height    int64     // height that this tx had been validated in
+	gasWanted int64     // amount of gas this tx states it will require
+	priority  int64     // priority of this tx, used by the PriorityMempool
+	sender    string    // sender of this tx, if any
+	nonce     uint64    // nonce of this tx among the txs of its sender
+	timestamp time.Time // time this tx was added to the mempool at
+	tx        types.Tx  //
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
@@ -627,12 +700,20 @@ func (memTx *mempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
 
+// isExpired returns true if the tx has been in the mempool for longer than
+// the TTL of the config at the given height and time.
+func (memTx *mempoolTx) isExpired(height int64, now time.Time, config *cfg.MempoolConfig) bool {
+	return (config.TTLNumBlocks > 0 && height-memTx.Height() > config.TTLNumBlocks) ||
+		(config.TTLDuration > 0 && now.Sub(memTx.timestamp) > config.TTLDuration)
+}
+
 //--------------------------------------------------------------------------------
 
 type txCache interface {
 	Reset()
 	Push(tx types.Tx) bool
 	Remove(tx types.Tx)
+	Has(key [sha256.Size]byte) bool
 }
 
 // mapTxCache maintains a LRU cache of transactions. This only stores the hash
@@ -702,13 +783,22 @@ func (cache *mapTxCache) Remove(tx types.Tx) {
 	cache.mtx.Unlock()
 }
 
+// Has returns true if the tx of the given key is in the cache.
+func (cache *mapTxCache) Has(key [sha256.Size]byte) bool {
+	cache.mtx.Lock()
+	_, exists := cache.map_[key]
+	cache.mtx.Unlock()
+	return exists
+}
+
 type nopTxCache struct{}
 
 var _ txCache = (*nopTxCache)(nil)
 
-func (nopTxCache) Reset()             {}
-func (nopTxCache) Push(types.Tx) bool { return true }
-func (nopTxCache) Remove(types.Tx)    {}
+func (nopTxCache) Reset()                     {}
+func (nopTxCache) Push(types.Tx) bool         { return true }
+func (nopTxCache) Remove(types.Tx)            {}
+func (nopTxCache) Has([sha256.Size]byte) bool { return false }
 
 //--------------------------------------------------------------------------------
 
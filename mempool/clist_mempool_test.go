@@ -1,6 +1,7 @@
 package mempool
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -212,6 +213,50 @@ func TestMempoolUpdate(t *testing.T) {
 	}
 }
 
+func TestMempoolExpiredTxs(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.TTLNumBlocks = 2
+	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+	mempool.eventBus = eventBus
+
+	oldTx, newTx := types.Tx("old"), types.Tx("new")
+	sub, err := eventBus.Subscribe(context.Background(), "test", types.EventQueryExpiredTxFor(oldTx))
+	require.NoError(t, err)
+
+	require.NoError(t, mempool.CheckTx(oldTx, nil, TxInfo{}))
+	require.NoError(t, mempool.Update(1, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	require.NoError(t, mempool.CheckTx(newTx, nil, TxInfo{}))
+	require.NoError(t, mempool.Update(2, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	assert.Equal(t, 2, mempool.Size())
+
+	// the old tx is dropped after 2 blocks
+	require.NoError(t, mempool.Update(3, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	assert.Equal(t, types.Txs{newTx}, mempool.ReapMaxTxs(-1))
+	select {
+	case msg := <-sub.Out():
+		assert.Equal(t, types.EventDataExpiredTx{Height: 3, Tx: oldTx}, msg.Data())
+	case <-time.After(time.Second):
+		t.Fatal("Expected an ExpiredTx event")
+	}
+
+	// and can be submitted again
+	require.NoError(t, mempool.CheckTx(oldTx, nil, TxInfo{}))
+
+	// the txs are dropped after ttl_duration too
+	config.Mempool.TTLNumBlocks = 0
+	config.Mempool.TTLDuration = time.Millisecond
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, mempool.Update(4, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	assert.Zero(t, mempool.Size())
+}
+
 func TestTxsAvailable(t *testing.T) {
 	app := kvstore.NewKVStoreApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -398,7 +443,7 @@ func TestMempoolCloseWAL(t *testing.T) {
 	sum1 := checksumFile(walFilepath, t)
 
 	// 6. Sanity check to ensure that the written TX matches the expectation.
-	require.Equal(t, sum1, checksumIt([]byte("foo\n")), "foo with a newline should be written")
+	require.Equal(t, sum1, checksumIt(encodeWALRecord(walTxRecord, []byte("foo"))), "the record of foo should be written")
 
 	// 7. Invoke CloseWAL() and ensure it discards the
 	// WAL thus any other write won't go through.
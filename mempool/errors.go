@@ -37,6 +37,25 @@ func (e ErrMempoolIsFull) Error() string {
 		e.txsBytes, e.maxTxsBytes)
 }
 
+// ErrSenderIsFull means the sender of a tx has too many txs in the mempool
+type ErrSenderIsFull struct {
+	sender string
+
+	numTxs int
+	maxTxs int
+
+	txsBytes    int64
+	maxTxsBytes int64
+}
+
+func (e ErrSenderIsFull) Error() string {
+	return fmt.Sprintf(
+		"sender %s is full: number of txs %d (max: %d), total txs bytes %d (max: %d)",
+		e.sender,
+		e.numTxs, e.maxTxs,
+		e.txsBytes, e.maxTxsBytes)
+}
+
 // ErrPreCheck is returned when tx is too big
 type ErrPreCheck struct {
 	Reason error
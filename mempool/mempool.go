@@ -69,6 +69,8 @@ type Mempool interface {
 	TxsBytes() int64
 
 	// InitWAL creates a directory for the WAL file and opens a file itself.
+	// The txs of the WAL left by the previous run are checked again first, up
+	// to the size of the mempool.
 	InitWAL()
 
 	// CloseWAL closes and discards the underlying WAL file.
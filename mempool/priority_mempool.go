@@ -0,0 +1,867 @@
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	auto "github.com/tendermint/tendermint/libs/autofile"
+	"github.com/tendermint/tendermint/libs/clist"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+//--------------------------------------------------------------------------------
+
+// PriorityMempool is an in-memory pool for transactions ordered by the
+// priority the application returns in ResponseCheckTx. Transactions are reaped
+// in order of decreasing priority, and in order of arrival among transactions
+// of the same priority. When the mempool is full, the transactions of the
+// lowest priority are evicted to make room for a transaction of a higher
+// priority; a transaction which can't make room is rejected. The
+// transactions of a sender of higher nonces are evicted along with the one
+// they depend on.
+//
+// Transactions are also kept in a concurrent list in order of arrival, which
+// is used to gossip them to peers and to recheck them.
+type PriorityMempool struct {
+	// Atomic integers
+	height     int64 // the last block Update()'d to
+	txsBytes   int64 // total size of mempool, in bytes
+	rechecking int32 // for re-checking filtered txs on Update()
+
+	// notify listeners (ie. consensus) when txs are available
+	notifiedTxsAvailable bool
+	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
+
+	config *cfg.MempoolConfig
+
+	proxyMtx     sync.Mutex
+	proxyAppConn proxy.AppConnMempool
+	txs          *clist.CList // concurrent linked-list of good txs, in order of arrival
+	preCheck     PreCheckFunc
+	postCheck    PostCheckFunc
+
+	// Track whether we're rechecking txs.
+	// This is not protected by a mutex and is expected to be mutated
+	// in serial (ie. by abci responses which are called in serial).
+	recheckQueue []*mempoolTx // txs of the expected responses, in order
+
+	// mtx protects the priority queue and the map below, which are updated
+	// by the abci responses while CheckTx or Reap may hold proxyMtx.
+	mtx sync.Mutex
+	// queue of txs with the lowest priority first, for eviction.
+	queue priorityTxQueue
+	// txsMap: txKey -> priorityTx
+	txsMap map[[sha256.Size]byte]*priorityTx
+	// senderTxs: sender -> txs of the sender, for eviction
+	senderTxs map[string][]*priorityTx
+	// arrival order of the next tx
+	nextSeq uint64
+
+	// Limits of the txs of every sender.
+	lanes *senderLanes
+
+	// Keep a cache of already-seen txs.
+	// This reduces the pressure on the proxyApp.
+	cache txCache
+
+	// A log of mempool txs
+	wal *auto.AutoFile
+
+	logger log.Logger
+
+	metrics  *Metrics
+	eventBus types.MempoolEventPublisher
+}
+
+var _ Mempool = &PriorityMempool{}
+
+// PriorityMempoolOption sets an optional parameter on the mempool.
+type PriorityMempoolOption func(*PriorityMempool)
+
+// NewPriorityMempool returns a new priority mempool with the given
+// configuration and connection to an application.
+func NewPriorityMempool(
+	config *cfg.MempoolConfig,
+	proxyAppConn proxy.AppConnMempool,
+	height int64,
+	options ...PriorityMempoolOption,
+) *PriorityMempool {
+	mempool := &PriorityMempool{
+		config:       config,
+		proxyAppConn: proxyAppConn,
+		txs:          clist.New(),
+		height:       height,
+		txsMap:       make(map[[sha256.Size]byte]*priorityTx),
+		senderTxs:    make(map[string][]*priorityTx),
+		lanes:        newSenderLanes(config.MaxTxsPerSender, config.MaxTxsBytesPerSender),
+		logger:       log.NewNopLogger(),
+		metrics:      NopMetrics(),
+		eventBus:     types.NopEventBus{},
+	}
+	if config.CacheSize > 0 {
+		mempool.cache = newMapTxCache(config.CacheSize)
+	} else {
+		mempool.cache = nopTxCache{}
+	}
+	proxyAppConn.SetResponseCallback(mempool.globalCb)
+	for _, option := range options {
+		option(mempool)
+	}
+	return mempool
+}
+
+// NOTE: not thread safe - should only be called once, on startup
+func (mem *PriorityMempool) EnableTxsAvailable() {
+	mem.txsAvailable = make(chan struct{}, 1)
+}
+
+// SetLogger sets the Logger.
+func (mem *PriorityMempool) SetLogger(l log.Logger) {
+	mem.logger = l
+}
+
+// WithPriorityPreCheck sets a filter for the mempool to reject a tx if f(tx)
+// returns false. This is ran before CheckTx.
+func WithPriorityPreCheck(f PreCheckFunc) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.preCheck = f }
+}
+
+// WithPriorityPostCheck sets a filter for the mempool to reject a tx if f(tx)
+// returns false. This is ran after CheckTx.
+func WithPriorityPostCheck(f PostCheckFunc) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.postCheck = f }
+}
+
+// WithPriorityMetrics sets the metrics.
+func WithPriorityMetrics(metrics *Metrics) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.metrics = metrics }
+}
+
+// WithPriorityEventBus sets the event bus the expired txs are published to.
+func WithPriorityEventBus(eventBus types.MempoolEventPublisher) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.eventBus = eventBus }
+}
+
+// InitWAL runs CheckTx on the txs of the WAL left by the previous run of the
+// node before opening it.
+// *panics* if can't create directory or open file.
+// *not thread safe*
+func (mem *PriorityMempool) InitWAL() {
+	walDir := mem.config.WalDir()
+	err := cmn.EnsureDir(walDir, 0700)
+	if err != nil {
+		panic(errors.Wrap(err, "Error ensuring WAL dir"))
+	}
+	af, err := replayWAL(mem, walDir+"/wal", mem.config, mem.logger)
+	if err != nil {
+		panic(errors.Wrap(err, "Error opening WAL file"))
+	}
+	mem.wal = af
+}
+
+func (mem *PriorityMempool) CloseWAL() {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	if err := mem.wal.Close(); err != nil {
+		mem.logger.Error("Error closing WAL", "err", err)
+	}
+	mem.wal = nil
+}
+
+func (mem *PriorityMempool) Lock() {
+	mem.proxyMtx.Lock()
+}
+
+func (mem *PriorityMempool) Unlock() {
+	mem.proxyMtx.Unlock()
+}
+
+func (mem *PriorityMempool) Size() int {
+	return mem.txs.Len()
+}
+
+func (mem *PriorityMempool) TxsBytes() int64 {
+	return atomic.LoadInt64(&mem.txsBytes)
+}
+
+func (mem *PriorityMempool) FlushAppConn() error {
+	return mem.proxyAppConn.FlushSync()
+}
+
+func (mem *PriorityMempool) Flush() {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	mem.cache.Reset()
+
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		mem.txs.Remove(e)
+		e.DetachPrev()
+	}
+
+	mem.queue = nil
+	mem.txsMap = make(map[[sha256.Size]byte]*priorityTx)
+	mem.senderTxs = make(map[string][]*priorityTx)
+	mem.lanes.Reset()
+	_ = atomic.SwapInt64(&mem.txsBytes, 0)
+	writeWAL(mem.wal, mem.logger, walFlushRecord, nil)
+}
+
+// TxsFront returns the first transaction in the list of transactions in
+// order of arrival for peer goroutines to call .NextWait() on.
+func (mem *PriorityMempool) TxsFront() *clist.CElement {
+	return mem.txs.Front()
+}
+
+// TxsWaitChan returns a channel to wait on transactions. It will be closed
+// once the mempool is not empty.
+func (mem *PriorityMempool) TxsWaitChan() <-chan struct{} {
+	return mem.txs.WaitChan()
+}
+
+// TxByKey returns the tx of the given key, if it is in the mempool.
+func (mem *PriorityMempool) TxByKey(key [sha256.Size]byte) (types.Tx, bool) {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+	if ptx, ok := mem.txsMap[key]; ok {
+		return ptx.memTx.tx, true
+	}
+	return nil, false
+}
+
+// SeenTx returns true if the tx of the given key is in the mempool or in the
+// cache. A tx still in the mempool records the peer as one of its senders, so
+// that it is not sent back to the peer.
+func (mem *PriorityMempool) SeenTx(key [sha256.Size]byte, senderID uint16) bool {
+	mem.mtx.Lock()
+	ptx, ok := mem.txsMap[key]
+	mem.mtx.Unlock()
+	if ok {
+		ptx.memTx.senders.LoadOrStore(senderID, true)
+		return true
+	}
+	return mem.cache.Has(key)
+}
+
+// CheckTx does not reject transactions when the mempool is full, unless they
+// are larger than the mempool. Whether a transaction can evict others is
+// decided once the application returned its priority.
+//
+// It blocks if we're waiting on Update() or Reap().
+// cb: A callback from the CheckTx command, called from another goroutine.
+// CONTRACT: Either cb will get called, or err returned.
+func (mem *PriorityMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo TxInfo) (err error) {
+	mem.proxyMtx.Lock()
+	// use defer to unlock mutex because application (*local client*) might panic
+	defer mem.proxyMtx.Unlock()
+
+	txSize := len(tx)
+	if mem.config.Size < 1 || int64(txSize) > mem.config.MaxTxsBytes {
+		return ErrMempoolIsFull{
+			mem.Size(), mem.config.Size,
+			mem.TxsBytes(), mem.config.MaxTxsBytes}
+	}
+
+	// The size of the corresponding amino-encoded TxMessage
+	// can't be larger than the maxMsgSize, otherwise we can't
+	// relay it to peers.
+	if txSize > mem.config.MaxTxBytes {
+		return ErrTxTooLarge{mem.config.MaxTxBytes, txSize}
+	}
+
+	if mem.preCheck != nil {
+		if err := mem.preCheck(tx); err != nil {
+			return ErrPreCheck{err}
+		}
+	}
+
+	// CACHE
+	if !mem.cache.Push(tx) {
+		// Record a new sender for a tx we've already seen, if it is still in
+		// the mempool.
+		mem.mtx.Lock()
+		if ptx, ok := mem.txsMap[txKey(tx)]; ok {
+			ptx.memTx.senders.LoadOrStore(txInfo.SenderID, true)
+		}
+		mem.mtx.Unlock()
+
+		return ErrTxInCache
+	}
+	// END CACHE
+
+	// WAL
+	writeWAL(mem.wal, mem.logger, walTxRecord, tx)
+	// END WAL
+
+	// NOTE: proxyAppConn may error if tx buffer is full
+	if err = mem.proxyAppConn.Error(); err != nil {
+		return err
+	}
+
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
+
+	return nil
+}
+
+// Global callback that will be called after every ABCI response.
+// When rechecking, the recheck callback happens here. See
+// CListMempool.globalCb.
+func (mem *PriorityMempool) globalCb(req *abci.Request, res *abci.Response) {
+	if len(mem.recheckQueue) == 0 {
+		return
+	}
+
+	mem.metrics.RecheckTimes.Add(1)
+	mem.resCbRecheck(req, res)
+
+	// update metrics
+	mem.metrics.Size.Set(float64(mem.Size()))
+}
+
+// Request specific callback that should be set on individual reqRes objects
+// to record the peer who sent us the tx. See CListMempool.reqResCb.
+func (mem *PriorityMempool) reqResCb(
+	tx []byte,
+	peerID uint16,
+	peerP2PID p2p.ID,
+	externalCb func(*abci.Response),
+) func(res *abci.Response) {
+	return func(res *abci.Response) {
+		if len(mem.recheckQueue) > 0 {
+			// this should never happen
+			panic("recheck queue is not empty in reqResCb")
+		}
+
+		mem.resCbFirstTime(tx, peerID, peerP2PID, res)
+
+		// update metrics
+		mem.metrics.Size.Set(float64(mem.Size()))
+
+		// passed in by the caller of CheckTx, eg. the RPC
+		if externalCb != nil {
+			externalCb(res)
+		}
+	}
+}
+
+// addTx adds the tx to the mempool, evicting txs of a lower priority if the
+// mempool is full. A tx of a sender is evicted along with the txs of the
+// sender of higher nonces, which can't be included without it. It returns an
+// error if the lane of the sender of the tx is full or if the tx does not fit
+// into the mempool.
+// It is called from resCbFirstTime if the tx is valid.
+func (mem *PriorityMempool) addTx(memTx *mempoolTx) error {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	if err := mem.lanes.Add(memTx); err != nil {
+		return err
+	}
+
+	var (
+		txSize   = int64(len(memTx.tx))
+		memSize  = mem.Size()
+		txsBytes = mem.TxsBytes()
+		evicted  []*priorityTx
+		skipped  []*priorityTx
+	)
+	for memSize >= mem.config.Size || txsBytes+txSize > mem.config.MaxTxsBytes {
+		if mem.queue.Len() == 0 || mem.queue[0].memTx.priority >= memTx.priority {
+			// Not enough txs of a lower priority, leave the mempool as it was.
+			for _, ptx := range append(evicted, skipped...) {
+				heap.Push(&mem.queue, ptx)
+			}
+			mem.lanes.Remove(memTx)
+			return ErrMempoolIsFull{
+				mem.Size(), mem.config.Size,
+				mem.TxsBytes(), mem.config.MaxTxsBytes}
+		}
+		// A tx is evicted along with the txs of its sender depending on it, so
+		// it is skipped if the new tx depends on it or if one of them has a
+		// priority which is not lower than the one of the new tx.
+		ptx := heap.Pop(&mem.queue).(*priorityTx)
+		dependents := mem.dependentTxs(ptx)
+		if dependsOn(memTx, ptx.memTx) || outranks(dependents, memTx) {
+			skipped = append(skipped, ptx)
+			continue
+		}
+		for _, next := range append([]*priorityTx{ptx}, dependents...) {
+			if next.index >= 0 {
+				heap.Remove(&mem.queue, next.index)
+			}
+			evicted = append(evicted, next)
+			memSize--
+			txsBytes -= int64(len(next.memTx.tx))
+		}
+	}
+	for _, ptx := range skipped {
+		heap.Push(&mem.queue, ptx)
+	}
+	for _, ptx := range evicted {
+		mem.logger.Info("Evicted transaction",
+			"tx", txID(ptx.memTx.tx),
+			"priority", ptx.memTx.priority,
+			"newTx", txID(memTx.tx),
+			"newPriority", memTx.priority,
+		)
+		mem.metrics.EvictedTxs.Add(1)
+		// NOTE: we remove tx from the cache so it can be resubmitted later
+		mem.removeTx(ptx, true)
+	}
+
+	ptx := &priorityTx{
+		memTx: memTx,
+		elem:  mem.txs.PushBack(memTx),
+		seq:   mem.nextSeq,
+	}
+	mem.nextSeq++
+	heap.Push(&mem.queue, ptx)
+	mem.txsMap[txKey(memTx.tx)] = ptx
+	if memTx.sender != "" {
+		mem.senderTxs[memTx.sender] = append(mem.senderTxs[memTx.sender], ptx)
+	}
+	atomic.AddInt64(&mem.txsBytes, txSize)
+	mem.metrics.TxSizeBytes.Observe(float64(txSize))
+	return nil
+}
+
+// dependsOn returns true if the tx can only be included after the other one,
+// i.e. if it is a tx of the same sender of a higher nonce.
+func dependsOn(memTx, other *mempoolTx) bool {
+	return memTx.sender != "" && memTx.sender == other.sender && memTx.nonce > other.nonce
+}
+
+// dependentTxs returns the txs in the priority queue which depend on the given
+// one. mtx must be held.
+func (mem *PriorityMempool) dependentTxs(ptx *priorityTx) []*priorityTx {
+	var dependents []*priorityTx
+	for _, next := range mem.senderTxs[ptx.memTx.sender] {
+		if next.index >= 0 && dependsOn(next.memTx, ptx.memTx) {
+			dependents = append(dependents, next)
+		}
+	}
+	return dependents
+}
+
+// outranks returns true if one of the txs has a priority which is not lower
+// than the one of memTx.
+func outranks(ptxs []*priorityTx, memTx *mempoolTx) bool {
+	for _, ptx := range ptxs {
+		if ptx.memTx.priority >= memTx.priority {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTx removes the tx from the list, the map and, unless it was popped
+// already, the priority queue. mtx must be held.
+func (mem *PriorityMempool) removeTx(ptx *priorityTx, removeFromCache bool) {
+	mem.txs.Remove(ptx.elem)
+	ptx.elem.DetachPrev()
+	if ptx.index >= 0 {
+		heap.Remove(&mem.queue, ptx.index)
+	}
+	key := txKey(ptx.memTx.tx)
+	delete(mem.txsMap, key)
+	mem.removeSenderTx(ptx)
+	mem.lanes.Remove(ptx.memTx)
+	atomic.AddInt64(&mem.txsBytes, int64(-len(ptx.memTx.tx)))
+	writeWAL(mem.wal, mem.logger, walRemovedTxRecord, key[:])
+
+	if removeFromCache {
+		mem.cache.Remove(ptx.memTx.tx)
+	}
+}
+
+// removeSenderTx removes the tx from the txs of its sender. mtx must be held.
+func (mem *PriorityMempool) removeSenderTx(ptx *priorityTx) {
+	sender := ptx.memTx.sender
+	if sender == "" {
+		return
+	}
+	txs := mem.senderTxs[sender]
+	for i := range txs {
+		if txs[i] == ptx {
+			txs = append(txs[:i], txs[i+1:]...)
+			break
+		}
+	}
+	if len(txs) == 0 {
+		delete(mem.senderTxs, sender)
+		return
+	}
+	mem.senderTxs[sender] = txs
+}
+
+// callback, which is called after the app checked the tx for the first time.
+//
+// The case where the app checks the tx for the second and subsequent times is
+// handled by the resCbRecheck callback.
+func (mem *PriorityMempool) resCbFirstTime(
+	tx []byte,
+	peerID uint16,
+	peerP2PID p2p.ID,
+	res *abci.Response,
+) {
+	switch r := res.Value.(type) {
+	case *abci.Response_CheckTx:
+		var postCheckErr error
+		if mem.postCheck != nil {
+			postCheckErr = mem.postCheck(tx, r.CheckTx)
+		}
+		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
+			memTx := &mempoolTx{
+				height:    mem.height,
+				gasWanted: r.CheckTx.GasWanted,
+				priority:  r.CheckTx.Priority,
+				sender:    r.CheckTx.Sender,
+				nonce:     r.CheckTx.Nonce,
+				timestamp: time.Now(),
+				tx:        tx,
+			}
+			memTx.senders.Store(peerID, true)
+			if err := mem.addTx(memTx); err != nil {
+				mem.logger.Info("Rejected transaction",
+					"tx", txID(tx), "peerID", peerP2PID, "priority", memTx.priority, "err", err)
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (it might fit later)
+				mem.cache.Remove(tx)
+				return
+			}
+			mem.logger.Info("Added good transaction",
+				"tx", txID(tx),
+				"res", r,
+				"height", memTx.height,
+				"priority", memTx.priority,
+				"total", mem.Size(),
+			)
+			mem.notifyTxsAvailable()
+		} else {
+			// ignore bad transaction
+			mem.logger.Info("Rejected bad transaction",
+				"tx", txID(tx), "peerID", peerP2PID, "res", r, "err", postCheckErr)
+			mem.metrics.FailedTxs.Add(1)
+			// remove from cache (it might be good later)
+			mem.cache.Remove(tx)
+		}
+	default:
+		// ignore other messages
+	}
+}
+
+// callback, which is called after the app rechecked the tx. The priority of
+// the tx is updated with the one of the response.
+//
+// The case where the app checks the tx for the first time is handled by the
+// resCbFirstTime callback.
+func (mem *PriorityMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
+	switch r := res.Value.(type) {
+	case *abci.Response_CheckTx:
+		tx := req.GetCheckTx().Tx
+		memTx := mem.recheckQueue[0]
+		if !bytes.Equal(tx, memTx.tx) {
+			panic(fmt.Sprintf(
+				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
+				memTx.tx,
+				tx))
+		}
+		var postCheckErr error
+		if mem.postCheck != nil {
+			postCheckErr = mem.postCheck(tx, r.CheckTx)
+		}
+		mem.mtx.Lock()
+		if ptx, ok := mem.txsMap[txKey(tx)]; ok {
+			if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
+				if memTx.priority != r.CheckTx.Priority {
+					memTx.priority = r.CheckTx.Priority
+					heap.Fix(&mem.queue, ptx.index)
+				}
+			} else {
+				// Tx became invalidated due to newly committed block.
+				mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", r, "err", postCheckErr)
+				// NOTE: we remove tx from the cache because it might be good later
+				mem.removeTx(ptx, true)
+			}
+		}
+		mem.mtx.Unlock()
+		mem.recheckQueue = mem.recheckQueue[1:]
+		if len(mem.recheckQueue) == 0 {
+			// Done!
+			mem.recheckQueue = nil
+			atomic.StoreInt32(&mem.rechecking, 0)
+			mem.logger.Info("Done rechecking txs")
+
+			// incase the recheck removed all txs
+			if mem.Size() > 0 {
+				mem.notifyTxsAvailable()
+			}
+		}
+	default:
+		// ignore other messages
+	}
+}
+
+func (mem *PriorityMempool) TxsAvailable() <-chan struct{} {
+	return mem.txsAvailable
+}
+
+func (mem *PriorityMempool) notifyTxsAvailable() {
+	if mem.Size() == 0 {
+		panic("notified txs available but mempool is empty!")
+	}
+	if mem.txsAvailable != nil && !mem.notifiedTxsAvailable {
+		// channel cap is 1, so this will send once
+		mem.notifiedTxsAvailable = true
+		select {
+		case mem.txsAvailable <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReapMaxBytesMaxGas reaps the txs of the highest priority first. See
+// Mempool.ReapMaxBytesMaxGas.
+func (mem *PriorityMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	var totalBytes int64
+	var totalGas int64
+	memTxs := mem.txsByPriority()
+	txs := make([]types.Tx, 0, len(memTxs))
+	for _, memTx := range memTxs {
+		// Check total size requirement
+		aminoOverhead := types.ComputeAminoOverhead(memTx.tx, 1)
+		if maxBytes > -1 && totalBytes+int64(len(memTx.tx))+aminoOverhead > maxBytes {
+			return txs
+		}
+		totalBytes += int64(len(memTx.tx)) + aminoOverhead
+		// Check total gas requirement.
+		// If maxGas is negative, skip this check.
+		newTotalGas := totalGas + memTx.gasWanted
+		if maxGas > -1 && newTotalGas > maxGas {
+			return txs
+		}
+		totalGas = newTotalGas
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
+// ReapMaxTxs reaps the txs of the highest priority first. See
+// Mempool.ReapMaxTxs.
+func (mem *PriorityMempool) ReapMaxTxs(max int) types.Txs {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	memTxs := mem.txsByPriority()
+	if max < 0 || max > len(memTxs) {
+		max = len(memTxs)
+	}
+	txs := make([]types.Tx, 0, max)
+	for _, memTx := range memTxs[:max] {
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
+// txsByPriority returns the txs in order of decreasing priority, and in order
+// of arrival among the txs of the same priority, except for the txs of every
+// sender, which are in order of nonce.
+func (mem *PriorityMempool) txsByPriority() []*mempoolTx {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	ptxs := make([]*priorityTx, len(mem.queue))
+	copy(ptxs, mem.queue)
+	sort.Slice(ptxs, func(i, j int) bool {
+		if ptxs[i].memTx.priority != ptxs[j].memTx.priority {
+			return ptxs[i].memTx.priority > ptxs[j].memTx.priority
+		}
+		return ptxs[i].seq < ptxs[j].seq
+	})
+	memTxs := make([]*mempoolTx, len(ptxs))
+	for i, ptx := range ptxs {
+		memTxs[i] = ptx.memTx
+	}
+	return orderByNonce(memTxs)
+}
+
+func (mem *PriorityMempool) Update(
+	height int64,
+	txs types.Txs,
+	deliverTxResponses []*abci.ResponseDeliverTx,
+	preCheck PreCheckFunc,
+	postCheck PostCheckFunc,
+) error {
+	// Set height
+	mem.height = height
+	mem.notifiedTxsAvailable = false
+
+	if preCheck != nil {
+		mem.preCheck = preCheck
+	}
+	if postCheck != nil {
+		mem.postCheck = postCheck
+	}
+
+	mem.mtx.Lock()
+	for i, tx := range txs {
+		if deliverTxResponses[i].Code == abci.CodeTypeOK {
+			// Add valid committed tx to the cache (if missing).
+			_ = mem.cache.Push(tx)
+		} else {
+			// Allow invalid transactions to be resubmitted.
+			mem.cache.Remove(tx)
+		}
+
+		// Remove committed tx from the mempool.
+		if ptx, ok := mem.txsMap[txKey(tx)]; ok {
+			mem.removeTx(ptx, false)
+		}
+	}
+	mem.purgeExpiredTxs(height)
+	mem.mtx.Unlock()
+
+	// Either recheck non-committed txs to see if they became invalid
+	// or just notify there're some txs left.
+	if mem.Size() > 0 {
+		if mem.config.Recheck {
+			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
+			mem.recheckTxs()
+		} else {
+			mem.notifyTxsAvailable()
+		}
+	}
+
+	// Update metrics
+	mem.metrics.Size.Set(float64(mem.Size()))
+
+	return nil
+}
+
+// purgeExpiredTxs removes the txs which have been in the mempool for longer
+// than the TTL of the config at the given height. mtx must be held.
+func (mem *PriorityMempool) purgeExpiredTxs(height int64) {
+	if mem.config.TTLNumBlocks == 0 && mem.config.TTLDuration == 0 {
+		return
+	}
+
+	now := time.Now()
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		if !memTx.isExpired(height, now, mem.config) {
+			continue
+		}
+		// NOTE: we remove tx from the cache so it can be resubmitted
+		mem.removeTx(mem.txsMap[txKey(memTx.tx)], true)
+		mem.logger.Info("Expired transaction", "tx", txID(memTx.tx), "height", memTx.Height())
+		mem.metrics.ExpiredTxs.Add(1)
+		err := mem.eventBus.PublishEventExpiredTx(types.EventDataExpiredTx{Height: height, Tx: memTx.tx})
+		if err != nil {
+			mem.logger.Error("Error publishing expired tx", "err", err)
+		}
+	}
+}
+
+func (mem *PriorityMempool) recheckTxs() {
+	if mem.Size() == 0 {
+		panic("recheckTxs is called, but the mempool is empty")
+	}
+
+	atomic.StoreInt32(&mem.rechecking, 1)
+	// The txs of a sender are rechecked in order of nonce, so that the app
+	// does not reject the follow-ups of a tx checked after them.
+	memTxs := make([]*mempoolTx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTxs = append(memTxs, e.Value.(*mempoolTx))
+	}
+	mem.recheckQueue = orderByNonce(memTxs)
+
+	// Push txs to proxyAppConn
+	// NOTE: globalCb may be called concurrently.
+	for _, memTx := range mem.recheckQueue {
+		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+			Tx:   memTx.tx,
+			Type: abci.CheckTxType_Recheck,
+		})
+	}
+
+	mem.proxyAppConn.FlushAsync()
+}
+
+//--------------------------------------------------------------------------------
+
+// priorityTx is a tx of the PriorityMempool.
+type priorityTx struct {
+	memTx *mempoolTx
+	elem  *clist.CElement // element of the tx in the list of txs
+	seq   uint64          // order of arrival
+	index int             // index in the priority queue, -1 once removed
+}
+
+// priorityTxQueue implements heap.Interface, with the tx to evict first, the
+// last arrived of the txs of the lowest priority, on top.
+type priorityTxQueue []*priorityTx
+
+var _ heap.Interface = (*priorityTxQueue)(nil)
+
+func (pq priorityTxQueue) Len() int { return len(pq) }
+
+func (pq priorityTxQueue) Less(i, j int) bool {
+	if pq[i].memTx.priority != pq[j].memTx.priority {
+		return pq[i].memTx.priority < pq[j].memTx.priority
+	}
+	return pq[i].seq > pq[j].seq
+}
+
+func (pq priorityTxQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityTxQueue) Push(x interface{}) {
+	ptx := x.(*priorityTx)
+	ptx.index = len(*pq)
+	*pq = append(*pq, ptx)
+}
+
+func (pq *priorityTxQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	ptx := old[n-1]
+	old[n-1] = nil
+	ptx.index = -1
+	*pq = old[:n-1]
+	return ptx
+}
@@ -0,0 +1,176 @@
+package mempool
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// priorityApp accepts every tx with the priority of its first byte, unless
+// the priority of the tx was changed with setPriority.
+type priorityApp struct {
+	abci.BaseApplication
+
+	mtx        sync.Mutex
+	priorities map[string]int64
+}
+
+func newPriorityApp() *priorityApp {
+	return &priorityApp{priorities: make(map[string]int64)}
+}
+
+func (app *priorityApp) setPriority(tx types.Tx, priority int64) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	app.priorities[string(tx)] = priority
+}
+
+func (app *priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	priority, ok := app.priorities[string(req.Tx)]
+	if !ok {
+		priority = int64(req.Tx[0])
+	}
+	return abci.ResponseCheckTx{Code: abci.CodeTypeOK, GasWanted: 1, Priority: priority}
+}
+
+func newPriorityMempoolWithApp(app abci.Application, config *cfg.Config) (*PriorityMempool, cleanupFunc) {
+	appConnMem, _ := proxy.NewLocalClientCreator(app).NewABCIClient()
+	appConnMem.SetLogger(log.TestingLogger().With("module", "abci-client", "connection", "mempool"))
+	err := appConnMem.Start()
+	if err != nil {
+		panic(err)
+	}
+	mempool := NewPriorityMempool(config.Mempool, appConnMem, 0)
+	mempool.SetLogger(log.TestingLogger())
+	return mempool, func() { os.RemoveAll(config.RootDir) }
+}
+
+// priorityTxs returns txs of the given priorities, padded to size bytes.
+func priorityTxs(size int, priorities ...byte) types.Txs {
+	txs := make(types.Txs, len(priorities))
+	for i, priority := range priorities {
+		txs[i] = make([]byte, size)
+		txs[i][0] = priority
+		txs[i][1] = byte(i)
+	}
+	return txs
+}
+
+func checkPriorityTxs(t *testing.T, mempool Mempool, txs types.Txs) {
+	for i, tx := range txs {
+		require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}), "tx #%d", i)
+	}
+}
+
+func TestPriorityMempoolReap(t *testing.T) {
+	app := newPriorityApp()
+	mempool, cleanup := newPriorityMempoolWithApp(app, cfg.ResetTestRoot("mempool_test"))
+	defer cleanup()
+
+	txs := priorityTxs(20, 1, 3, 2, 3)
+	checkPriorityTxs(t, mempool, txs)
+
+	// txs of the same priority are reaped in order of arrival
+	byPriority := types.Txs{txs[1], txs[3], txs[2], txs[0]}
+	assert.Equal(t, byPriority, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, byPriority[:2], mempool.ReapMaxTxs(2))
+	assert.Equal(t, byPriority, mempool.ReapMaxBytesMaxGas(-1, -1))
+	assert.Equal(t, byPriority[:3], mempool.ReapMaxBytesMaxGas(-1, 3))
+	assert.Equal(t, byPriority[:1], mempool.ReapMaxBytesMaxGas(2*21-1, -1))
+
+	// txs are still gossiped in order of arrival
+	i := 0
+	for e := mempool.TxsFront(); e != nil; e = e.Next() {
+		assert.Equal(t, txs[i], e.Value.(*mempoolTx).tx)
+		i++
+	}
+	assert.Equal(t, len(txs), i)
+
+	// the priority returned on recheck replaces the one of the first check
+	app.setPriority(txs[0], 4)
+	require.NoError(t, mempool.Update(1, txs[1:2], abciResponses(1, abci.CodeTypeOK), nil, nil))
+	assert.Equal(t, types.Txs{txs[0], txs[3], txs[2]}, mempool.ReapMaxTxs(-1))
+}
+
+func TestPriorityMempoolEvictByCount(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.Size = 3
+	mempool, cleanup := newPriorityMempoolWithApp(newPriorityApp(), config)
+	defer cleanup()
+
+	txs := priorityTxs(20, 1, 2, 2)
+	checkPriorityTxs(t, mempool, txs)
+	require.Equal(t, 3, mempool.Size())
+
+	// a tx of a higher priority evicts the one of the lowest
+	higher := priorityTxs(20, 3)[0]
+	checkPriorityTxs(t, mempool, types.Txs{higher})
+	assert.Equal(t, types.Txs{higher, txs[1], txs[2]}, mempool.ReapMaxTxs(-1))
+
+	// a tx of the lowest priority is rejected, and can be submitted again
+	lower := priorityTxs(20, 2)[0]
+	lower[1] = 0xff
+	checkPriorityTxs(t, mempool, types.Txs{lower, lower})
+	assert.Equal(t, types.Txs{higher, txs[1], txs[2]}, mempool.ReapMaxTxs(-1))
+
+	// the last arrived of the txs of the lowest priority is evicted first,
+	// and can be submitted again
+	highest := priorityTxs(20, 5)[0]
+	checkPriorityTxs(t, mempool, types.Txs{highest})
+	assert.Equal(t, types.Txs{highest, higher, txs[1]}, mempool.ReapMaxTxs(-1))
+	assert.NoError(t, mempool.CheckTx(txs[2], nil, TxInfo{}))
+	assert.Equal(t, 3, mempool.Size())
+}
+
+func TestPriorityMempoolEvictByBytes(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.MaxTxsBytes = 60
+	mempool, cleanup := newPriorityMempoolWithApp(newPriorityApp(), config)
+	defer cleanup()
+
+	txs := priorityTxs(20, 2, 1, 1)
+	checkPriorityTxs(t, mempool, txs)
+
+	// a larger tx evicts as many txs of a lower priority as needed
+	large := priorityTxs(40, 3)[0]
+	checkPriorityTxs(t, mempool, types.Txs{large})
+	assert.Equal(t, types.Txs{large, txs[0]}, mempool.ReapMaxTxs(-1))
+	assert.EqualValues(t, 60, mempool.TxsBytes())
+
+	// but does not evict any if there are not enough of them
+	larger := priorityTxs(50, 3)[0]
+	checkPriorityTxs(t, mempool, types.Txs{larger})
+	assert.Equal(t, types.Txs{large, txs[0]}, mempool.ReapMaxTxs(-1))
+	assert.EqualValues(t, 60, mempool.TxsBytes())
+
+	// a tx larger than the mempool is rejected at once
+	err := mempool.CheckTx(priorityTxs(61, 9)[0], nil, TxInfo{})
+	assert.IsType(t, ErrMempoolIsFull{}, err)
+}
+
+func TestPriorityMempoolExpiredTxs(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.TTLNumBlocks = 1
+	mempool, cleanup := newPriorityMempoolWithApp(newPriorityApp(), config)
+	defer cleanup()
+
+	txs := priorityTxs(20, 1, 2)
+	checkPriorityTxs(t, mempool, txs[:1])
+	require.NoError(t, mempool.Update(1, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	checkPriorityTxs(t, mempool, txs[1:])
+
+	require.NoError(t, mempool.Update(2, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+	assert.Equal(t, txs[1:], mempool.ReapMaxTxs(-1))
+	assert.EqualValues(t, 20, mempool.TxsBytes())
+}
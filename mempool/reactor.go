@@ -1,6 +1,7 @@
 package mempool
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math"
 	"reflect"
@@ -11,6 +12,7 @@ import (
 
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/clist"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/types"
@@ -19,8 +21,33 @@ import (
 const (
 	MempoolChannel = byte(0x30)
 
+	// MempoolAnnounceChannel is the channel of the tx keys announced to and
+	// requested by the peers which know about it. The txs themselves are
+	// sent on MempoolChannel.
+	MempoolAnnounceChannel = byte(0x31)
+
 	aminoOverheadForTxMessage = 8
 
+	// max number of tx keys in a TxAnnounceMessage or a TxRequestMessage
+	maxTxKeysPerMessage = 100
+	// amino overhead of a TxAnnounceMessage or a TxRequestMessage, and of
+	// each of its keys
+	aminoOverheadForTxKeysMessage = 8
+	aminoOverheadForTxKey         = 2
+
+	// a tx requested from a peer is not requested again from another peer
+	// which announces it, unless the first peer did not send it in time, in
+	// which case it is requested from the next peer which announced it
+	txRequestTimeout = 2 * time.Second
+	// how often the timed out tx requests are sent to other peers
+	txRequestRetryInterval = txRequestTimeout / 4
+
+	// number of tx requests from or to a peer waiting to be sent
+	txRequestsQueueSize = 100
+
+	// key of the queue of tx requests from or to a peer, in the peer data
+	peerTxRequestsKey = "MempoolReactor.txRequests"
+
 	peerCatchupSleepIntervalMS = 100 // If peer is behind, sleep this amount
 
 	// UnknownPeerID is the peer ID to use when running CheckTx when there is
@@ -33,13 +60,45 @@ const (
 // Reactor handles mempool tx broadcasting amongst peers.
 // It maintains a map from peer ID to counter, to prevent gossiping txs to the
 // peers you received it from.
+//
+// Peers which know about MempoolAnnounceChannel are only sent the keys of the
+// txs, and request the txs they have not seen yet. Older peers are sent the
+// txs right away.
 type Reactor struct {
 	p2p.BaseReactor
-	config  *cfg.MempoolConfig
-	mempool *CListMempool
-	ids     *mempoolIDs
+	config   *cfg.MempoolConfig
+	mempool  BroadcastMempool
+	ids      *mempoolIDs
+	requests *txRequests
+}
+
+// BroadcastMempool is a Mempool whose txs can be gossiped by the Reactor.
+type BroadcastMempool interface {
+	Mempool
+
+	// SetLogger sets the Logger.
+	SetLogger(l log.Logger)
+
+	// TxsFront returns the first tx in the list of txs for peer goroutines
+	// to call .NextWait() on. The values of the elements are *mempoolTx.
+	TxsFront() *clist.CElement
+
+	// TxsWaitChan returns a channel which is closed once the mempool is not
+	// empty.
+	TxsWaitChan() <-chan struct{}
+
+	// TxByKey returns the tx of the given key, if it is in the mempool.
+	TxByKey(key [sha256.Size]byte) (types.Tx, bool)
+
+	// SeenTx returns true if the tx of the given key is in the mempool or in
+	// its cache, and records the peer as a sender of the tx if it is in the
+	// mempool.
+	SeenTx(key [sha256.Size]byte, senderID uint16) bool
 }
 
+var _ BroadcastMempool = (*CListMempool)(nil)
+var _ BroadcastMempool = (*PriorityMempool)(nil)
+
 type mempoolIDs struct {
 	mtx       sync.RWMutex
 	peerMap   map[p2p.ID]uint16
@@ -95,6 +154,91 @@ func (ids *mempoolIDs) GetForPeer(peer p2p.Peer) uint16 {
 	return ids.peerMap[peer.ID()]
 }
 
+// txRequests keeps track of the txs requested from peers, so that a tx
+// announced by several peers is only requested from one of them at a time.
+// The other peers which announced a tx are kept, to request the tx from them
+// in turn if it is not received in time: a peer announces a tx only once.
+type txRequests struct {
+	mtx       sync.Mutex
+	requested map[[sha256.Size]byte]*txRequest
+}
+
+// txRequest is the pending request of a tx.
+type txRequest struct {
+	requestedAt time.Time
+	announcers  []p2p.Peer // peers which announced the tx and were not asked for it yet
+}
+
+func newTxRequests() *txRequests {
+	return &txRequests{requested: make(map[[sha256.Size]byte]*txRequest)}
+}
+
+// Add records that the peer announced the tx. It records a request of the tx
+// from the peer at the given time and returns true, unless the tx was
+// requested from another peer less than txRequestTimeout before, in which
+// case the peer is asked for the tx only if that request times out.
+func (reqs *txRequests) Add(key [sha256.Size]byte, peer p2p.Peer, now time.Time) bool {
+	reqs.mtx.Lock()
+	defer reqs.mtx.Unlock()
+
+	req, ok := reqs.requested[key]
+	if !ok {
+		reqs.requested[key] = &txRequest{requestedAt: now}
+		return true
+	}
+	if now.Sub(req.requestedAt) < txRequestTimeout {
+		req.announcers = append(req.announcers, peer)
+		return false
+	}
+	req.requestedAt = now
+	return true
+}
+
+// Remove removes the request of the tx, once it was received.
+func (reqs *txRequests) Remove(key [sha256.Size]byte) {
+	reqs.mtx.Lock()
+	delete(reqs.requested, key)
+	reqs.mtx.Unlock()
+}
+
+// Cancel marks the request of the tx as timed out, once it could not be sent.
+func (reqs *txRequests) Cancel(key [sha256.Size]byte) {
+	reqs.mtx.Lock()
+	if req, ok := reqs.requested[key]; ok {
+		req.requestedAt = time.Time{}
+	}
+	reqs.mtx.Unlock()
+}
+
+// Retry records a new request, at the given time, of every tx whose request
+// timed out, from the next running peer which announced it. It returns the
+// keys of the txs to request by peer. The requests of the txs which no other
+// peer announced are forgotten.
+func (reqs *txRequests) Retry(now time.Time) map[p2p.Peer][][]byte {
+	reqs.mtx.Lock()
+	defer reqs.mtx.Unlock()
+
+	retries := make(map[p2p.Peer][][]byte)
+	for key, req := range reqs.requested {
+		if now.Sub(req.requestedAt) < txRequestTimeout {
+			continue
+		}
+		for len(req.announcers) > 0 && !req.announcers[0].IsRunning() {
+			req.announcers = req.announcers[1:]
+		}
+		if len(req.announcers) == 0 {
+			delete(reqs.requested, key)
+			continue
+		}
+		peer := req.announcers[0]
+		req.announcers = req.announcers[1:]
+		req.requestedAt = now
+		key := key
+		retries[peer] = append(retries[peer], key[:])
+	}
+	return retries
+}
+
 func newMempoolIDs() *mempoolIDs {
 	return &mempoolIDs{
 		peerMap:   make(map[p2p.ID]uint16),
@@ -104,11 +248,12 @@ func newMempoolIDs() *mempoolIDs {
 }
 
 // NewReactor returns a new Reactor with the given config and mempool.
-func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool) *Reactor {
+func NewReactor(config *cfg.MempoolConfig, mempool BroadcastMempool) *Reactor {
 	memR := &Reactor{
-		config:  config,
-		mempool: mempool,
-		ids:     newMempoolIDs(),
+		config:   config,
+		mempool:  mempool,
+		ids:      newMempoolIDs(),
+		requests: newTxRequests(),
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Reactor", memR)
 	return memR
@@ -125,6 +270,7 @@ func (memR *Reactor) OnStart() error {
 	if !memR.config.Broadcast {
 		memR.Logger.Info("Tx broadcasting is disabled")
 	}
+	go memR.retryTxRequestsRoutine()
 	return nil
 }
 
@@ -136,14 +282,30 @@ func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			ID:       MempoolChannel,
 			Priority: 5,
 		},
+		{
+			ID:       MempoolAnnounceChannel,
+			Priority: 5,
+		},
 	}
 }
 
+// InitPeer implements Reactor by creating the queue of the tx requests from
+// or to the peer, which may announce or request txs as soon as it is started.
+func (memR *Reactor) InitPeer(peer p2p.Peer) p2p.Peer {
+	peer.Set(peerTxRequestsKey, make(chan peerTxRequest, txRequestsQueueSize))
+	return peer
+}
+
 // AddPeer implements Reactor.
-// It starts a broadcast routine ensuring all txs are forwarded to the given peer.
+// It starts a broadcast routine ensuring all txs are forwarded to the given
+// peer, or announced to it if it knows about MempoolAnnounceChannel.
 func (memR *Reactor) AddPeer(peer p2p.Peer) {
 	memR.ids.ReserveForPeer(peer)
-	go memR.broadcastTxRoutine(peer)
+	if peerHasChannel(peer, MempoolAnnounceChannel) {
+		go memR.announceTxRoutine(peer)
+	} else {
+		go memR.broadcastTxRoutine(peer)
+	}
 }
 
 // RemovePeer implements Reactor.
@@ -155,7 +317,7 @@ func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 // Receive implements Reactor.
 // It adds any received transactions to the mempool.
 func (memR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
-	msg, err := memR.decodeMsg(msgBytes)
+	msg, err := memR.decodeMsg(chID, msgBytes)
 	if err != nil {
 		memR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
 		memR.Switch.StopPeerForError(src, err)
@@ -169,16 +331,128 @@ func (memR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 		if src != nil {
 			txInfo.SenderP2PID = src.ID()
 		}
+		memR.requests.Remove(txKey(msg.Tx))
 		err := memR.mempool.CheckTx(msg.Tx, nil, txInfo)
 		if err != nil {
 			memR.Logger.Info("Could not check tx", "tx", txID(msg.Tx), "err", err)
 		}
 		// broadcasting happens from go routines per peer
+	case *TxAnnounceMessage:
+		if err := msg.ValidateBasic(); err != nil {
+			memR.Switch.StopPeerForError(src, err)
+			return
+		}
+		memR.requestTxs(src, msg.TxKeys)
+	case *TxRequestMessage:
+		if err := msg.ValidateBasic(); err != nil {
+			memR.Switch.StopPeerForError(src, err)
+			return
+		}
+		if !queueTxRequest(src, peerTxRequest{keys: msg.TxKeys, byPeer: true}) {
+			memR.Logger.Debug("Dropping tx request of peer", "src", src, "numTxs", len(msg.TxKeys))
+		}
 	default:
 		memR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
 }
 
+// requestTxs requests the txs of the given keys which were not seen yet, and
+// which are not requested from another peer already.
+func (memR *Reactor) requestTxs(src p2p.Peer, keys [][]byte) {
+	var (
+		senderID = memR.ids.GetForPeer(src)
+		now      = time.Now()
+		missing  [][]byte
+	)
+	for _, key := range keys {
+		if memR.mempool.SeenTx(toTxKey(key), senderID) || !memR.requests.Add(toTxKey(key), src, now) {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	if !queueTxRequest(src, peerTxRequest{keys: missing}) {
+		memR.cancelTxRequests(missing)
+	}
+}
+
+// cancelTxRequests cancels the requests of the txs of the given keys, to
+// request them from another peer which announced them.
+func (memR *Reactor) cancelTxRequests(keys [][]byte) {
+	for _, key := range keys {
+		memR.requests.Cancel(toTxKey(key))
+	}
+}
+
+// retryTxRequestsRoutine requests the txs which were not received in time
+// from the next peers which announced them.
+func (memR *Reactor) retryTxRequestsRoutine() {
+	ticker := time.NewTicker(txRequestRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			memR.retryTxRequests(now)
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
+// retryTxRequests requests the txs whose requests timed out at the given
+// time from the next peers which announced them.
+func (memR *Reactor) retryTxRequests(now time.Time) {
+	for peer, keys := range memR.requests.Retry(now) {
+		for len(keys) > 0 {
+			n := cmn.MinInt(len(keys), maxTxKeysPerMessage)
+			if !queueTxRequest(peer, peerTxRequest{keys: keys[:n]}) {
+				memR.cancelTxRequests(keys[:n])
+			}
+			keys = keys[n:]
+		}
+	}
+}
+
+// peerTxRequest is a request of txs by a peer, or to a peer, waiting to be
+// handled by the broadcast routine of the peer.
+type peerTxRequest struct {
+	keys   [][]byte
+	byPeer bool // requested by the peer rather than from it
+}
+
+// queueTxRequest queues the request for the broadcast routine of the peer,
+// so that the Receive routine of the peer does not block. It returns false if
+// the queue is full.
+func queueTxRequest(peer p2p.Peer, req peerTxRequest) bool {
+	requests, ok := peer.Get(peerTxRequestsKey).(chan peerTxRequest)
+	if !ok {
+		return false
+	}
+	select {
+	case requests <- req:
+		return true
+	default:
+		return false
+	}
+}
+
+// peerHasChannel returns true if the peer knows about the given channel.
+func peerHasChannel(peer p2p.Peer, chID byte) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	if !ok {
+		return false
+	}
+	for _, ch := range nodeInfo.Channels {
+		if ch == chID {
+			return true
+		}
+	}
+	return false
+}
+
 // PeerState describes the state of a peer.
 type PeerState interface {
 	GetHeight() int64
@@ -254,6 +528,128 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 	}
 }
 
+// Announce the keys of new mempool txs to peer, and handle the tx requests
+// from or to peer.
+func (memR *Reactor) announceTxRoutine(peer p2p.Peer) {
+	var (
+		peerID      = memR.ids.GetForPeer(peer)
+		requests, _ = peer.Get(peerTxRequestsKey).(chan peerTxRequest)
+		next        *clist.CElement
+		announced   *clist.CElement // last tx whose key was added to keys
+		keys        [][]byte
+	)
+
+	if !memR.config.Broadcast {
+		// the txs announced by peer are still requested
+		for {
+			select {
+			case req := <-requests:
+				memR.handleTxRequest(peer, req)
+			case <-peer.Quit():
+				return
+			case <-memR.Quit():
+				return
+			}
+		}
+	}
+
+	for {
+		// In case of both next.NextWaitChan() and peer.Quit() are variable at the same time
+		if !memR.IsRunning() || !peer.IsRunning() {
+			return
+		}
+		// Serve the requests of the peer before announcing more txs.
+		select {
+		case req := <-requests:
+			memR.handleTxRequest(peer, req)
+			continue
+		default:
+		}
+		// This happens because the CElement we were looking at got garbage
+		// collected (removed). That is, .NextWait() returned nil. Go ahead and
+		// start from the beginning.
+		if next == nil {
+			select {
+			case <-memR.mempool.TxsWaitChan(): // Wait until a tx is available
+				if next = memR.mempool.TxsFront(); next == nil {
+					continue
+				}
+			case req := <-requests:
+				memR.handleTxRequest(peer, req)
+				continue
+			case <-peer.Quit():
+				return
+			case <-memR.Quit():
+				return
+			}
+		}
+
+		if next != announced {
+			memTx := next.Value.(*mempoolTx)
+
+			// make sure the peer is up to date
+			peerState, ok := peer.Get(types.PeerStateKey).(PeerState)
+			if !ok || peerState.GetHeight() < memTx.Height()-1 { // Allow for a lag of 1 block
+				time.Sleep(peerCatchupSleepIntervalMS * time.Millisecond)
+				continue
+			}
+
+			// ensure peer hasn't already sent us this tx
+			if _, ok := memTx.senders.Load(peerID); !ok {
+				key := txKey(memTx.tx)
+				keys = append(keys, key[:])
+			}
+			announced = next
+		}
+
+		// Announce the keys in batches, once no more txs are available right
+		// away or the batch is full.
+		if len(keys) > 0 && (next.Next() == nil || len(keys) >= maxTxKeysPerMessage) {
+			msg := &TxAnnounceMessage{TxKeys: keys}
+			if !peer.Send(MempoolAnnounceChannel, cdc.MustMarshalBinaryBare(msg)) {
+				time.Sleep(peerCatchupSleepIntervalMS * time.Millisecond)
+				continue
+			}
+			keys = nil
+		}
+
+		select {
+		case <-next.NextWaitChan():
+			// see the start of the for loop for nil check
+			next = next.Next()
+		case req := <-requests:
+			memR.handleTxRequest(peer, req)
+		case <-peer.Quit():
+			return
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
+// handleTxRequest sends peer the txs it requested which are still in the
+// mempool, or requests txs from it.
+func (memR *Reactor) handleTxRequest(peer p2p.Peer, req peerTxRequest) {
+	if !req.byPeer {
+		msg := &TxRequestMessage{TxKeys: req.keys}
+		if !peer.Send(MempoolAnnounceChannel, cdc.MustMarshalBinaryBare(msg)) {
+			memR.cancelTxRequests(req.keys)
+		}
+		return
+	}
+
+	for _, key := range req.keys {
+		tx, ok := memR.mempool.TxByKey(toTxKey(key))
+		if !ok {
+			continue
+		}
+		msg := &TxMessage{Tx: tx}
+		if !peer.Send(MempoolChannel, cdc.MustMarshalBinaryBare(msg)) {
+			return
+		}
+	}
+}
+
 //-----------------------------------------------------------------------------
 // Messages
 
@@ -263,10 +659,15 @@ type MempoolMessage interface{}
 func RegisterMempoolMessages(cdc *amino.Codec) {
 	cdc.RegisterInterface((*MempoolMessage)(nil), nil)
 	cdc.RegisterConcrete(&TxMessage{}, "tendermint/mempool/TxMessage", nil)
+	cdc.RegisterConcrete(&TxAnnounceMessage{}, "tendermint/mempool/TxAnnounceMessage", nil)
+	cdc.RegisterConcrete(&TxRequestMessage{}, "tendermint/mempool/TxRequestMessage", nil)
 }
 
-func (memR *Reactor) decodeMsg(bz []byte) (msg MempoolMessage, err error) {
+func (memR *Reactor) decodeMsg(chID byte, bz []byte) (msg MempoolMessage, err error) {
 	maxMsgSize := calcMaxMsgSize(memR.config.MaxTxBytes)
+	if chID == MempoolAnnounceChannel {
+		maxMsgSize = maxTxKeysMsgSize
+	}
 	if l := len(bz); l > maxMsgSize {
 		return msg, ErrTxTooLarge{maxMsgSize, l}
 	}
@@ -291,3 +692,60 @@ func (m *TxMessage) String() string {
 func calcMaxMsgSize(maxTxSize int) int {
 	return maxTxSize + aminoOverheadForTxMessage
 }
+
+//-------------------------------------
+
+// max size of a TxAnnounceMessage or a TxRequestMessage
+var maxTxKeysMsgSize = aminoOverheadForTxKeysMessage +
+	maxTxKeysPerMessage*(sha256.Size+aminoOverheadForTxKey)
+
+// TxAnnounceMessage is a MempoolMessage containing the keys of txs the
+// sender has.
+type TxAnnounceMessage struct {
+	TxKeys [][]byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *TxAnnounceMessage) ValidateBasic() error {
+	return validateTxKeys(m.TxKeys)
+}
+
+// String returns a string representation of the TxAnnounceMessage.
+func (m *TxAnnounceMessage) String() string {
+	return fmt.Sprintf("[TxAnnounceMessage %d txs]", len(m.TxKeys))
+}
+
+// TxRequestMessage is a MempoolMessage containing the keys of txs the sender
+// requests.
+type TxRequestMessage struct {
+	TxKeys [][]byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *TxRequestMessage) ValidateBasic() error {
+	return validateTxKeys(m.TxKeys)
+}
+
+// String returns a string representation of the TxRequestMessage.
+func (m *TxRequestMessage) String() string {
+	return fmt.Sprintf("[TxRequestMessage %d txs]", len(m.TxKeys))
+}
+
+// toTxKey converts a tx key of a TxAnnounceMessage or a TxRequestMessage,
+// once validated.
+func toTxKey(bz []byte) (key [sha256.Size]byte) {
+	copy(key[:], bz)
+	return key
+}
+
+func validateTxKeys(keys [][]byte) error {
+	if len(keys) > maxTxKeysPerMessage {
+		return fmt.Errorf("too many tx keys (%d). Max is %d", len(keys), maxTxKeysPerMessage)
+	}
+	for i, key := range keys {
+		if len(key) != sha256.Size {
+			return fmt.Errorf("wrong size of tx key #%d (%d). Expected %d", i, len(key), sha256.Size)
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-kit/kit/log/term"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	cfg "github.com/tendermint/tendermint/config"
@@ -149,6 +150,203 @@ func TestReactorNoBroadcastToSender(t *testing.T) {
 	ensureNoTxs(t, reactors[1], 100*time.Millisecond)
 }
 
+// recordingPeer is a mock peer which knows about the given channels, and
+// records the mempool messages sent to it.
+type recordingPeer struct {
+	*mock.Peer
+	channels  []byte
+	countOnly bool // only count the txs and the bytes sent, without recording messages
+
+	mtx       sync.Mutex
+	msgs      []MempoolMessage
+	numTxs    int // number of txs sent or announced
+	sentBytes int
+}
+
+func newRecordingPeer(channels ...byte) *recordingPeer {
+	peer := &recordingPeer{Peer: mock.NewPeer(nil), channels: channels}
+	peer.Set(types.PeerStateKey, peerState{1})
+	return peer
+}
+
+func (p *recordingPeer) NodeInfo() p2p.NodeInfo {
+	return p2p.DefaultNodeInfo{ID_: p.ID(), Channels: p.channels}
+}
+
+func (p *recordingPeer) Send(chID byte, msgBytes []byte) bool {
+	var msg MempoolMessage
+	cdc.MustUnmarshalBinaryBare(msgBytes, &msg)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if !p.countOnly {
+		p.msgs = append(p.msgs, msg)
+	}
+	p.sentBytes += len(msgBytes)
+	switch msg := msg.(type) {
+	case *TxMessage:
+		p.numTxs++
+	case *TxAnnounceMessage:
+		p.numTxs += len(msg.TxKeys)
+	}
+	return true
+}
+
+func (p *recordingPeer) TrySend(chID byte, msgBytes []byte) bool {
+	return p.Send(chID, msgBytes)
+}
+
+func (p *recordingPeer) messages() []MempoolMessage {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return append([]MempoolMessage{}, p.msgs...)
+}
+
+// waitForMessages waits until count messages were sent to the peer, and
+// returns them.
+func (p *recordingPeer) waitForMessages(count int) []MempoolMessage {
+	for msgs := p.messages(); ; msgs = p.messages() {
+		if len(msgs) >= count {
+			return msgs
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (p *recordingPeer) sent() (numTxs, sentBytes int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.numTxs, p.sentBytes
+}
+
+// waitForNumTxs waits until count txs were sent or announced to every peer.
+func waitForNumTxs(count int, peers ...*recordingPeer) {
+	for _, peer := range peers {
+		for numTxs, _ := peer.sent(); numTxs < count; numTxs, _ = peer.sent() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func newReactorWithPeers(config *cfg.Config, peers ...*recordingPeer) (*Reactor, cleanupFunc) {
+	cc := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
+	memR := NewReactor(config.Mempool, mempool)
+	memR.SetLogger(log.TestingLogger())
+	if err := memR.Start(); err != nil {
+		panic(err)
+	}
+	for _, peer := range peers {
+		memR.AddPeer(memR.InitPeer(peer))
+	}
+	return memR, func() {
+		memR.Stop()
+		cleanup()
+	}
+}
+
+func txKeys(txs types.Txs) [][]byte {
+	keys := make([][]byte, len(txs))
+	for i, tx := range txs {
+		key := txKey(tx)
+		keys[i] = key[:]
+	}
+	return keys
+}
+
+// Ensure the txs are announced to the peers which know about
+// MempoolAnnounceChannel, and sent to the others.
+func TestReactorAnnounceTxs(t *testing.T) {
+	newPeer := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	oldPeer := newRecordingPeer(MempoolChannel)
+	memR, cleanup := newReactorWithPeers(cfg.ResetTestRoot("mempool_test"), newPeer, oldPeer)
+	defer cleanup()
+
+	txs := checkTxs(t, memR.mempool, 10, UnknownPeerID)
+	waitForNumTxs(len(txs), newPeer, oldPeer)
+
+	var announced [][]byte
+	for _, msg := range newPeer.messages() {
+		require.IsType(t, &TxAnnounceMessage{}, msg)
+		announced = append(announced, msg.(*TxAnnounceMessage).TxKeys...)
+	}
+	assert.Equal(t, txKeys(txs), announced)
+
+	var sent types.Txs
+	for _, msg := range oldPeer.messages() {
+		require.IsType(t, &TxMessage{}, msg)
+		sent = append(sent, msg.(*TxMessage).Tx)
+	}
+	assert.Equal(t, txs, sent)
+}
+
+// Ensure only the txs not seen yet are requested, from a single peer at a
+// time, and that the requested txs are sent.
+func TestReactorRequestTxs(t *testing.T) {
+	peer1 := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	peer2 := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	memR, cleanup := newReactorWithPeers(cfg.ResetTestRoot("mempool_test"), peer1, peer2)
+	defer cleanup()
+
+	seen := checkTxs(t, memR.mempool, 1, UnknownPeerID)
+	waitForNumTxs(1, peer1, peer2)
+	missing := types.Txs{[]byte("tx1"), []byte("tx2")}
+
+	announce := &TxAnnounceMessage{TxKeys: txKeys(append(seen, missing...))}
+	memR.Receive(MempoolAnnounceChannel, peer1, cdc.MustMarshalBinaryBare(announce))
+	memR.Receive(MempoolAnnounceChannel, peer2, cdc.MustMarshalBinaryBare(announce))
+	msgs := peer1.waitForMessages(2)
+	assert.Equal(t, &TxRequestMessage{TxKeys: txKeys(missing)}, msgs[1])
+
+	// the tx received from peer1 is announced to peer2 only
+	memR.Receive(MempoolChannel, peer1, cdc.MustMarshalBinaryBare(&TxMessage{Tx: missing[0]}))
+	waitForNumTxs(2, peer2)
+	assert.Len(t, peer1.messages(), 2)
+	assert.Len(t, peer2.messages(), 2)
+
+	// peer2 requests the tx it has not seen
+	request := &TxRequestMessage{TxKeys: txKeys(missing[:1])}
+	memR.Receive(MempoolAnnounceChannel, peer2, cdc.MustMarshalBinaryBare(request))
+	waitForNumTxs(3, peer2)
+	msgs = peer2.messages()
+	assert.Equal(t, &TxMessage{Tx: missing[0]}, msgs[len(msgs)-1])
+}
+
+// Ensure a tx which is not received in time from the peer it was requested
+// from is requested from the next peer which announced it.
+func TestReactorRetryTxRequests(t *testing.T) {
+	peer1 := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	peer2 := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	peer3 := newRecordingPeer(MempoolChannel, MempoolAnnounceChannel)
+	memR, cleanup := newReactorWithPeers(cfg.ResetTestRoot("mempool_test"), peer1, peer2, peer3)
+	defer cleanup()
+
+	missing := types.Txs{[]byte("tx1")}
+	announce := cdc.MustMarshalBinaryBare(&TxAnnounceMessage{TxKeys: txKeys(missing)})
+	memR.Receive(MempoolAnnounceChannel, peer1, announce)
+	memR.Receive(MempoolAnnounceChannel, peer2, announce)
+	memR.Receive(MempoolAnnounceChannel, peer3, announce)
+	request := &TxRequestMessage{TxKeys: txKeys(missing)}
+	assert.Equal(t, []MempoolMessage{request}, peer1.waitForMessages(1))
+
+	// peer1 does not send the tx, so it is requested from peer2, then peer3
+	now := time.Now()
+	memR.retryTxRequests(now)
+	assert.Empty(t, peer2.messages())
+	memR.retryTxRequests(now.Add(txRequestTimeout))
+	assert.Equal(t, []MempoolMessage{request}, peer2.waitForMessages(1))
+	assert.Empty(t, peer3.messages())
+	memR.retryTxRequests(now.Add(2 * txRequestTimeout))
+	assert.Equal(t, []MempoolMessage{request}, peer3.waitForMessages(1))
+
+	// the request is forgotten once no peer is left to ask
+	memR.retryTxRequests(now.Add(3 * txRequestTimeout))
+	memR.requests.mtx.Lock()
+	assert.Empty(t, memR.requests.requested)
+	memR.requests.mtx.Unlock()
+	assert.Len(t, peer1.messages(), 1)
+}
+
 func TestBroadcastTxForPeerStopsWhenPeerStops(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
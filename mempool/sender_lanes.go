@@ -0,0 +1,108 @@
+package mempool
+
+import (
+	"sort"
+	"sync"
+)
+
+// senderLanes keeps track of the txs of every sender in the mempool, i.e. of
+// the txs for which the app returned a Sender in ResponseCheckTx, to limit
+// the number and the size of the txs of a sender.
+type senderLanes struct {
+	mtx         sync.Mutex
+	maxTxs      int   // no limit if 0
+	maxTxsBytes int64 // no limit if 0
+	numTxs      map[string]int
+	txsBytes    map[string]int64
+}
+
+func newSenderLanes(maxTxs int, maxTxsBytes int64) *senderLanes {
+	return &senderLanes{
+		maxTxs:      maxTxs,
+		maxTxsBytes: maxTxsBytes,
+		numTxs:      make(map[string]int),
+		txsBytes:    make(map[string]int64),
+	}
+}
+
+// Add adds the tx to the lane of its sender. It returns ErrSenderIsFull if
+// the lane has no room for the tx.
+func (lanes *senderLanes) Add(memTx *mempoolTx) error {
+	if memTx.sender == "" {
+		return nil
+	}
+
+	lanes.mtx.Lock()
+	defer lanes.mtx.Unlock()
+
+	var (
+		numTxs   = lanes.numTxs[memTx.sender]
+		txsBytes = lanes.txsBytes[memTx.sender]
+	)
+	if (lanes.maxTxs > 0 && numTxs >= lanes.maxTxs) ||
+		(lanes.maxTxsBytes > 0 && txsBytes+int64(len(memTx.tx)) > lanes.maxTxsBytes) {
+		return ErrSenderIsFull{
+			memTx.sender,
+			numTxs, lanes.maxTxs,
+			txsBytes, lanes.maxTxsBytes}
+	}
+	lanes.numTxs[memTx.sender] = numTxs + 1
+	lanes.txsBytes[memTx.sender] = txsBytes + int64(len(memTx.tx))
+	return nil
+}
+
+// Remove removes the tx from the lane of its sender.
+func (lanes *senderLanes) Remove(memTx *mempoolTx) {
+	if memTx.sender == "" {
+		return
+	}
+
+	lanes.mtx.Lock()
+	defer lanes.mtx.Unlock()
+
+	if lanes.numTxs[memTx.sender] <= 1 {
+		delete(lanes.numTxs, memTx.sender)
+		delete(lanes.txsBytes, memTx.sender)
+		return
+	}
+	lanes.numTxs[memTx.sender]--
+	lanes.txsBytes[memTx.sender] -= int64(len(memTx.tx))
+}
+
+// Reset removes all the txs from the lanes.
+func (lanes *senderLanes) Reset() {
+	lanes.mtx.Lock()
+	lanes.numTxs = make(map[string]int)
+	lanes.txsBytes = make(map[string]int64)
+	lanes.mtx.Unlock()
+}
+
+// orderByNonce reorders the txs of every sender by nonce: the txs of a
+// sender keep their places, but the first of them becomes the one of the
+// lowest nonce, and so on. Txs of the same nonce keep their order, and txs
+// without a sender are not moved.
+func orderByNonce(memTxs []*mempoolTx) []*mempoolTx {
+	lanes := make(map[string][]*mempoolTx)
+	for _, memTx := range memTxs {
+		if memTx.sender != "" {
+			lanes[memTx.sender] = append(lanes[memTx.sender], memTx)
+		}
+	}
+	if len(lanes) == 0 {
+		return memTxs
+	}
+	for _, lane := range lanes {
+		sort.SliceStable(lane, func(i, j int) bool { return lane[i].nonce < lane[j].nonce })
+	}
+
+	ordered := make([]*mempoolTx, len(memTxs))
+	for i, memTx := range memTxs {
+		if memTx.sender == "" {
+			ordered[i] = memTx
+			continue
+		}
+		ordered[i] = lanes[memTx.sender][0]
+		lanes[memTx.sender] = lanes[memTx.sender][1:]
+	}
+	return ordered
+}
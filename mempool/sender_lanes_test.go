@@ -0,0 +1,168 @@
+package mempool
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestOrderByNonce(t *testing.T) {
+	tx := func(sender string, nonce uint64) *mempoolTx {
+		return &mempoolTx{sender: sender, nonce: nonce, tx: []byte(fmt.Sprintf("%s/%d", sender, nonce))}
+	}
+	a0, a1, a2, b0, b1, c := tx("a", 0), tx("a", 1), tx("a", 2), tx("b", 0), tx("b", 1), tx("", 0)
+
+	assert.Equal(t, []*mempoolTx{a0, c, a1}, orderByNonce([]*mempoolTx{a1, c, a0}))
+	assert.Equal(t,
+		[]*mempoolTx{b0, a0, c, a1, b1, a2},
+		orderByNonce([]*mempoolTx{b1, a2, c, a0, b0, a1}))
+	assert.Equal(t, []*mempoolTx{c, b0}, orderByNonce([]*mempoolTx{c, b0}))
+}
+
+// nonceApp accepts txs of the form "sender/nonce/priority". It accepts any
+// nonce in the first check, but only the next nonce of the sender on recheck.
+type nonceApp struct {
+	abci.BaseApplication
+
+	mtx       sync.Mutex
+	nextNonce map[string]uint64
+}
+
+func newNonceApp() *nonceApp {
+	return &nonceApp{nextNonce: make(map[string]uint64)}
+}
+
+func (app *nonceApp) resetRecheck() {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+	app.nextNonce = make(map[string]uint64)
+}
+
+func (app *nonceApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	parts := strings.Split(string(req.Tx), "/")
+	nonce, _ := strconv.ParseUint(parts[1], 10, 64)
+	priority, _ := strconv.ParseInt(parts[2], 10, 64)
+	if req.Type == abci.CheckTxType_Recheck {
+		if nonce != app.nextNonce[parts[0]] {
+			return abci.ResponseCheckTx{Code: 1}
+		}
+		app.nextNonce[parts[0]]++
+	}
+	return abci.ResponseCheckTx{
+		Code:      abci.CodeTypeOK,
+		GasWanted: 1,
+		Priority:  priority,
+		Sender:    parts[0],
+		Nonce:     nonce,
+	}
+}
+
+func nonceTx(sender string, nonce uint64, priority int64) types.Tx {
+	return types.Tx(fmt.Sprintf("%s/%d/%d", sender, nonce, priority))
+}
+
+func newNonceMempools(config *cfg.Config) (app *nonceApp, mempools map[string]Mempool) {
+	app = newNonceApp()
+	clistMempool, _ := newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(app), config)
+	priorityMempool, _ := newPriorityMempoolWithApp(app, config)
+	return app, map[string]Mempool{"v0": clistMempool, "v1": priorityMempool}
+}
+
+// Ensure the txs of a sender are reaped and rechecked in order of nonce, even
+// when they arrived out of order.
+func TestMempoolSenderNonceOrder(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	defer os.RemoveAll(config.RootDir)
+	app, mempools := newNonceMempools(config)
+
+	for version, mempool := range mempools {
+		txs := types.Txs{nonceTx("a", 1, 3), nonceTx("b", 0, 1), nonceTx("a", 0, 2)}
+		checkPriorityTxs(t, mempool, txs)
+
+		// the txs of "a" are rechecked in order of nonce
+		app.resetRecheck()
+		require.NoError(t, mempool.Update(1, types.Txs{}, abciResponses(0, abci.CodeTypeOK), nil, nil))
+		require.Equal(t, 3, mempool.Size(), version)
+
+		// the first tx of "a" takes the place of the other one, in order of
+		// arrival or of priority
+		byNonce := types.Txs{txs[2], txs[1], txs[0]}
+		if version == "v1" {
+			byNonce = types.Txs{txs[2], txs[0], txs[1]}
+		}
+		assert.Equal(t, byNonce, mempool.ReapMaxTxs(-1), version)
+		assert.Equal(t, byNonce, mempool.ReapMaxBytesMaxGas(-1, -1), version)
+	}
+}
+
+func TestMempoolSenderLimits(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Mempool.Recheck = false
+	config.Mempool.MaxTxsPerSender = 2
+	config.Mempool.MaxTxsBytesPerSender = 10
+	_, mempools := newNonceMempools(config)
+
+	for version, mempool := range mempools {
+		// the txs of "a" are 5 bytes long, so the third one is over both limits
+		checkPriorityTxs(t, mempool, types.Txs{nonceTx("a", 0, 0), nonceTx("a", 1, 0), nonceTx("a", 2, 0)})
+		assert.Equal(t, 2, mempool.Size(), version)
+
+		// other senders have a lane of their own, limited in size too
+		checkPriorityTxs(t, mempool, types.Txs{nonceTx("b", 0, 0), nonceTx("b", 10, 0)})
+		assert.Equal(t, 3, mempool.Size(), version)
+
+		// the rejected txs can be submitted again once there is room
+		require.NoError(t, mempool.Update(1, types.Txs{nonceTx("a", 0, 0)}, abciResponses(1, abci.CodeTypeOK), nil, nil))
+		checkPriorityTxs(t, mempool, types.Txs{nonceTx("a", 2, 0)})
+		assert.Equal(t, 3, mempool.Size(), version)
+		assert.Equal(t, types.Txs{nonceTx("a", 1, 0), nonceTx("b", 0, 0), nonceTx("a", 2, 0)},
+			mempool.ReapMaxTxs(-1), version)
+	}
+}
+
+// Ensure the priority mempool never evicts a tx of a sender and keeps the txs
+// of the sender of higher nonces, nor evicts a tx of a higher priority.
+func TestPriorityMempoolEvictBySender(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Mempool.Size = 3
+	config.Mempool.Recheck = false
+	mempool, _ := newPriorityMempoolWithApp(newNonceApp(), config)
+
+	txs := types.Txs{nonceTx("a", 0, 1), nonceTx("a", 1, 5), nonceTx("b", 0, 2)}
+	checkPriorityTxs(t, mempool, txs)
+
+	// the tx of the lowest priority is not evicted when a tx depending on it
+	// outranks the new tx
+	checkPriorityTxs(t, mempool, types.Txs{nonceTx("c", 0, 3)})
+	assert.Equal(t, types.Txs{txs[0], nonceTx("c", 0, 3), txs[1]}, mempool.ReapMaxTxs(-1))
+
+	// but takes the txs depending on it when none of them does
+	checkPriorityTxs(t, mempool, types.Txs{nonceTx("e", 0, 6)})
+	assert.Equal(t, types.Txs{nonceTx("e", 0, 6), nonceTx("c", 0, 3)}, mempool.ReapMaxTxs(-1))
+
+	// a tx can't make room by evicting a tx of its sender it depends on
+	checkPriorityTxs(t, mempool, types.Txs{nonceTx("d", 0, 1)})
+	checkPriorityTxs(t, mempool, types.Txs{nonceTx("d", 1, 9)})
+	assert.Equal(t, types.Txs{nonceTx("d", 0, 1), nonceTx("e", 0, 6), nonceTx("d", 1, 9)}, mempool.ReapMaxTxs(-1))
+
+	// and a tx of a priority no higher than the ones of the txs which would be
+	// evicted is rejected
+	checkPriorityTxs(t, mempool, types.Txs{nonceTx("f", 0, 6)})
+	assert.Equal(t, types.Txs{nonceTx("d", 0, 1), nonceTx("e", 0, 6), nonceTx("d", 1, 9)}, mempool.ReapMaxTxs(-1))
+}
@@ -0,0 +1,277 @@
+package mempool
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	cfg "github.com/tendermint/tendermint/config"
+	auto "github.com/tendermint/tendermint/libs/autofile"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// The WAL records every tx which passes the cache in CheckTx, and the key of
+// every tx removed from the mempool, so that the txs still pending survive a
+// restart of the node.
+//
+// Format: 4 bytes CRC sum + 4 bytes length + 1 byte record type + data
+
+const (
+	walTxRecord        = byte(0x01) // data is a tx checked by CheckTx
+	walRemovedTxRecord = byte(0x02) // data is the key of a tx removed from the mempool
+	walFlushRecord     = byte(0x03) // all the txs were removed from the mempool
+
+	walRecordHeaderSize = 8
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// encodeWALRecord returns the WAL record of the given type and data.
+func encodeWALRecord(recordType byte, data []byte) []byte {
+	record := make([]byte, walRecordHeaderSize+1+len(data))
+	record[walRecordHeaderSize] = recordType
+	copy(record[walRecordHeaderSize+1:], data)
+	binary.BigEndian.PutUint32(record[0:4], crc32.Checksum(record[walRecordHeaderSize:], crc32c))
+	binary.BigEndian.PutUint32(record[4:8], uint32(1+len(data)))
+	return record
+}
+
+// writeWAL appends the record of the given type and data to the WAL, if any.
+func writeWAL(wal *auto.AutoFile, logger log.Logger, recordType byte, data []byte) {
+	if wal == nil {
+		return
+	}
+	// TODO: Notify administrators when WAL fails
+	if _, err := wal.Write(encodeWALRecord(recordType, data)); err != nil {
+		logger.Error("Error writing to WAL", "err", err)
+	}
+}
+
+// walTxs are the txs read from a WAL which were not removed from the mempool,
+// of which only the last ones which fit in a mempool of the given config are
+// kept.
+type walTxs struct {
+	config *cfg.MempoolConfig
+	list   *list.List
+	map_   map[[sha256.Size]byte]*list.Element
+	bytes  int64
+}
+
+func newWALTxs(config *cfg.MempoolConfig) *walTxs {
+	return &walTxs{
+		config: config,
+		list:   list.New(),
+		map_:   make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Push adds the tx, unless it is already there or larger than MaxTxBytes.
+func (w *walTxs) Push(tx types.Tx) {
+	if len(tx) > w.config.MaxTxBytes {
+		return
+	}
+	if _, ok := w.map_[txKey(tx)]; ok {
+		return
+	}
+	w.map_[txKey(tx)] = w.list.PushBack(tx)
+	w.bytes += int64(len(tx))
+	// older txs would not fit in the mempool anyway
+	for w.list.Len() > 0 && (w.list.Len() > w.config.Size || w.bytes > w.config.MaxTxsBytes) {
+		w.remove(w.list.Front())
+	}
+}
+
+// Remove removes the tx with the given key.
+func (w *walTxs) Remove(key [sha256.Size]byte) {
+	if e, ok := w.map_[key]; ok {
+		w.remove(e)
+	}
+}
+
+func (w *walTxs) remove(e *list.Element) {
+	tx := w.list.Remove(e).(types.Tx)
+	delete(w.map_, txKey(tx))
+	w.bytes -= int64(len(tx))
+}
+
+// Flush removes all the txs.
+func (w *walTxs) Flush() {
+	w.list.Init()
+	w.map_ = make(map[[sha256.Size]byte]*list.Element)
+	w.bytes = 0
+}
+
+// Txs returns the txs in the order they were pushed.
+func (w *walTxs) Txs() types.Txs {
+	txs := make(types.Txs, 0, w.list.Len())
+	for e := w.list.Front(); e != nil; e = e.Next() {
+		txs = append(txs, e.Value.(types.Tx))
+	}
+	return txs
+}
+
+// readWAL returns the txs of the WAL file at path which were not removed from
+// the mempool, keeping only the last of them which fit in a mempool of the
+// given config. The txs larger than MaxTxBytes, e.g. because it was lowered
+// since they were written, are skipped. It stops at the first record which is
+// corrupted, or truncated by a crash in the middle of a write, and returns the
+// txs read before it along with the error. A file whose first record is not
+// valid is read as a WAL of a previous version, see readLegacyWAL.
+func readWAL(path string, config *cfg.MempoolConfig) (types.Txs, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		rd          = bufio.NewReader(f)
+		maxDataSize = config.MaxTxBytes
+		txs         = newWALTxs(config)
+	)
+	if maxDataSize < sha256.Size {
+		maxDataSize = sha256.Size
+	}
+
+	for i := 0; ; i++ {
+		recordType, data, err := readWALRecord(rd, maxDataSize)
+		if _, ok := err.(errWALRecordTooLarge); ok {
+			continue
+		} else if err == io.EOF {
+			return txs.Txs(), nil
+		} else if err != nil && i == 0 {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return readLegacyWAL(bufio.NewReader(f), config)
+		} else if err != nil {
+			return txs.Txs(), fmt.Errorf("record #%d: %v", i, err)
+		}
+
+		switch recordType {
+		case walTxRecord:
+			txs.Push(data)
+		case walRemovedTxRecord:
+			if len(data) != sha256.Size {
+				return txs.Txs(), fmt.Errorf("record #%d: wrong size of tx key (%d)", i, len(data))
+			}
+			var key [sha256.Size]byte
+			copy(key[:], data)
+			txs.Remove(key)
+		case walFlushRecord:
+			txs.Flush()
+		default:
+			return txs.Txs(), fmt.Errorf("record #%d: unknown record type %d", i, recordType)
+		}
+	}
+}
+
+// readLegacyWAL returns the txs of a WAL written by a previous version, which
+// wrote every tx checked by CheckTx followed by a newline, keeping only the
+// last of them which fit in a mempool of the given config. Such a WAL does not
+// record the txs removed from the mempool, which fail CheckTx when they are
+// replayed, and the txs with a newline are split in two.
+func readLegacyWAL(rd *bufio.Reader, config *cfg.MempoolConfig) (types.Txs, error) {
+	txs := newWALTxs(config)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if tx := bytes.TrimSuffix(line, []byte("\n")); len(tx) > 0 {
+			txs.Push(tx)
+		}
+		if err == io.EOF {
+			return txs.Txs(), nil
+		} else if err != nil {
+			return txs.Txs(), err
+		}
+	}
+}
+
+// errWALRecordTooLarge is returned by readWALRecord for a record whose data is
+// larger than the maximum. The record is skipped.
+type errWALRecordTooLarge struct {
+	size int64
+}
+
+func (e errWALRecordTooLarge) Error() string {
+	return fmt.Sprintf("record of %d bytes is too large", e.size)
+}
+
+// readWALRecord reads the next record of the WAL. It returns io.EOF if there
+// are no more records, and errWALRecordTooLarge if the data of the record is
+// longer than maxDataSize bytes, in which case the record is skipped.
+func readWALRecord(rd io.Reader, maxDataSize int) (recordType byte, data []byte, err error) {
+	header := make([]byte, walRecordHeaderSize)
+	if _, err := io.ReadFull(rd, header); err == io.EOF {
+		return 0, nil, err
+	} else if err != nil {
+		return 0, nil, fmt.Errorf("failed to read header: %v", err)
+	}
+	crc := binary.BigEndian.Uint32(header[0:4])
+	length := int64(binary.BigEndian.Uint32(header[4:8]))
+	if length < 1 {
+		return 0, nil, fmt.Errorf("length %d out of range [1, %d]", length, 1+maxDataSize)
+	}
+	if length > 1+int64(maxDataSize) {
+		// The record is not kept in memory, only its checksum is verified.
+		hash := crc32.New(crc32c)
+		if _, err := io.CopyN(hash, rd, length); err != nil {
+			return 0, nil, fmt.Errorf("failed to read data: %v", err)
+		}
+		if actualCRC := hash.Sum32(); actualCRC != crc {
+			return 0, nil, fmt.Errorf("checksums do not match: read: %v, actual: %v", crc, actualCRC)
+		}
+		return 0, nil, errWALRecordTooLarge{length - 1}
+	}
+
+	record := make([]byte, length)
+	if _, err := io.ReadFull(rd, record); err != nil {
+		return 0, nil, fmt.Errorf("failed to read data: %v", err)
+	}
+	if actualCRC := crc32.Checksum(record, crc32c); actualCRC != crc {
+		return 0, nil, fmt.Errorf("checksums do not match: read: %v, actual: %v", crc, actualCRC)
+	}
+	return record[0], record[1:], nil
+}
+
+// replayWAL runs CheckTx on the txs of the WAL file at path, left by the
+// previous run of the node. It then rewrites the file with only the txs which
+// made it into the mempool, and opens it to record the new txs.
+func replayWAL(mem Mempool, path string, config *cfg.MempoolConfig, logger log.Logger) (*auto.AutoFile, error) {
+	txs, err := readWAL(path, config)
+	if err != nil {
+		// the txs read before the error are still replayed
+		logger.Error("Error reading mempool WAL", "path", path, "txs", len(txs), "err", err)
+	}
+
+	for _, tx := range txs {
+		if err := mem.CheckTx(tx, nil, TxInfo{SenderID: UnknownPeerID}); err != nil {
+			logger.Debug("Could not replay tx", "tx", txID(tx), "err", err)
+		}
+	}
+	if err := mem.FlushAppConn(); err != nil {
+		return nil, err
+	}
+	if len(txs) > 0 {
+		logger.Info("Replayed mempool WAL", "txs", len(txs), "size", mem.Size())
+	}
+
+	var buf bytes.Buffer
+	for _, tx := range mem.ReapMaxTxs(-1) {
+		buf.Write(encodeWALRecord(walTxRecord, tx))
+	}
+	if err := cmn.WriteFileAtomic(path, buf.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	return auto.OpenAutoFile(path)
+}
@@ -0,0 +1,120 @@
+package mempool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// newWALMempool returns a mempool of the given version with the WAL of the
+// config replayed, as on the start of a node.
+func newWALMempool(version string, config *cfg.Config) Mempool {
+	app := kvstore.NewKVStoreApplication()
+	var mempool Mempool
+	if version == "v1" {
+		mempool, _ = newPriorityMempoolWithApp(app, config)
+	} else {
+		mempool, _ = newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(app), config)
+	}
+	mempool.InitWAL()
+	return mempool
+}
+
+func walRecords(txs ...types.Tx) []byte {
+	var records []byte
+	for _, tx := range txs {
+		records = append(records, encodeWALRecord(walTxRecord, tx)...)
+	}
+	return records
+}
+
+func walRemovedRecord(tx types.Tx) []byte {
+	key := txKey(tx)
+	return encodeWALRecord(walRemovedTxRecord, key[:])
+}
+
+func concat(records ...[]byte) []byte {
+	var all []byte
+	for _, r := range records {
+		all = append(all, r...)
+	}
+	return all
+}
+
+func TestMempoolReplayWAL(t *testing.T) {
+	for _, version := range []string{"v0", "v1"} {
+		config := cfg.ResetTestRoot("mempool_test")
+		config.Mempool.WalPath = "data/mempool.wal"
+		walFile := filepath.Join(config.Mempool.WalDir(), "wal")
+
+		mempool := newWALMempool(version, config)
+		txs := types.Txs{[]byte("tx1"), []byte("tx\n2"), []byte("tx3")}
+		checkPriorityTxs(t, mempool, txs)
+		mempool.CloseWAL()
+
+		// the txs are checked again on restart
+		mempool = newWALMempool(version, config)
+		assert.Equal(t, txs, mempool.ReapMaxTxs(-1), version)
+
+		// the committed txs are not checked again
+		require.NoError(t, mempool.Update(1, txs[:1], abciResponses(1, 0), nil, nil))
+		mempool.CloseWAL()
+		mempool = newWALMempool(version, config)
+		assert.Equal(t, txs[1:], mempool.ReapMaxTxs(-1), version)
+		mempool.CloseWAL()
+
+		// the WAL is rewritten with only the txs in the mempool
+		records, err := ioutil.ReadFile(walFile)
+		require.NoError(t, err)
+		assert.Equal(t, walRecords(txs[1:]...), records, version)
+
+		os.RemoveAll(config.RootDir)
+	}
+}
+
+func TestMempoolReplayCorruptedWAL(t *testing.T) {
+	config := cfg.ResetTestRoot("mempool_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Mempool.WalPath = "data/mempool.wal"
+	config.Mempool.Size = 2
+	config.Mempool.MaxTxBytes = 10
+	walFile := filepath.Join(config.Mempool.WalDir(), "wal")
+	require.NoError(t, os.MkdirAll(config.Mempool.WalDir(), 0700))
+
+	txs := types.Txs{[]byte("tx1"), []byte("tx2"), []byte("tx3")}
+	testCases := []struct {
+		name     string
+		records  []byte
+		replayed types.Txs
+	}{
+		{"only the last txs which fit in the mempool", walRecords(txs...), txs[1:]},
+		{"removed tx", concat(walRecords(txs[:2]...), walRemovedRecord(txs[0]), walRecords(txs[2])), txs[1:]},
+		{"flush", concat(walRecords(txs[:2]...), encodeWALRecord(walFlushRecord, nil), walRecords(txs[2])), txs[2:]},
+		{"truncated record", walRecords(txs...)[:30], txs[:2]},
+		{"unknown record type", concat(walRecords(txs[0]), encodeWALRecord(0xff, nil)), txs[:1]},
+		{"wrong checksum", append(walRecords(txs[:1]...), 0, 0, 0, 0, 0, 0, 0, 1, 'x'), txs[:1]},
+		{"too large tx", walRecords(txs[0], []byte("01234567890"), txs[1]), txs[:2]},
+		{"too large record", concat(walRecords(txs[0]), encodeWALRecord(walRemovedTxRecord, make([]byte, 40)),
+			walRecords(txs[1])), txs[:2]},
+		{"corrupted too large record", concat(walRecords(txs[0]), []byte{0, 0, 0, 0, 0, 0, 0, 40},
+			make([]byte, 40), walRecords(txs[1])), txs[:1]},
+		{"former format", []byte("tx1\ntx2\n"), txs[:2]},
+		{"former format, only the last txs which fit in the mempool", []byte("tx1\ntx2\ntx3"), txs[1:]},
+		{"former format, too large tx", []byte("tx1\n01234567890\n\ntx2\n"), txs[:2]},
+	}
+	for _, tc := range testCases {
+		require.NoError(t, ioutil.WriteFile(walFile, tc.records, 0600))
+		mempool := newWALMempool("v0", config)
+		assert.Equal(t, tc.replayed, mempool.ReapMaxTxs(-1), tc.name)
+		mempool.CloseWAL()
+	}
+}
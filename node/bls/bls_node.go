@@ -9,6 +9,7 @@ import (
 	"github.com/corestario/dkglib/lib/basic"
 
 	bShare "github.com/corestario/dkglib/lib/blsShare"
+	dkgDealer "github.com/corestario/dkglib/lib/dealer"
 	dkgOffChain "github.com/corestario/dkglib/lib/offChain"
 	dkgtypes "github.com/corestario/dkglib/lib/types"
 	"github.com/pkg/errors"
@@ -47,7 +48,7 @@ func createBLSBlockchainReactor(config *cfg.Config,
 	case "v0":
 		bcReactor = bcv0.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync, bcv0.WithVerifier(verifier))
 	case "v1":
-		bcReactor = bcv1.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync)
+		bcReactor = bcv1.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync, bcv1.WithVerifier(verifier))
 	default:
 		return nil, fmt.Errorf("unknown fastsync version %s", config.FastSync.Version)
 	}
@@ -60,7 +61,7 @@ func createBLSConsensus(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
-	mempool *mempl.CListMempool,
+	mempool mempl.Mempool,
 	evidencePool *evidence.EvidencePool,
 	privValidator types.PrivValidator,
 	csMetrics *cs.Metrics,
@@ -68,7 +69,8 @@ func createBLSConsensus(config *cfg.Config,
 	eventBus *types.EventBus,
 	consensusLogger log.Logger,
 	verifier dkgtypes.Verifier,
-	genDoc *types.GenesisDoc) (*cs.ConsensusReactor, *cs.ConsensusState) {
+	genDoc *types.GenesisDoc,
+	options ...cs.StateOption) (*cs.ConsensusReactor, *cs.DKGReactor, *cs.ConsensusState) {
 	// Make ConsensusReactor
 	evsw := events.NewEventSwitch()
 
@@ -84,6 +86,7 @@ func createBLSConsensus(config *cfg.Config,
 		dkgOffChain.WithDKGNumBlocks(genDoc.DKGNumBlocks),
 		dkgOffChain.WithLogger(logger),
 		dkgOffChain.WithPVKey(privValidator),
+		dkgOffChain.WithDKGDealerConstructor(cs.DKGDealerWithKeys(dkgDealer.NewDKGDealer)),
 	)
 	if err != nil {
 		panic(err)
@@ -96,9 +99,11 @@ func createBLSConsensus(config *cfg.Config,
 		blockStore,
 		mempool,
 		evidencePool,
-		cs.StateMetrics(csMetrics),
-		cs.WithEVSW(evsw),
-		cs.WithDKG(dkg),
+		append([]cs.StateOption{
+			cs.StateMetrics(csMetrics),
+			cs.WithEVSW(evsw),
+			cs.WithDKG(dkg),
+		}, options...)...,
 	)
 
 	consensusState.SetLogger(consensusLogger)
@@ -110,7 +115,10 @@ func createBLSConsensus(config *cfg.Config,
 	// services which will be publishing and/or subscribing for messages (events)
 	// consensusReactor will set it on consensusState and blockExecutor
 	consensusReactor.SetEventBus(eventBus)
-	return consensusReactor, consensusState
+	// The DKG messages are gossiped by a reactor of their own.
+	dkgReactor := cs.NewDKGReactor(consensusState, cs.DKGReactorMetrics(csMetrics))
+	dkgReactor.SetLogger(consensusLogger)
+	return consensusReactor, dkgReactor, consensusState
 }
 
 func NewBLSNodeForCosmos(config *cfg.Config, logger log.Logger, app abci.Application) (*nd.Node, error) {
@@ -165,6 +173,7 @@ func NewBLSNode(config *cfg.Config,
 	if err != nil {
 		return nil, err
 	}
+	blockStore.SetLogger(logger.With("module", "store"))
 
 	state, genDoc, err := nd.LoadStateFromDBOrGenesisDocProvider(stateDB, genesisDocProvider)
 	if err != nil {
@@ -223,7 +232,10 @@ func NewBLSNode(config *cfg.Config,
 	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
 
 	// Make MempoolReactor
-	mempoolReactor, mempool := nd.CreateMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger)
+	mempoolReactor, mempool, err := nd.CreateMempoolAndMempoolReactor(config, proxyApp, state, eventBus, memplMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Make Evidence Reactor
 	evidenceReactor, evidencePool, err := nd.CreateEvidenceReactor(config, dbProvider, stateDB, logger)
@@ -241,10 +253,21 @@ func NewBLSNode(config *cfg.Config,
 		sm.BlockExecutorWithMetrics(smMetrics),
 	)
 
-	var verifier *bShare.BLSVerifier
-	fmt.Println("load bls from", "path", config.BLSKeyFile())
-	blsShare, err := bShare.LoadBLSShareJSON(config.BLSKeyFile())
-	if err == nil {
+	// The verifier is a typed nil rather than a nil interface when there is no
+	// BLS key, consensus checks it with IsNil.
+	var verifier dkgtypes.Verifier = (*types.BLSVerifier)(nil)
+	if config.PrivValidatorListenAddr != "" && config.BLSKey == "" {
+		// The BLS key share is kept by the remote signer, which signs the random
+		// shares. The verifier only verifies them.
+		masterPubKey, err := bShare.LoadPubKey(genDoc.BLSMasterPubKey, genDoc.BLSNumShares)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load master public key from genesis: %v", err)
+		}
+		verifier, err = types.NewBLSVerifier(masterPubKey, nil, genDoc.BLSThreshold, genDoc.BLSNumShares)
+		if err != nil {
+			return nil, err
+		}
+	} else if blsShare, err := bShare.LoadBLSShareJSON(config.BLSKeyFile()); err == nil {
 		keypair, err := blsShare.Deserialize()
 		if err != nil {
 			return nil, fmt.Errorf("failed to load keypair: %v", err)
@@ -255,11 +278,43 @@ func NewBLSNode(config *cfg.Config,
 			return nil, fmt.Errorf("failed to load master public key from genesis: %v", err)
 		}
 
-		verifier = bShare.NewBLSVerifier(masterPubKey, keypair, genDoc.BLSThreshold, genDoc.BLSNumShares)
+		verifier, err = types.NewBLSVerifier(masterPubKey, keypair, genDoc.BLSThreshold, genDoc.BLSNumShares)
+		if err != nil {
+			return nil, err
+		}
+
+		// The random shares are signed by the private validator, which refuses
+		// to sign conflicting shares.
+		if filePV, ok := privValidator.(*privval.FilePV); ok {
+			filePV.SetBLSShare(keypair)
+		}
 	} else {
 		logger.Info("Failed to load BLS key from", config.BLSKeyFile())
 	}
 
+	// A verifier produced by a DKG round before a restart takes precedence over
+	// the initial BLS key. Its key is kept by the private validator: a FilePV
+	// saves it to dkg_verifier_file, a remote signer to a file of its own.
+	if filePV, ok := privValidator.(*privval.FilePV); ok {
+		if err := filePV.LoadDKGVerifierFile(config.DKGVerifierFile()); err != nil {
+			return nil, errors.Wrap(err, "failed to load DKG verifier")
+		}
+	}
+	keyStore, _ := privValidator.(privval.DKGVerifierKeyStore)
+	if keyStore != nil {
+		dkgVerifier, err := loadDKGVerifier(keyStore, state.LastBlockHeight+1, logger)
+		if err != nil {
+			return nil, err
+		}
+		if dkgVerifier != nil {
+			verifier = dkgVerifier
+		}
+	}
+	var csOptions []cs.StateOption
+	if keyStore != nil {
+		csOptions = append(csOptions, cs.WithVerifierSaver(dkgVerifierSaver(keyStore)))
+	}
+
 	// Make BlockchainReactor
 	bcReactor, err := createBLSBlockchainReactor(config, state, blockExec, blockStore, verifier, fastSync, logger)
 	if err != nil {
@@ -267,15 +322,19 @@ func NewBLSNode(config *cfg.Config,
 	}
 
 	// Make ConsensusReactor
-	consensusReactor, consensusState := createBLSConsensus(
+	consensusReactor, dkgReactor, consensusState := createBLSConsensus(
 		config, state, blockExec, blockStore, mempool, evidencePool,
-		privValidator, csMetrics, fastSync, eventBus, consensusLogger, verifier, genDoc,
+		privValidator, csMetrics, fastSync, eventBus, consensusLogger, verifier, genDoc, csOptions...,
 	)
 
 	nodeInfo, err := nd.MakeNodeInfo(config, nodeKey, txIndexer, genDoc, state)
 	if err != nil {
 		return nil, err
 	}
+	if defaultNodeInfo, ok := nodeInfo.(p2p.DefaultNodeInfo); ok {
+		defaultNodeInfo.Channels = append(defaultNodeInfo.Channels, cs.DKGChannel)
+		nodeInfo = defaultNodeInfo
+	}
 
 	// Setup Transport.
 	transport, peerFilters := nd.CreateTransport(config, nodeInfo, nodeKey, proxyApp)
@@ -283,7 +342,7 @@ func NewBLSNode(config *cfg.Config,
 	p2pLogger := logger.With("module", "p2p")
 	sw := createBLSSwitch(
 		config, transport, p2pMetrics, peerFilters, mempoolReactor, bcReactor,
-		consensusReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger,
+		consensusReactor, dkgReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger,
 	)
 	err = sw.AddPersistentPeers(nd.SplitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
 	if err != nil {
@@ -351,6 +410,46 @@ func NewBLSNode(config *cfg.Config,
 	return node, nil
 }
 
+// loadDKGVerifier loads the verifier of the last DKG round from keyStore. It
+// returns nil if there is none or if it is not used yet at the given height.
+// The verifier of a key without its BLS key share leaves the signing of the
+// random shares to the private validator.
+func loadDKGVerifier(keyStore privval.DKGVerifierKeyStore, height int64,
+	logger log.Logger) (dkgtypes.Verifier, error) {
+
+	key, err := keyStore.DKGVerifierKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DKG verifier")
+	}
+	if key == nil {
+		return nil, nil
+	}
+	if key.StartHeight > height {
+		logger.Info("Ignoring DKG verifier which is not used yet",
+			"startHeight", key.StartHeight, "height", height)
+		return nil, nil
+	}
+	verifier, err := key.Verifier()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load DKG verifier")
+	}
+	logger.Info("Loaded DKG verifier", "startHeight", key.StartHeight, "share", key.HasShare())
+	return verifier, nil
+}
+
+// dkgVerifierSaver returns a function giving the keys of the verifiers of the
+// random beacon epochs to keyStore, so that the private validator signs the
+// random shares with the new BLS key share and keeps it across restarts.
+func dkgVerifierSaver(keyStore privval.DKGVerifierKeyStore) func(dkgtypes.Verifier, int64) error {
+	return func(verifier dkgtypes.Verifier, height int64) error {
+		key, err := privval.NewDKGVerifierKey(verifier, height)
+		if err != nil {
+			return err
+		}
+		return keyStore.SetDKGVerifierKey(key)
+	}
+}
+
 func createBLSSwitch(config *cfg.Config,
 	transport p2p.Transport,
 	p2pMetrics *p2p.Metrics,
@@ -358,6 +457,7 @@ func createBLSSwitch(config *cfg.Config,
 	mempoolReactor *mempl.Reactor,
 	bcReactor p2p.Reactor,
 	consensusReactor *cs.ConsensusReactor,
+	dkgReactor *cs.DKGReactor,
 	evidenceReactor *evidence.EvidenceReactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
@@ -373,6 +473,7 @@ func createBLSSwitch(config *cfg.Config,
 	sw.AddReactor("MEMPOOL", mempoolReactor)
 	sw.AddReactor("BLOCKCHAIN", bcReactor)
 	sw.AddReactor("CONSENSUS", consensusReactor)
+	sw.AddReactor("DKG", dkgReactor)
 	sw.AddReactor("EVIDENCE", evidenceReactor)
 
 	sw.SetNodeInfo(nodeInfo)
@@ -0,0 +1,103 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/log"
+	nd "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// TestBLSNodeRemoteSignerRandomShares runs a validator whose BLS key share is
+// kept by a remote signer, which must sign the random shares of every height.
+func TestBLSNodeRemoteSignerRandomShares(t *testing.T) {
+	config := cfg.ResetTestRoot("bls_node_priv_val_tcp_test")
+	defer os.RemoveAll(config.RootDir)
+	config.P2P.ListenAddress = "tcp://" + testFreeAddr(t)
+	config.RPC.ListenAddress = "tcp://" + testFreeAddr(t)
+	addr := "tcp://" + testFreeAddr(t)
+	config.BaseConfig.PrivValidatorListenAddr = addr
+	config.BaseConfig.BLSKey = ""
+
+	// The remote signer loads the key share of the validator from the genesis
+	// key set, as priv_val_server does with -bls-key-file.
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	blsKeyFile := filepath.Join(config.RootDir, "signer_bls_key.json")
+	blsKeyJSON, err := json.Marshal(genDoc.BLSShare)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(blsKeyFile, blsKeyJSON, 0600))
+	pv := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	require.NoError(t, pv.LoadBLSShareFile(blsKeyFile))
+	dialer := privval.DialTCPFn(addr, 3*time.Second, ed25519.GenPrivKey())
+	dialerEndpoint := privval.NewSignerDialerEndpoint(log.TestingLogger(), dialer)
+	privval.SignerDialerEndpointTimeoutReadWrite(3 * time.Second)(dialerEndpoint)
+	signerServer := privval.NewSignerServer(dialerEndpoint, config.ChainID(), pv)
+	go func() {
+		err := signerServer.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	defer signerServer.Stop()
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+	n, err := NewBLSNode(
+		config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication()),
+		nd.DefaultGenesisDocProviderFunc(config),
+		nd.DefaultDBProvider,
+		nd.DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+	)
+	require.NoError(t, err)
+	assert.IsType(t, &privval.SignerClient{}, n.GetPrivValidator())
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	// The random data of a block is recovered from the random shares of its
+	// precommits, so the chain only grows if the remote signer signs them.
+	deadline := time.Now().Add(20 * time.Second)
+	for n.GetBlockStore().Height() < 3 {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for blocks")
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	key := types.RandomBeaconKey{
+		MasterPubKey: genDoc.BLSMasterPubKey,
+		Threshold:    genDoc.BLSThreshold,
+		NumShares:    genDoc.BLSNumShares,
+	}
+	verifier, err := key.Verifier(nil)
+	require.NoError(t, err)
+	header := n.GetBlockStore().LoadBlockMeta(1).Header
+	msg := types.MakeRandomMessage([]byte(types.InitialRandomData), nil)
+	assert.NoError(t, verifier.VerifyRandomData(msg, header.RandomData))
+}
+
+func testFreeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	return fmt.Sprintf("127.0.0.1:%d", ln.Addr().(*net.TCPAddr).Port)
+}
@@ -323,16 +323,34 @@ func OnlyValidatorIsUs(state sm.State, privVal types.PrivValidator) bool {
 }
 
 func CreateMempoolAndMempoolReactor(config *cfg.Config, proxyApp proxy.AppConns,
-	state sm.State, memplMetrics *mempl.Metrics, logger log.Logger) (*mempl.Reactor, *mempl.CListMempool) {
-
-	mempool := mempl.NewCListMempool(
-		config.Mempool,
-		proxyApp.Mempool(),
-		state.LastBlockHeight,
-		mempl.WithMetrics(memplMetrics),
-		mempl.WithPreCheck(sm.TxPreCheck(state)),
-		mempl.WithPostCheck(sm.TxPostCheck(state)),
-	)
+	state sm.State, eventBus types.MempoolEventPublisher, memplMetrics *mempl.Metrics,
+	logger log.Logger) (*mempl.Reactor, mempl.BroadcastMempool, error) {
+
+	var mempool mempl.BroadcastMempool
+	switch config.Mempool.Version {
+	case "v0":
+		mempool = mempl.NewCListMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			mempl.WithMetrics(memplMetrics),
+			mempl.WithPreCheck(sm.TxPreCheck(state)),
+			mempl.WithPostCheck(sm.TxPostCheck(state)),
+			mempl.WithEventBus(eventBus),
+		)
+	case "v1":
+		mempool = mempl.NewPriorityMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			mempl.WithPriorityMetrics(memplMetrics),
+			mempl.WithPriorityPreCheck(sm.TxPreCheck(state)),
+			mempl.WithPriorityPostCheck(sm.TxPostCheck(state)),
+			mempl.WithPriorityEventBus(eventBus),
+		)
+	default:
+		return nil, nil, fmt.Errorf("unknown mempool version %s", config.Mempool.Version)
+	}
 	mempoolLogger := logger.With("module", "mempool")
 	mempoolReactor := mempl.NewReactor(config.Mempool, mempool)
 	mempoolReactor.SetLogger(mempoolLogger)
@@ -340,7 +358,7 @@ func CreateMempoolAndMempoolReactor(config *cfg.Config, proxyApp proxy.AppConns,
 	if config.Consensus.WaitForTxs() {
 		mempool.EnableTxsAvailable()
 	}
-	return mempoolReactor, mempool
+	return mempoolReactor, mempool, nil
 }
 
 func CreateEvidenceReactor(config *cfg.Config, dbProvider DBProvider,
@@ -382,7 +400,7 @@ func createConsensusReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
-	mempool *mempl.CListMempool,
+	mempool mempl.Mempool,
 	evidencePool *evidence.EvidencePool,
 	privValidator types.PrivValidator,
 	csMetrics *cs.Metrics,
@@ -568,6 +586,7 @@ func NewNode(config *cfg.Config,
 	if err != nil {
 		return nil, err
 	}
+	blockStore.SetLogger(logger.With("module", "store"))
 
 	state, genDoc, err := LoadStateFromDBOrGenesisDocProvider(stateDB, genesisDocProvider)
 	if err != nil {
@@ -638,7 +657,10 @@ func NewNode(config *cfg.Config,
 	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
 
 	// Make MempoolReactor
-	mempoolReactor, mempool := CreateMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger)
+	mempoolReactor, mempool, err := CreateMempoolAndMempoolReactor(config, proxyApp, state, eventBus, memplMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	logger.Debug("state create mempool", "validators", state.Validators)
 
@@ -766,6 +788,11 @@ func (n *Node) OnStart() error {
 	// Add private IDs to addrbook to block those peers being added
 	n.AddrBook.AddPrivateIDs(SplitAndTrimEmpty(n.Config.P2P.PrivatePeerIDs, ",", " "))
 
+	// Replay the mempool WAL before new txs are received from the RPC or peers
+	if n.Config.Mempool.WalEnabled() {
+		n.Mempool.InitWAL() // no need to have the mempool wal during tests
+	}
+
 	// Start the RPC server before the P2P server
 	// so we can eg. receive txs for the first block
 	if n.Config.RPC.ListenAddress != "" {
@@ -792,10 +819,6 @@ func (n *Node) OnStart() error {
 
 	n.IsListening = true
 
-	if n.Config.Mempool.WalEnabled() {
-		n.Mempool.InitWAL() // no need to have the mempool wal during tests
-	}
-
 	// Start the switch (the P2P server).
 	err = n.Sw.Start()
 	if err != nil {
@@ -1107,7 +1130,7 @@ func MakeNodeInfo(
 		Channels: []byte{
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
-			mempl.MempoolChannel,
+			mempl.MempoolChannel, mempl.MempoolAnnounceChannel,
 			evidence.EvidenceChannel,
 		},
 		Moniker: config.Moniker,
@@ -0,0 +1,153 @@
+package privval
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/crypto/xsalsa20symmetric"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+// dkgVerifierKeyDomain separates the secret the DKG verifier key is encrypted
+// with from any other use of the validator's private key.
+const dkgVerifierKeyDomain = "tendermint/DKGVerifierKey"
+
+// DKGVerifierKey stores the BLS key share and the master public key produced
+// by a DKG round, so that a restarted validator can reuse them instead of
+// running a new DKG round. The key is valid from StartHeight until the next
+// DKG round replaces it.
+type DKGVerifierKey struct {
+	ShareID      int                   `json:"share_id"`
+	Share        blsShare.BLSShareJSON `json:"share"`
+	MasterPubKey string                `json:"master_pub_key"`
+	Threshold    int                   `json:"threshold"`
+	NumShares    int                   `json:"num_shares"`
+	StartHeight  int64                 `json:"start_height"`
+}
+
+// DKGVerifierKeyStore is implemented by the private validators which keep the
+// key of the last DKG round of the validator, so that the validator signs the
+// random shares with its BLS key share and reuses the key after a restart.
+type DKGVerifierKeyStore interface {
+	// DKGVerifierKey returns the key of the last DKG round, or nil if there is
+	// none. The key may come without its BLS key share if the store signs the
+	// random shares itself.
+	DKGVerifierKey() (*DKGVerifierKey, error)
+	// SetDKGVerifierKey stores the key of a new DKG round.
+	SetDKGVerifierKey(key *DKGVerifierKey) error
+}
+
+// NewDKGVerifierKey returns the key of the given verifier, which is used from
+// startHeight on. Only the verifiers which carry their key, *types.BLSVerifier,
+// are supported.
+func NewDKGVerifierKey(verifier dkgtypes.Verifier, startHeight int64) (*DKGVerifierKey, error) {
+	v, ok := verifier.(*types.BLSVerifier)
+	if !ok {
+		return nil, fmt.Errorf("unsupported verifier type %T", verifier)
+	}
+	if v.IsNil() || v.Keypair == nil {
+		return nil, fmt.Errorf("empty verifier")
+	}
+
+	shareJSON, err := blsShare.NewBLSShareJSON(v.Keypair)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DKGVerifierKey{
+		ShareID:      v.Keypair.ID,
+		Share:        *shareJSON,
+		MasterPubKey: v.Key.MasterPubKey,
+		Threshold:    v.Key.Threshold,
+		NumShares:    v.Key.NumShares,
+		StartHeight:  startHeight,
+	}, nil
+}
+
+// RandomBeaconKey returns the public part of the key.
+func (key *DKGVerifierKey) RandomBeaconKey() types.RandomBeaconKey {
+	return types.RandomBeaconKey{
+		MasterPubKey: key.MasterPubKey,
+		Threshold:    key.Threshold,
+		NumShares:    key.NumShares,
+	}
+}
+
+// HasShare returns true if the key holds its BLS key share, i.e. was not
+// returned by WithoutShare.
+func (key *DKGVerifierKey) HasShare() bool {
+	return key.Share.Priv != ""
+}
+
+// WithoutShare returns a copy of the key without its BLS key share.
+func (key *DKGVerifierKey) WithoutShare() *DKGVerifierKey {
+	keyCopy := *key
+	keyCopy.Share = blsShare.BLSShareJSON{}
+	return &keyCopy
+}
+
+// Keypair returns the BLS key share stored in the key.
+func (key *DKGVerifierKey) Keypair() (*blsShare.BLSShare, error) {
+	if !key.HasShare() {
+		return nil, fmt.Errorf("no BLS key share")
+	}
+	keypair, err := key.Share.Deserialize()
+	if err != nil {
+		return nil, err
+	}
+	keypair.ID = key.ShareID
+	return keypair, nil
+}
+
+// Verifier returns the verifier stored in the key. It does not sign if the key
+// has no BLS key share.
+func (key *DKGVerifierKey) Verifier() (*types.BLSVerifier, error) {
+	var keypair *blsShare.BLSShare
+	if key.HasShare() {
+		var err error
+		if keypair, err = key.Keypair(); err != nil {
+			return nil, err
+		}
+	}
+	return key.RandomBeaconKey().Verifier(keypair)
+}
+
+// Save encrypts the key with a secret derived from privKey and persists it to
+// filePath.
+func (key *DKGVerifierKey) Save(filePath string, privKey crypto.PrivKey) error {
+	jsonBytes, err := cdc.MarshalJSON(key)
+	if err != nil {
+		return err
+	}
+	ciphertext := xsalsa20symmetric.EncryptSymmetric(jsonBytes, dkgVerifierKeySecret(privKey))
+	return cmn.WriteFileAtomic(filePath, ciphertext, 0600)
+}
+
+// LoadDKGVerifierKey loads the key saved to filePath and decrypts it with a
+// secret derived from privKey.
+func LoadDKGVerifierKey(filePath string, privKey crypto.PrivKey) (*DKGVerifierKey, error) {
+	ciphertext, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, err := xsalsa20symmetric.DecryptSymmetric(ciphertext, dkgVerifierKeySecret(privKey))
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting DKG verifier key from %v: %v", filePath, err)
+	}
+
+	key := new(DKGVerifierKey)
+	if err := cdc.UnmarshalJSON(jsonBytes, key); err != nil {
+		return nil, fmt.Errorf("error reading DKG verifier key from %v: %v", filePath, err)
+	}
+	return key, nil
+}
+
+func dkgVerifierKeySecret(privKey crypto.PrivKey) []byte {
+	return tmhash.Sum(append([]byte(dkgVerifierKeyDomain), privKey.Bytes()...))
+}
@@ -0,0 +1,100 @@
+package privval
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestDKGVerifierKeySaveLoad(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	verifiers := make([]*types.BLSVerifier, 3)
+	for id := range verifiers {
+		verifiers[id], err = types.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[id], 2, 3)
+		require.NoError(t, err)
+	}
+
+	tempFile, err := ioutil.TempFile("", "dkg_verifier_")
+	require.NoError(t, err)
+	defer os.Remove(tempFile.Name())
+
+	privKey := ed25519.GenPrivKey()
+	key, err := NewDKGVerifierKey(verifiers[1], 10)
+	require.NoError(t, err)
+	require.NoError(t, key.Save(tempFile.Name(), privKey))
+
+	// The key is not stored in the clear.
+	bz, err := ioutil.ReadFile(tempFile.Name())
+	require.NoError(t, err)
+	assert.NotContains(t, string(bz), key.Share.Priv)
+
+	_, err = LoadDKGVerifierKey(tempFile.Name(), ed25519.GenPrivKey())
+	assert.Error(t, err)
+
+	loadedKey, err := LoadDKGVerifierKey(tempFile.Name(), privKey)
+	require.NoError(t, err)
+	assert.Equal(t, key, loadedKey)
+	assert.EqualValues(t, 10, loadedKey.StartHeight)
+	assert.Equal(t, 2, loadedKey.Threshold)
+	assert.Equal(t, 3, loadedKey.NumShares)
+
+	verifier, err := loadedKey.Verifier()
+	require.NoError(t, err)
+	assert.Equal(t, 1, verifier.Keypair.ID)
+
+	// The loaded verifier produces shares that recover together with the
+	// shares of the other participants.
+	msg := []byte("random message")
+	share0, err := verifiers[0].Sign(msg)
+	require.NoError(t, err)
+	share1, err := verifier.Sign(msg)
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyRandomShare("", msg, share1))
+
+	randomData, err := verifier.Recover(msg, []blsShare.BLSSigner{
+		&testBLSSigner{share0}, &testBLSSigner{share1},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, verifiers[2].VerifyRandomData(msg, randomData))
+
+	// Without its share, the key only verifies.
+	publicKey := loadedKey.WithoutShare()
+	assert.False(t, publicKey.HasShare())
+	assert.True(t, loadedKey.HasShare())
+	verifier, err = publicKey.Verifier()
+	require.NoError(t, err)
+	assert.Nil(t, verifier.Keypair)
+	assert.NoError(t, verifier.VerifyRandomData(msg, randomData))
+	_, err = publicKey.Keypair()
+	assert.Error(t, err)
+}
+
+func TestDKGVerifierKeyEmptyVerifier(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+
+	verifier, err := types.NewBLSVerifier(keyring.MasterPubKey, nil, 2, 3)
+	require.NoError(t, err)
+	_, err = NewDKGVerifierKey(verifier, 5)
+	assert.Error(t, err)
+	_, err = NewDKGVerifierKey((*types.BLSVerifier)(nil), 5)
+	assert.Error(t, err)
+	// the verifiers of dkglib do not carry their key
+	_, err = NewDKGVerifierKey(blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[0], 2, 3), 5)
+	assert.Error(t, err)
+}
+
+type testBLSSigner struct {
+	blsSignature []byte
+}
+
+func (s *testBLSSigner) GetBLSSignature() []byte { return s.blsSignature }
+func (s *testBLSSigner) GetHash() []byte         { return []byte("hash") }
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
+
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -73,6 +75,12 @@ type FilePVLastSignState struct {
 	Signature []byte       `json:"signature,omitempty"`
 	SignBytes cmn.HexBytes `json:"signbytes,omitempty"`
 
+	// The last random share and the height and message it was signed for.
+	// Random shares are signed once per height, independently of the votes.
+	RandomShareHeight  int64        `json:"random_share_height,omitempty"`
+	RandomShareMessage cmn.HexBytes `json:"random_share_message,omitempty"`
+	RandomShare        cmn.HexBytes `json:"random_share,omitempty"`
+
 	filePath string
 }
 
@@ -117,6 +125,30 @@ func (lss *FilePVLastSignState) CheckHRS(height int64, round int, step int8) (bo
 	return false, nil
 }
 
+// CheckRandomShare checks the given height and random message against the
+// last random share of the FilePVLastSignState. It returns an error if the
+// height is a regression or if a different message was already signed at the
+// height, since the random message of a height never changes.
+// The returned boolean indicates whether the last RandomShare should be reused -
+// it returns true if the message was already signed at the height.
+func (lss *FilePVLastSignState) CheckRandomShare(height int64, msg []byte) (bool, error) {
+	if lss.RandomShareHeight > height {
+		return false, fmt.Errorf("random share height regression. Got %v, last height %v",
+			height, lss.RandomShareHeight)
+	}
+
+	if lss.RandomShareHeight == height {
+		if !bytes.Equal(lss.RandomShareMessage, msg) {
+			return false, fmt.Errorf("conflicting random message at height %v", height)
+		}
+		if lss.RandomShare == nil {
+			panic("pv: RandomShare is nil but RandomShareMessage is not!")
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 // Save persists the FilePvLastSignState to its filePath.
 func (lss *FilePVLastSignState) Save() {
 	outFile := lss.filePath
@@ -143,6 +175,14 @@ func (lss *FilePVLastSignState) Save() {
 type FilePV struct {
 	Key           FilePVKey
 	LastSignState FilePVLastSignState
+
+	// the BLS key share of the validator, if it is kept by the FilePV
+	blsShare *blsShare.BLSShare
+	// the key of the last DKG round and its BLS key share, used from the start
+	// height of the key on
+	dkgVerifierKey      *DKGVerifierKey
+	dkgVerifierKeyShare *blsShare.BLSShare
+	dkgVerifierFilePath string
 }
 
 // GenFilePV generates a new validator with randomly generated private key
@@ -262,6 +302,84 @@ func (pv *FilePV) SignData(chainID string, data types.DataSigner) error {
 	return nil
 }
 
+// SetBLSShare sets the BLS key share the FilePV signs the random shares with,
+// e.g. when it backs a remote signer.
+func (pv *FilePV) SetBLSShare(share *blsShare.BLSShare) {
+	pv.blsShare = share
+}
+
+// LoadBLSShareFile sets the BLS key share saved to filePath in the format of
+// bls_key_file, see SetBLSShare.
+func (pv *FilePV) LoadBLSShareFile(filePath string) error {
+	shareJSON, err := blsShare.LoadBLSShareJSON(filePath)
+	if err != nil {
+		return err
+	}
+	share, err := shareJSON.Deserialize()
+	if err != nil {
+		return fmt.Errorf("failed to load BLS key share: %v", err)
+	}
+	pv.SetBLSShare(share)
+	return nil
+}
+
+// LoadDKGVerifierFile loads the key of the last DKG round saved to filePath,
+// if any, and saves the keys set by SetDKGVerifierKey to filePath from now on.
+// The file is encrypted with the private key of the FilePV.
+func (pv *FilePV) LoadDKGVerifierFile(filePath string) error {
+	pv.dkgVerifierFilePath = filePath
+	if !cmn.FileExists(filePath) {
+		return nil
+	}
+	key, err := LoadDKGVerifierKey(filePath, pv.Key.PrivKey)
+	if err != nil {
+		return err
+	}
+	return pv.setDKGVerifierKey(key)
+}
+
+// DKGVerifierKey returns the key of the last DKG round, or nil if there is
+// none. Implements DKGVerifierKeyStore.
+func (pv *FilePV) DKGVerifierKey() (*DKGVerifierKey, error) {
+	return pv.dkgVerifierKey, nil
+}
+
+// SetDKGVerifierKey sets the key of a new DKG round, whose BLS key share is
+// used to sign the random shares from the start height of the key on. The key
+// is saved to the file given to LoadDKGVerifierFile, if any. Implements
+// DKGVerifierKeyStore.
+func (pv *FilePV) SetDKGVerifierKey(key *DKGVerifierKey) error {
+	if err := pv.setDKGVerifierKey(key); err != nil {
+		return err
+	}
+	if pv.dkgVerifierFilePath == "" {
+		return nil
+	}
+	return key.Save(pv.dkgVerifierFilePath, pv.Key.PrivKey)
+}
+
+func (pv *FilePV) setDKGVerifierKey(key *DKGVerifierKey) error {
+	keypair, err := key.Keypair()
+	if err != nil {
+		return err
+	}
+	pv.dkgVerifierKey, pv.dkgVerifierKeyShare = key, keypair
+	return nil
+}
+
+// SignRandomShare signs the share of the random data of the given height and
+// round with the BLS key share. It refuses to sign a message other than the
+// one already signed at the height. Implements types.RandomShareSigner.
+func (pv *FilePV) SignRandomShare(chainID string, height int64, round int, msg []byte) ([]byte, error) {
+	if pv.blsShareAt(height) == nil {
+		return nil, errors.New("no BLS key share")
+	}
+	if err := pv.signRandomShare(height, msg); err != nil {
+		return nil, fmt.Errorf("error signing random share: %v", err)
+	}
+	return pv.LastSignState.RandomShare, nil
+}
+
 // SignProposal signs a canonical representation of the proposal, along with
 // the chainID. Implements PrivValidator.
 func (pv *FilePV) SignProposal(chainID string, proposal *types.Proposal) error {
@@ -286,6 +404,9 @@ func (pv *FilePV) Reset() {
 	pv.LastSignState.Step = 0
 	pv.LastSignState.Signature = sig
 	pv.LastSignState.SignBytes = nil
+	pv.LastSignState.RandomShareHeight = 0
+	pv.LastSignState.RandomShareMessage = nil
+	pv.LastSignState.RandomShare = nil
 	pv.Save()
 }
 
@@ -386,6 +507,39 @@ func (pv *FilePV) signProposal(chainID string, proposal *types.Proposal) error {
 	return nil
 }
 
+// signRandomShare checks if the random message is good to sign and signs it,
+// unless it was already signed at the height.
+func (pv *FilePV) signRandomShare(height int64, msg []byte) error {
+	sameHeight, err := pv.LastSignState.CheckRandomShare(height, msg)
+	if err != nil {
+		return err
+	}
+	// We might crash before the precommit carrying the share hits the wal,
+	// causing us to sign the same message again. Use the last share then.
+	if sameHeight {
+		return nil
+	}
+
+	// Only the key share is needed to sign.
+	share, err := blsShare.NewBLSVerifier(nil, pv.blsShareAt(height), 0, 0).Sign(msg)
+	if err != nil {
+		return err
+	}
+	pv.LastSignState.RandomShareHeight = height
+	pv.LastSignState.RandomShareMessage = msg
+	pv.LastSignState.RandomShare = share
+	pv.LastSignState.Save()
+	return nil
+}
+
+// blsShareAt returns the BLS key share used at the given height.
+func (pv *FilePV) blsShareAt(height int64) *blsShare.BLSShare {
+	if pv.dkgVerifierKey != nil && height >= pv.dkgVerifierKey.StartHeight {
+		return pv.dkgVerifierKeyShare
+	}
+	return pv.blsShare
+}
+
 // Persist height/round/step and signature
 func (pv *FilePV) saveSigned(height int64, round int, step int8,
 	signBytes []byte, sig []byte) {
@@ -2,12 +2,14 @@ package privval
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto/ed25519"
@@ -198,6 +200,83 @@ func TestSignVote(t *testing.T) {
 	assert.Equal(sig, vote.Signature)
 }
 
+func TestSignRandomShare(t *testing.T) {
+	tempKeyFile, err := ioutil.TempFile("", "priv_validator_key_")
+	require.Nil(t, err)
+	tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+	require.Nil(t, err)
+
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, 2, 3)
+
+	privVal := GenFilePV(tempKeyFile.Name(), tempStateFile.Name())
+	privVal.Save()
+	_, err = privVal.SignRandomShare("mychainid", 10, 0, []byte("msg"))
+	assert.Error(t, err, "expected error signing without a BLS key share")
+	privVal.SetBLSShare(keyring.Shares[0])
+
+	height := int64(10)
+	msg, otherMsg := []byte("random message"), []byte("other random message")
+
+	// sign a random share for the first time
+	share, err := privVal.SignRandomShare("mychainid", height, 0, msg)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+
+	// the same message can be signed again in a later round
+	share2, err := privVal.SignRandomShare("mychainid", height, 1, msg)
+	require.NoError(t, err)
+	assert.Equal(t, share, share2)
+
+	// now try some conflicting messages
+	_, err = privVal.SignRandomShare("mychainid", height, 1, otherMsg)
+	assert.Error(t, err, "expected error on signing a different message at the same height")
+	_, err = privVal.SignRandomShare("mychainid", height-1, 0, otherMsg)
+	assert.Error(t, err, "expected error on height regression")
+
+	// the last random share survives a restart
+	privVal = LoadFilePV(tempKeyFile.Name(), tempStateFile.Name())
+	privVal.SetBLSShare(keyring.Shares[0])
+	_, err = privVal.SignRandomShare("mychainid", height, 0, otherMsg)
+	assert.Error(t, err, "expected error on signing a different message after a restart")
+	share2, err = privVal.SignRandomShare("mychainid", height, 0, msg)
+	require.NoError(t, err)
+	assert.Equal(t, share, share2)
+
+	// the next height has a new message
+	share2, err = privVal.SignRandomShare("mychainid", height+1, 0, otherMsg)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomShare("", otherMsg, share2))
+}
+
+func TestLoadBLSShareFile(t *testing.T) {
+	tempKeyFile, err := ioutil.TempFile("", "priv_validator_key_")
+	require.Nil(t, err)
+	tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+	require.Nil(t, err)
+	tempBLSKeyFile, err := ioutil.TempFile("", "bls_key_")
+	require.Nil(t, err)
+
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, 2, 3)
+	shareJSON, err := blsShare.NewBLSShareJSON(keyring.Shares[1])
+	require.NoError(t, err)
+	shareBytes, err := json.Marshal(shareJSON)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(tempBLSKeyFile.Name(), shareBytes, 0600))
+
+	privVal := GenFilePV(tempKeyFile.Name(), tempStateFile.Name())
+	assert.Error(t, privVal.LoadBLSShareFile(tempStateFile.Name()), "expected error loading a file without a BLS key share")
+	require.NoError(t, privVal.LoadBLSShareFile(tempBLSKeyFile.Name()))
+
+	msg := []byte("random message")
+	share, err := privVal.SignRandomShare("mychainid", 10, 0, msg)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+}
+
 func TestSignProposal(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1,6 +1,7 @@
 package privval
 
 import (
+	"github.com/corestario/dkglib/lib/alias"
 	amino "github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/types"
@@ -17,6 +18,14 @@ func RegisterRemoteSignerMsg(cdc *amino.Codec) {
 	cdc.RegisterConcrete(&SignedVoteResponse{}, "tendermint/remotesigner/SignedVoteResponse", nil)
 	cdc.RegisterConcrete(&SignProposalRequest{}, "tendermint/remotesigner/SignProposalRequest", nil)
 	cdc.RegisterConcrete(&SignedProposalResponse{}, "tendermint/remotesigner/SignedProposalResponse", nil)
+	cdc.RegisterConcrete(&SignRandomShareRequest{}, "tendermint/remotesigner/SignRandomShareRequest", nil)
+	cdc.RegisterConcrete(&SignedRandomShareResponse{}, "tendermint/remotesigner/SignedRandomShareResponse", nil)
+	cdc.RegisterConcrete(&SignDKGDataRequest{}, "tendermint/remotesigner/SignDKGDataRequest", nil)
+	cdc.RegisterConcrete(&SignedDKGDataResponse{}, "tendermint/remotesigner/SignedDKGDataResponse", nil)
+	cdc.RegisterConcrete(&DKGVerifierKeyRequest{}, "tendermint/remotesigner/DKGVerifierKeyRequest", nil)
+	cdc.RegisterConcrete(&DKGVerifierKeyResponse{}, "tendermint/remotesigner/DKGVerifierKeyResponse", nil)
+	cdc.RegisterConcrete(&SetDKGVerifierKeyRequest{}, "tendermint/remotesigner/SetDKGVerifierKeyRequest", nil)
+	cdc.RegisterConcrete(&SetDKGVerifierKeyResponse{}, "tendermint/remotesigner/SetDKGVerifierKeyResponse", nil)
 
 	cdc.RegisterConcrete(&PingRequest{}, "tendermint/remotesigner/PingRequest", nil)
 	cdc.RegisterConcrete(&PingResponse{}, "tendermint/remotesigner/PingResponse", nil)
@@ -55,6 +64,54 @@ type SignedProposalResponse struct {
 	Error    *RemoteSignerError
 }
 
+// SignRandomShareRequest is a request to sign the share of the random data of a
+// height with the BLS key share
+type SignRandomShareRequest struct {
+	Height  int64
+	Round   int
+	Message []byte
+}
+
+// SignedRandomShareResponse is a response containing a random share or an error
+type SignedRandomShareResponse struct {
+	Share []byte
+	Error *RemoteSignerError
+}
+
+// SignDKGDataRequest is a request to sign a DKG message
+type SignDKGDataRequest struct {
+	Data *alias.DKGData
+}
+
+// SignedDKGDataResponse is a response containing a signed DKG message or an error
+type SignedDKGDataResponse struct {
+	Data  *alias.DKGData
+	Error *RemoteSignerError
+}
+
+// DKGVerifierKeyRequest requests the key of the last DKG round, without its
+// BLS key share, from the remote signer
+type DKGVerifierKeyRequest struct{}
+
+// DKGVerifierKeyResponse is a response containing the key of the last DKG round,
+// nil if there is none, or an error
+type DKGVerifierKeyResponse struct {
+	Key   *DKGVerifierKey
+	Error *RemoteSignerError
+}
+
+// SetDKGVerifierKeyRequest is a request to keep the key of a new DKG round and
+// sign the random shares with its BLS key share from its start height on
+type SetDKGVerifierKeyRequest struct {
+	Key *DKGVerifierKey
+}
+
+// SetDKGVerifierKeyResponse is a response confirming that the key of a DKG
+// round is kept, or an error
+type SetDKGVerifierKeyResponse struct {
+	Error *RemoteSignerError
+}
+
 // PingRequest is a request to confirm that the connection is alive.
 type PingRequest struct {
 }
@@ -1,8 +1,10 @@
 package privval
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/corestario/dkglib/lib/alias"
 	"github.com/pkg/errors"
 
 	"github.com/tendermint/tendermint/crypto"
@@ -16,6 +18,7 @@ type SignerClient struct {
 }
 
 var _ types.PrivValidator = (*SignerClient)(nil)
+var _ types.RandomShareSigner = (*SignerClient)(nil)
 
 // NewSignerClient returns an instance of SignerClient.
 // it will start the endpoint (if not already started)
@@ -87,9 +90,19 @@ func (sc *SignerClient) GetPubKey() crypto.PubKey {
 	return pubKeyResp.PubKey
 }
 
+// SignData requests a remote signer to sign a vote, a proposal or a DKG
+// message. Other data is not signed by the remote signer.
 func (sc *SignerClient) SignData(chainID string, data types.DataSigner) error {
-	// TODO: @programmer10110 implement this.
-	return nil
+	switch data := data.(type) {
+	case *types.Vote:
+		return sc.SignVote(chainID, data)
+	case *types.Proposal:
+		return sc.SignProposal(chainID, data)
+	case *alias.DKGData:
+		return sc.SignDKGData(chainID, data)
+	default:
+		return fmt.Errorf("remote signer can not sign %T", data)
+	}
 }
 
 // SignVote requests a remote signer to sign a vote
@@ -134,3 +147,88 @@ func (sc *SignerClient) SignProposal(chainID string, proposal *types.Proposal) e
 
 	return nil
 }
+
+// SignDKGData requests a remote signer to sign a DKG message
+func (sc *SignerClient) SignDKGData(chainID string, data *alias.DKGData) error {
+	response, err := sc.endpoint.SendRequest(&SignDKGDataRequest{Data: data})
+	if err != nil {
+		sc.endpoint.Logger.Error("SignerClient::SignDKGData", "err", err)
+		return err
+	}
+
+	resp, ok := response.(*SignedDKGDataResponse)
+	if !ok {
+		sc.endpoint.Logger.Error("SignerClient::SignDKGData", "err", "response != SignedDKGDataResponse")
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	*data = *resp.Data
+
+	return nil
+}
+
+// SignRandomShare requests a remote signer to sign the share of the random
+// data of a height with the BLS key share. Implements types.RandomShareSigner.
+func (sc *SignerClient) SignRandomShare(chainID string, height int64, round int, msg []byte) ([]byte, error) {
+	response, err := sc.endpoint.SendRequest(&SignRandomShareRequest{Height: height, Round: round, Message: msg})
+	if err != nil {
+		sc.endpoint.Logger.Error("SignerClient::SignRandomShare", "err", err)
+		return nil, err
+	}
+
+	resp, ok := response.(*SignedRandomShareResponse)
+	if !ok {
+		sc.endpoint.Logger.Error("SignerClient::SignRandomShare", "err", "response != SignedRandomShareResponse")
+		return nil, ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Share, nil
+}
+
+// DKGVerifierKey requests the key of the last DKG round, without its BLS key
+// share, from a remote signer. Implements DKGVerifierKeyStore.
+func (sc *SignerClient) DKGVerifierKey() (*DKGVerifierKey, error) {
+	response, err := sc.endpoint.SendRequest(&DKGVerifierKeyRequest{})
+	if err != nil {
+		sc.endpoint.Logger.Error("SignerClient::DKGVerifierKey", "err", err)
+		return nil, err
+	}
+
+	resp, ok := response.(*DKGVerifierKeyResponse)
+	if !ok {
+		sc.endpoint.Logger.Error("SignerClient::DKGVerifierKey", "err", "response != DKGVerifierKeyResponse")
+		return nil, ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Key, nil
+}
+
+// SetDKGVerifierKey sends the key of a new DKG round to a remote signer, which
+// keeps it and signs the random shares with its BLS key share from its start
+// height on. Implements DKGVerifierKeyStore.
+func (sc *SignerClient) SetDKGVerifierKey(key *DKGVerifierKey) error {
+	response, err := sc.endpoint.SendRequest(&SetDKGVerifierKeyRequest{Key: key})
+	if err != nil {
+		sc.endpoint.Logger.Error("SignerClient::SetDKGVerifierKey", "err", err)
+		return err
+	}
+
+	resp, ok := response.(*SetDKGVerifierKeyResponse)
+	if !ok {
+		sc.endpoint.Logger.Error("SignerClient::SetDKGVerifierKey", "err", "response != SetDKGVerifierKeyResponse")
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	return nil
+}
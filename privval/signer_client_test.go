@@ -2,9 +2,14 @@ package privval
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/alias"
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -118,6 +123,115 @@ func TestSignerVote(t *testing.T) {
 	}
 }
 
+func TestSignerDKGData(t *testing.T) {
+	for _, tc := range getSignerTestCases(t) {
+		want := &alias.DKGData{Type: alias.DKGPubKey, RoundID: 1, Data: []byte("pub key")}
+		have := &alias.DKGData{Type: alias.DKGPubKey, RoundID: 1, Data: []byte("pub key")}
+
+		defer tc.signerServer.Stop()
+		defer tc.signerClient.Close()
+
+		require.NoError(t, tc.mockPV.SignData(tc.chainID, want))
+		require.NoError(t, tc.signerClient.SignData(tc.chainID, have))
+
+		assert.Equal(t, want.Signature, have.Signature)
+	}
+}
+
+func TestSignerRandomShare(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, 2, 3)
+	msg := []byte("random message")
+
+	for _, tc := range getSignerTestCases(t) {
+		defer tc.signerServer.Stop()
+		defer tc.signerClient.Close()
+
+		// The mock private validator has no BLS key share.
+		_, err := tc.signerClient.SignRandomShare(tc.chainID, 1, 0, msg)
+		require.Error(t, err)
+		assert.IsType(t, &RemoteSignerError{}, err)
+
+		tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+		require.NoError(t, err)
+		defer os.Remove(tempStateFile.Name())
+		filePV := GenFilePV("", tempStateFile.Name())
+		filePV.SetBLSShare(keyring.Shares[1])
+		tc.signerServer.privVal = filePV
+
+		share, err := tc.signerClient.SignRandomShare(tc.chainID, 1, 0, msg)
+		require.NoError(t, err)
+		assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+
+		// The remote signer refuses to sign another message at the height.
+		_, err = tc.signerClient.SignRandomShare(tc.chainID, 1, 1, []byte("other message"))
+		require.Error(t, err)
+		assert.IsType(t, &RemoteSignerError{}, err)
+	}
+}
+
+func TestSignerDKGVerifierKey(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	dkgKeyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	dkgVerifier, err := types.NewBLSVerifier(dkgKeyring.MasterPubKey, dkgKeyring.Shares[1], 2, 3)
+	require.NoError(t, err)
+	key, err := NewDKGVerifierKey(dkgVerifier, 5)
+	require.NoError(t, err)
+	msg := []byte("random message")
+
+	for _, tc := range getSignerTestCases(t) {
+		defer tc.signerServer.Stop()
+		defer tc.signerClient.Close()
+
+		// The mock private validator can not keep the key.
+		err := tc.signerClient.SetDKGVerifierKey(key)
+		require.Error(t, err)
+		assert.IsType(t, &RemoteSignerError{}, err)
+
+		tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+		require.NoError(t, err)
+		defer os.Remove(tempStateFile.Name())
+		tempDir, err := ioutil.TempDir("", "dkg_verifier_")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+		dkgVerifierFile := filepath.Join(tempDir, "dkg_verifier")
+
+		filePV := GenFilePV("", tempStateFile.Name())
+		filePV.SetBLSShare(keyring.Shares[1])
+		require.NoError(t, filePV.LoadDKGVerifierFile(dkgVerifierFile))
+		tc.signerServer.privVal = filePV
+
+		remoteKey, err := tc.signerClient.DKGVerifierKey()
+		require.NoError(t, err)
+		assert.Nil(t, remoteKey)
+
+		require.NoError(t, tc.signerClient.SetDKGVerifierKey(key))
+		remoteKey, err = tc.signerClient.DKGVerifierKey()
+		require.NoError(t, err)
+		assert.Equal(t, key.WithoutShare(), remoteKey)
+		assert.False(t, remoteKey.HasShare())
+
+		// The share of the key is used from its start height on.
+		share, err := tc.signerClient.SignRandomShare(tc.chainID, 4, 0, msg)
+		require.NoError(t, err)
+		assert.NoError(t, blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, 2, 3).VerifyRandomShare("", msg, share))
+		share, err = tc.signerClient.SignRandomShare(tc.chainID, 5, 0, msg)
+		require.NoError(t, err)
+		assert.NoError(t, dkgVerifier.VerifyRandomShare("", msg, share))
+
+		// The key is reloaded by a restarted signer.
+		restartedPV := GenFilePV("", tempStateFile.Name())
+		restartedPV.Key = filePV.Key
+		require.NoError(t, restartedPV.LoadDKGVerifierFile(dkgVerifierFile))
+		loadedKey, err := restartedPV.DKGVerifierKey()
+		require.NoError(t, err)
+		assert.Equal(t, key, loadedKey)
+	}
+}
+
 func TestSignerVoteResetDeadline(t *testing.T) {
 	for _, tc := range getSignerTestCases(t) {
 		ts := time.Now()
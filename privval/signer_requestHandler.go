@@ -1,6 +1,7 @@
 package privval
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/tendermint/tendermint/crypto"
@@ -37,6 +38,56 @@ func DefaultValidationRequestHandler(
 			res = &SignedProposalResponse{r.Proposal, nil}
 		}
 
+	case *SignRandomShareRequest:
+		var share []byte
+		if signer, ok := privVal.(types.RandomShareSigner); ok {
+			share, err = signer.SignRandomShare(chainID, r.Height, r.Round, r.Message)
+		} else {
+			err = errors.New("private validator has no BLS key share")
+		}
+		if err != nil {
+			res = &SignedRandomShareResponse{nil, &RemoteSignerError{0, err.Error()}}
+		} else {
+			res = &SignedRandomShareResponse{share, nil}
+		}
+
+	case *SignDKGDataRequest:
+		err = privVal.SignData(chainID, r.Data)
+		if err != nil {
+			res = &SignedDKGDataResponse{nil, &RemoteSignerError{0, err.Error()}}
+		} else {
+			res = &SignedDKGDataResponse{r.Data, nil}
+		}
+
+	case *DKGVerifierKeyRequest:
+		var key *DKGVerifierKey
+		if store, ok := privVal.(DKGVerifierKeyStore); ok {
+			key, err = store.DKGVerifierKey()
+		} else {
+			err = errors.New("private validator can not keep DKG verifier keys")
+		}
+		if err != nil {
+			res = &DKGVerifierKeyResponse{nil, &RemoteSignerError{0, err.Error()}}
+		} else {
+			if key != nil {
+				// the BLS key share never leaves the signer
+				key = key.WithoutShare()
+			}
+			res = &DKGVerifierKeyResponse{key, nil}
+		}
+
+	case *SetDKGVerifierKeyRequest:
+		if store, ok := privVal.(DKGVerifierKeyStore); ok {
+			err = store.SetDKGVerifierKey(r.Key)
+		} else {
+			err = errors.New("private validator can not keep DKG verifier keys")
+		}
+		if err != nil {
+			res = &SetDKGVerifierKeyResponse{&RemoteSignerError{0, err.Error()}}
+		} else {
+			res = &SetDKGVerifierKeyResponse{nil}
+		}
+
 	case *PingRequest:
 		err, res = nil, &PingResponse{}
 
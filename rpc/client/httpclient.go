@@ -309,6 +309,37 @@ func (c *baseRPCClient) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return result, nil
 }
 
+func (c *baseRPCClient) Random(height *int64) (*ctypes.ResultRandom, error) {
+	result := new(ctypes.ResultRandom)
+	_, err := c.caller.Call("random", map[string]interface{}{"height": height}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "Random")
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) RandomRange(minHeight, maxHeight int64) (*ctypes.ResultRandomRange, error) {
+	result := new(ctypes.ResultRandomRange)
+	_, err := c.caller.Call("random_range",
+		map[string]interface{}{"minHeight": minHeight, "maxHeight": maxHeight},
+		result)
+	if err != nil {
+		return nil, errors.Wrap(err, "RandomRange")
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) RandomEpochs(minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error) {
+	result := new(ctypes.ResultRandomEpochs)
+	_, err := c.caller.Call("random_epochs",
+		map[string]interface{}{"minEpoch": minEpoch, "maxEpoch": maxEpoch},
+		result)
+	if err != nil {
+		return nil, errors.Wrap(err, "RandomEpochs")
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	result := new(ctypes.ResultTx)
 	params := map[string]interface{}{
@@ -66,6 +66,9 @@ type SignClient interface {
 	Block(height *int64) (*ctypes.ResultBlock, error)
 	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
 	Commit(height *int64) (*ctypes.ResultCommit, error)
+	Random(height *int64) (*ctypes.ResultRandom, error)
+	RandomRange(minHeight, maxHeight int64) (*ctypes.ResultRandomRange, error)
+	RandomEpochs(minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error)
 	Validators(height *int64) (*ctypes.ResultValidators, error)
 	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
 	TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error)
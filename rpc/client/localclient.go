@@ -151,6 +151,18 @@ func (c *Local) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return core.Commit(c.ctx, height)
 }
 
+func (c *Local) Random(height *int64) (*ctypes.ResultRandom, error) {
+	return core.Random(c.ctx, height)
+}
+
+func (c *Local) RandomRange(minHeight, maxHeight int64) (*ctypes.ResultRandomRange, error) {
+	return core.RandomRange(c.ctx, minHeight, maxHeight)
+}
+
+func (c *Local) RandomEpochs(minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error) {
+	return core.RandomEpochs(c.ctx, minEpoch, maxEpoch)
+}
+
 func (c *Local) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	return core.Validators(c.ctx, height)
 }
@@ -149,6 +149,18 @@ func (c Client) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return core.Commit(&rpctypes.Context{}, height)
 }
 
+func (c Client) Random(height *int64) (*ctypes.ResultRandom, error) {
+	return core.Random(&rpctypes.Context{}, height)
+}
+
+func (c Client) RandomRange(minHeight, maxHeight int64) (*ctypes.ResultRandomRange, error) {
+	return core.RandomRange(&rpctypes.Context{}, minHeight, maxHeight)
+}
+
+func (c Client) RandomEpochs(minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error) {
+	return core.RandomEpochs(&rpctypes.Context{}, minEpoch, maxEpoch)
+}
+
 func (c Client) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	return core.Validators(&rpctypes.Context{}, height)
 }
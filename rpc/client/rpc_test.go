@@ -263,6 +263,13 @@ func TestAppCalls(t *testing.T) {
 		require.Nil(err, "%d: %+v", i, err)
 		assert.Equal(block.Block.LastCommit, commit2.Commit)
 
+		// the random value comes with the shares it was recovered from
+		random, err := c.Random(&h)
+		require.Nil(err, "%d: %+v", i, err)
+		assert.EqualValues(h, random.Height)
+		assert.Equal(commit2.Header.RandomData, random.RandomData)
+		assert.NotEmpty(random.Shares)
+
 		// and we got a proof that works!
 		_pres, err := c.ABCIQueryWithOptions("/key", k, client.ABCIQueryOptions{Prove: true})
 		pres := _pres.Response
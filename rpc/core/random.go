@@ -0,0 +1,201 @@
+package core
+
+import (
+	"fmt"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Random gets the random beacon value at a given height together with
+// everything needed to re-verify it offline: the message signed by the
+// validators, the BLS master public key and the precommit signature shares
+// the aggregate signature was recovered from.
+// If no height is provided, it will fetch the latest random value.
+// Fails if the seed of the height is not available, i.e. the ABCI responses of
+// the previous height are not saved.
+// More: https://tendermint.com/rpc/#/Info/random
+func Random(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultRandom, error) {
+	storeHeight := blockStore.Height()
+	height, err := getHeight(storeHeight, heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadRandom(storeHeight, height)
+}
+
+// RandomRange gets random beacon values for minHeight <= height <= maxHeight.
+// Values are returned in descending order (highest first).
+// More: https://tendermint.com/rpc/#/Info/random_range
+func RandomRange(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.ResultRandomRange, error) {
+
+	// maximum 20 random values
+	const limit int64 = 20
+	storeHeight := blockStore.Height()
+	minHeight, maxHeight, err := filterMinMax(storeHeight, minHeight, maxHeight, limit)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("RandomRangeHandler", "maxHeight", maxHeight, "minHeight", minHeight)
+
+	randoms := []*ctypes.ResultRandom{}
+	for height := maxHeight; height >= minHeight; height-- {
+		random, err := loadRandom(storeHeight, height)
+		if err != nil {
+			return nil, err
+		}
+		randoms = append(randoms, random)
+	}
+
+	return &ctypes.ResultRandomRange{
+		LastHeight: storeHeight,
+		Randoms:    randoms}, nil
+}
+
+// RandomEpochs gets the key epochs of the random beacon for
+// minEpoch <= epoch <= maxEpoch.
+// Epochs are returned in descending order (highest first).
+// More: https://tendermint.com/rpc/#/Info/random_epochs
+func RandomEpochs(ctx *rpctypes.Context, minEpoch, maxEpoch int64) (*ctypes.ResultRandomEpochs, error) {
+
+	// maximum 20 epochs
+	const limit int64 = 20
+	lastEpoch := sm.LastRandomBeaconEpoch(stateDB)
+	if lastEpoch == 0 {
+		return &ctypes.ResultRandomEpochs{Epochs: []*sm.RandomBeaconEpoch{}}, nil
+	}
+	minEpoch, maxEpoch, err := filterMinMax(lastEpoch, minEpoch, maxEpoch, limit)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("RandomEpochsHandler", "maxEpoch", maxEpoch, "minEpoch", minEpoch)
+
+	epochs := []*sm.RandomBeaconEpoch{}
+	for epoch := maxEpoch; epoch >= minEpoch; epoch-- {
+		e := sm.LoadRandomBeaconEpochByNumber(stateDB, epoch)
+		if e == nil {
+			return nil, sm.ErrNoRandomBeaconEpoch{Epoch: epoch}
+		}
+		epochs = append(epochs, e)
+	}
+
+	return &ctypes.ResultRandomEpochs{
+		LastEpoch: lastEpoch,
+		Epochs:    epochs}, nil
+}
+
+func loadRandom(storeHeight, height int64) (*ctypes.ResultRandom, error) {
+	blockMeta := blockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, fmt.Errorf("no block meta found for height %d", height)
+	}
+
+	// The random value at height H is the aggregate signature of the random
+	// value at H-1 followed by the seed returned by EndBlock at H-1.
+	prevRandomData := []byte(types.InitialRandomData)
+	var seed []byte
+	if height > 1 {
+		prevMeta := blockStore.LoadBlockMeta(height - 1)
+		if prevMeta == nil {
+			return nil, fmt.Errorf("no block meta found for height %d", height-1)
+		}
+		prevRandomData = prevMeta.Header.RandomData
+
+		abciResponses, err := sm.LoadABCIResponses(stateDB, height-1)
+		if _, ok := err.(sm.ErrNoABCIResponsesForHeight); ok {
+			// The responses are pruned, or were never saved by a node that
+			// started from a state sync snapshot, so the signed message can not
+			// be rebuilt.
+			return nil, fmt.Errorf("seed not available for height %d: no ABCI responses saved for height %d",
+				height, height-1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		seed = abciResponses.EndBlock.GetSeed()
+	}
+
+	// If the next block has not been committed yet,
+	// use a non-canonical commit
+	canonical := height != storeHeight
+	var commit *types.Commit
+	if canonical {
+		commit = blockStore.LoadBlockCommit(height)
+	} else {
+		commit = blockStore.LoadSeenCommit(height)
+	}
+
+	// The commits saved by the block store mark the shares the random data was
+	// recovered from, see Commit.AggregateSigners. The seen commit marks them if
+	// the canonical commit does not. Once they are verified, only those are
+	// returned.
+	signersCommit := commit
+	if signersCommit == nil || signersCommit.AggregateSigners == nil {
+		signersCommit = blockStore.LoadSeenCommit(height)
+	}
+	if signersCommit != nil && signersCommit.AggregateSigners != nil {
+		err := sm.VerifyRandomDataSigners(stateDB, height, prevRandomData, seed, blockMeta.Header.RandomData,
+			signersCommit)
+		switch err.(type) {
+		case nil:
+			canonical = canonical && signersCommit == commit
+			commit = signersCommit
+		case sm.ErrNoRandomBeaconEpochForHeight:
+			// There is no key to verify the random data with.
+		default:
+			return nil, fmt.Errorf("failed to verify the signers of the random data at height %d: %v", height, err)
+		}
+	}
+
+	result := &ctypes.ResultRandom{
+		Height:          height,
+		RandomData:      blockMeta.Header.RandomData,
+		RandomHash:      blockMeta.Header.RandomHash,
+		PrevRandomData:  prevRandomData,
+		Seed:            seed,
+		SignedMessage:   types.MakeRandomMessage(prevRandomData, seed),
+		BLSMasterPubKey: genDoc.BLSMasterPubKey,
+		BLSThreshold:    genDoc.BLSThreshold,
+		BLSNumShares:    genDoc.BLSNumShares,
+		Shares:          randomShares(commit),
+		CanonicalCommit: canonical,
+	}
+	// DKG rounds replace the master public key from the genesis file.
+	if epoch, err := sm.LoadRandomBeaconEpoch(stateDB, height); err == nil {
+		result.BLSMasterPubKey = epoch.MasterPubKey
+		result.BLSThreshold = epoch.Threshold
+		result.BLSNumShares = epoch.NumShares
+	}
+	return result, nil
+}
+
+// randomShares extracts the BLS signature shares from the precommits for the
+// committed block. Precommits for nil or for other blocks are skipped, as they
+// are skipped by Verifier.Recover, and so are the precommits which are not
+// marked by the aggregate signers of the commit, if any.
+func randomShares(commit *types.Commit) []ctypes.RandomShare {
+	shares := []ctypes.RandomShare{}
+	if commit == nil {
+		return shares
+	}
+	for idx, precommit := range commit.Precommits {
+		if precommit == nil || len(precommit.BLSSignature) == 0 {
+			continue
+		}
+		if !precommit.BlockID.Equals(commit.BlockID) {
+			continue
+		}
+		if commit.AggregateSigners != nil && !commit.AggregateSigners.GetIndex(idx) {
+			continue
+		}
+		shares = append(shares, ctypes.RandomShare{
+			ValidatorAddress: precommit.ValidatorAddress,
+			ValidatorIndex:   idx,
+			BLSSignature:     precommit.BLSSignature,
+		})
+	}
+	return shares
+}
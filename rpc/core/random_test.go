@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestRandomShares(t *testing.T) {
+	blockID := types.BlockID{Hash: []byte("block")}
+	otherID := types.BlockID{Hash: []byte("other")}
+
+	commit := types.NewCommit(blockID, []*types.CommitSig{
+		{BlockID: blockID, ValidatorAddress: []byte("val0"), BLSSignature: []byte{0}},
+		nil,
+		{BlockID: otherID, ValidatorAddress: []byte("val2"), BLSSignature: []byte{2}},
+		{BlockID: blockID, ValidatorAddress: []byte("val3")},
+		{BlockID: blockID, ValidatorAddress: []byte("val4"), BLSSignature: []byte{4}},
+	})
+
+	shares := randomShares(commit)
+	if assert.Len(t, shares, 2) {
+		assert.Equal(t, 0, shares[0].ValidatorIndex)
+		assert.EqualValues(t, []byte{0}, shares[0].BLSSignature)
+		assert.Equal(t, 4, shares[1].ValidatorIndex)
+		assert.EqualValues(t, []byte("val4"), shares[1].ValidatorAddress)
+	}
+
+	assert.Empty(t, randomShares(nil))
+}
+
+func TestRandomEpochs(t *testing.T) {
+	SetStateDB(dbm.NewMemDB())
+
+	res, err := RandomEpochs(&rpctypes.Context{}, 0, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, res.LastEpoch)
+	assert.Empty(t, res.Epochs)
+
+	for epoch := int64(1); epoch <= 30; epoch++ {
+		require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{
+			Epoch:       epoch,
+			StartHeight: epoch * 10,
+		}))
+	}
+
+	cases := []struct {
+		min, max int64
+		first    int64
+		last     int64
+		wantErr  bool
+	}{
+		{0, 0, 30, 11, false},
+		{1, 5, 5, 1, false},
+		{25, 100, 30, 25, false},
+		{5, 1, 0, 0, true},
+		{-1, 5, 0, 0, true},
+	}
+	for i, c := range cases {
+		res, err := RandomEpochs(&rpctypes.Context{}, c.min, c.max)
+		if c.wantErr {
+			assert.Error(t, err, "#%d", i)
+			continue
+		}
+		require.NoError(t, err, "#%d", i)
+		assert.EqualValues(t, 30, res.LastEpoch, "#%d", i)
+		if assert.Len(t, res.Epochs, int(c.first-c.last+1), "#%d", i) {
+			assert.Equal(t, c.first, res.Epochs[0].Epoch, "#%d", i)
+			assert.Equal(t, c.last, res.Epochs[len(res.Epochs)-1].Epoch, "#%d", i)
+			assert.Equal(t, c.first*10, res.Epochs[0].StartHeight, "#%d", i)
+		}
+	}
+}
+
+// The seed of a height is returned by EndBlock for the previous height, so the
+// random data can not be checked once its ABCI responses are pruned.
+func TestRandomSeedNotAvailable(t *testing.T) {
+	SetStateDB(dbm.NewMemDB())
+	SetGenesisDoc(&types.GenesisDoc{})
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	SetBlockStore(bs)
+
+	lastCommit := types.NewCommit(types.BlockID{}, nil)
+	for height := int64(1); height <= 2; height++ {
+		block := types.MakeBlock(height, nil, lastCommit, nil)
+		seenCommit := types.NewCommit(types.BlockID{Hash: block.Hash()},
+			[]*types.CommitSig{{Height: height, BlockID: types.BlockID{Hash: block.Hash()}}})
+		bs.SaveBlock(block, block.MakePartSet(2), seenCommit)
+		lastCommit = seenCommit
+	}
+
+	height := int64(1)
+	res, err := Random(&rpctypes.Context{}, &height)
+	require.NoError(t, err)
+	assert.Empty(t, res.Seed)
+
+	wantErr := fmt.Errorf("seed not available for height 2: no ABCI responses saved for height 1")
+	height = 2
+	_, err = Random(&rpctypes.Context{}, &height)
+	assert.Equal(t, wantErr, err)
+	_, err = RandomRange(&rpctypes.Context{}, 1, 2)
+	assert.Equal(t, wantErr, err)
+}
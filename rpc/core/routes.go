@@ -21,6 +21,9 @@ var Routes = map[string]*rpc.RPCFunc{
 	"block":                rpc.NewRPCFunc(Block, "height"),
 	"block_results":        rpc.NewRPCFunc(BlockResults, "height"),
 	"commit":               rpc.NewRPCFunc(Commit, "height"),
+	"random":               rpc.NewRPCFunc(Random, "height"),
+	"random_range":         rpc.NewRPCFunc(RandomRange, "minHeight,maxHeight"),
+	"random_epochs":        rpc.NewRPCFunc(RandomEpochs, "minEpoch,maxEpoch"),
 	"tx":                   rpc.NewRPCFunc(Tx, "hash,prove"),
 	"tx_search":            rpc.NewRPCFunc(TxSearch, "query,prove,page,per_page"),
 	"validators":           rpc.NewRPCFunc(Validators, "height"),
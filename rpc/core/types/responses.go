@@ -41,6 +41,45 @@ type ResultBlockResults struct {
 	Results *state.ABCIResponses `json:"results"`
 }
 
+// Random beacon value for a height with the data required
+// to verify its threshold signature offline
+type ResultRandom struct {
+	Height     int64        `json:"height"`
+	RandomData []byte       `json:"random_data"`
+	RandomHash cmn.HexBytes `json:"random_hash"`
+
+	// SignedMessage is the message the shares sign: PrevRandomData
+	// followed by Seed.
+	PrevRandomData []byte `json:"prev_random_data"`
+	Seed           []byte `json:"seed"`
+	SignedMessage  []byte `json:"signed_message"`
+
+	BLSMasterPubKey string        `json:"bls_master_pub_key"`
+	BLSThreshold    int           `json:"bls_threshold"`
+	BLSNumShares    int           `json:"bls_num_shares"`
+	Shares          []RandomShare `json:"shares"`
+	CanonicalCommit bool          `json:"canonical"`
+}
+
+// A BLS signature share from a precommit
+type RandomShare struct {
+	ValidatorAddress types.Address `json:"validator_address"`
+	ValidatorIndex   int           `json:"validator_index"`
+	BLSSignature     []byte        `json:"bls_signature"`
+}
+
+// List of random beacon values
+type ResultRandomRange struct {
+	LastHeight int64           `json:"last_height"`
+	Randoms    []*ResultRandom `json:"randoms"`
+}
+
+// List of random beacon key epochs
+type ResultRandomEpochs struct {
+	LastEpoch int64                      `json:"last_epoch"`
+	Epochs    []*state.RandomBeaconEpoch `json:"epochs"`
+}
+
 // NewResultCommit is a helper to initialize the ResultCommit with
 // the embedded struct
 func NewResultCommit(header *types.Header, commit *types.Commit,
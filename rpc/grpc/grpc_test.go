@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	dkgTypes "github.com/corestario/dkglib/lib/types"
+	dkgOffChain "github.com/corestario/dkglib/lib/offChain"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	core_grpc "github.com/tendermint/tendermint/rpc/grpc"
 	rpctest "github.com/tendermint/tendermint/rpc/test"
@@ -17,7 +17,7 @@ func TestMain(m *testing.M) {
 	// start a tendermint node in the background to test against
 	app := kvstore.NewKVStoreApplication()
 	node := rpctest.StartTendermint(app)
-	node.GetConsensusState().SetVerifier(&dkgTypes.MockVerifier{})
+	node.GetConsensusState().SetVerifier(dkgOffChain.GetVerifier(1, 1)("grpc_tests", 0))
 
 	code := m.Run()
 
@@ -43,6 +43,14 @@ type (
 	ErrNoABCIResponsesForHeight struct {
 		Height int64
 	}
+
+	ErrNoRandomBeaconEpochForHeight struct {
+		Height int64
+	}
+
+	ErrNoRandomBeaconEpoch struct {
+		Epoch int64
+	}
 )
 
 func (e ErrUnknownBlock) Error() string {
@@ -89,3 +97,11 @@ func (e ErrNoConsensusParamsForHeight) Error() string {
 func (e ErrNoABCIResponsesForHeight) Error() string {
 	return fmt.Sprintf("Could not find results for height #%d", e.Height)
 }
+
+func (e ErrNoRandomBeaconEpochForHeight) Error() string {
+	return fmt.Sprintf("Could not find random beacon epoch for height #%d", e.Height)
+}
+
+func (e ErrNoRandomBeaconEpoch) Error() string {
+	return fmt.Sprintf("Could not find random beacon epoch #%d", e.Epoch)
+}
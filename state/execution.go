@@ -5,13 +5,17 @@ import (
 	"math/big"
 	"time"
 
+	dkgtypes "github.com/corestario/dkglib/lib/types"
+
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/fail"
 	"github.com/tendermint/tendermint/libs/log"
 	mempl "github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
 //-----------------------------------------------------------------------------
@@ -89,9 +93,11 @@ func (blockExec *BlockExecutor) SetEventBus(eventBus types.BlockEventPublisher)
 // and txs from the mempool. The max bytes must be big enough to fit the commit.
 // Up to 1/10th of the block space is allcoated for maximum sized evidence.
 // The rest is given to txs, up to the max gas.
+// The block also commits the given random beacon key change, which may be nil.
 func (blockExec *BlockExecutor) CreateProposalBlock(
 	height int64,
 	state State, commit *types.Commit,
+	keyChange *types.RandomBeaconKeyChange,
 	proposerAddr []byte,
 ) (*types.Block, *types.PartSet) {
 
@@ -104,9 +110,12 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 
 	// Fetch a limited amount of valid txs
 	maxDataBytes := types.MaxDataBytes(maxBytes, state.Validators.Size(), len(evidence))
+	if keyChange != nil {
+		maxDataBytes -= int64(len(cdc.MustMarshalBinaryLengthPrefixed(keyChange)))
+	}
 	txs := blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas)
 
-	return state.MakeBlock(height, txs, commit, evidence, proposerAddr)
+	return state.makeBlock(height, txs, commit, evidence, keyChange, proposerAddr)
 }
 
 // ValidateBlock validates the given block against the given state.
@@ -117,6 +126,75 @@ func (blockExec *BlockExecutor) ValidateBlock(state State, block *types.Block) e
 	return validateBlock(blockExec.evpool, blockExec.db, state, block)
 }
 
+// VerifyRandomData verifies the random data of the next block of the state
+// against the random beacon key of the block, see State. If the chain has no
+// key yet, fallback is used instead if it is not nil.
+func (blockExec *BlockExecutor) VerifyRandomData(state State, block *types.Block, prevRandomData []byte,
+	fallback dkgtypes.Verifier) error {
+	verifier, err := randomDataVerifier(state, block, fallback)
+	if err != nil || verifier == nil {
+		return err
+	}
+	return verifier.VerifyRandomData(types.MakeRandomMessage(prevRandomData, state.Seed), block.RandomData)
+}
+
+// RandomDataSigners returns the signers of the random data of the next block
+// of the state, the precommits of commit that carry a valid random share for
+// it, to be saved with commit as the seen commit of the block. Returns nil if
+// the block has no random data or no key to verify it with, see
+// VerifyRandomData.
+func (blockExec *BlockExecutor) RandomDataSigners(state State, block *types.Block, commit *types.Commit,
+	fallback dkgtypes.Verifier) *cmn.BitArray {
+	if len(block.RandomData) == 0 {
+		return nil
+	}
+	verifier, err := randomDataVerifier(state, block, fallback)
+	if err != nil || verifier == nil {
+		return nil
+	}
+	return randomDataSigners(verifier, types.MakeRandomMessage(state.LastRandomData, state.Seed), commit)
+}
+
+// randomDataVerifier returns the verifier of the random data of the next block
+// of the state, see VerifyRandomData, or nil if there is none.
+func randomDataVerifier(state State, block *types.Block, fallback dkgtypes.Verifier) (dkgtypes.Verifier, error) {
+	key := state.randomBeaconKey(block.RandomBeaconKeyChange)
+	if key.IsEmpty() {
+		if fallback == nil || fallback.IsNil() {
+			return nil, nil
+		}
+		return fallback, nil
+	}
+	verifier, err := key.Verifier(nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid random beacon key: %v", err)
+	}
+	return verifier, nil
+}
+
+// randomDataSigners marks the precommits of commit for its block which carry
+// a valid random share for msg. As in consensus, shares with an index that was
+// already seen are skipped.
+func randomDataSigners(verifier dkgtypes.Verifier, msg []byte, commit *types.Commit) *cmn.BitArray {
+	signers := cmn.NewBitArray(len(commit.Precommits))
+	seen := make(map[int]bool)
+	for idx, precommit := range commit.Precommits {
+		if precommit == nil || len(precommit.BLSSignature) == 0 || !precommit.BlockID.Equals(commit.BlockID) {
+			continue
+		}
+		index, err := tbls.SigShare(precommit.BLSSignature).Index()
+		if err != nil || seen[index] {
+			continue
+		}
+		if err := verifier.VerifyRandomShare(precommit.ValidatorAddress.String(), msg, precommit.BLSSignature); err != nil {
+			continue
+		}
+		seen[index] = true
+		signers.SetIndex(idx, true)
+	}
+	return signers
+}
+
 // ApplyBlock validates the block against the state, executes it against the app,
 // fires the relevant events, commits the app, and saves the new state and responses.
 // It's the only function that needs to be called
@@ -143,9 +221,6 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 	fail.Fail() // XXX
 
-	// The response seed may be nil.
-	state.Seed = abciResponses.EndBlock.GetSeed()
-
 	// validate the validator updates and convert to tendermint types
 	abciValUpdates := abciResponses.EndBlock.ValidatorUpdates
 	err = validateValidatorUpdates(abciValUpdates, state.ConsensusParams.Validator)
@@ -165,6 +240,7 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 	if err != nil {
 		return state, fmt.Errorf("Commit failed for application: %v", err)
 	}
+	state.RandomBeaconEpoch = nextRandomBeaconEpoch(state.RandomBeaconEpoch, block)
 	blockExec.logger.Debug("state update in applyBlock", "validators", state.Validators)
 
 	// Lock mempool, commit app state, update mempoool.
@@ -453,6 +529,9 @@ func updateState(
 		LastHeightConsensusParamsChanged: lastHeightParamsChanged,
 		LastResultsHash:                  abciResponses.ResultsHash(),
 		AppHash:                          nil,
+		Seed:                             abciResponses.EndBlock.GetSeed(),
+		RandomBeaconEpoch:                state.RandomBeaconEpoch,
+		LastRandomData:                   header.RandomData,
 	}, nil
 }
 
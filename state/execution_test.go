@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
@@ -119,6 +120,8 @@ func TestBeginBlockByzantineValidators(t *testing.T) {
 	height2, idx2, val2 := int64(3), 1, state.Validators.Validators[1].Address
 	ev1 := types.NewMockGoodEvidence(height1, idx1, val1)
 	ev2 := types.NewMockGoodEvidence(height2, idx2, val2)
+	ev3 := types.NewDKGEvidenceCorruptData(state.Validators.Validators[0].PubKey, height1,
+		types.DKGMessage{Addr: val1, Data: []byte("corrupt")})
 
 	now := tmtime.Now()
 	valSet := state.Validators
@@ -132,6 +135,7 @@ func TestBeginBlockByzantineValidators(t *testing.T) {
 		{"multiple byzantine", []types.Evidence{ev1, ev2}, []abci.Evidence{
 			types.TM2PB.Evidence(ev1, valSet, now),
 			types.TM2PB.Evidence(ev2, valSet, now)}},
+		{"dkg byzantine", []types.Evidence{ev3}, []abci.Evidence{types.TM2PB.Evidence(ev3, valSet, now)}},
 	}
 
 	commitSig0 := (&types.Vote{ValidatorIndex: 0, Timestamp: now, Type: types.PrecommitType}).CommitSig()
@@ -365,6 +369,132 @@ func TestEndBlockValidatorUpdates(t *testing.T) {
 	}
 }
 
+// TestEndBlockSeed ensures the seed returned by EndBlock is kept in the state
+// and survives saving and loading it.
+func TestEndBlockSeed(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB, _ := makeState(1, 1)
+
+	blockExec := sm.NewBlockExecutor(
+		stateDB,
+		log.TestingLogger(),
+		proxyApp.Consensus(),
+		mock.Mempool{},
+		sm.MockEvidencePool{},
+	)
+
+	block := makeBlock(state, 1)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: block.MakePartSet(testPartSize).Header()}
+
+	app.Seed = []byte("seed")
+	state, err = blockExec.ApplyBlock(state, blockID, block)
+	require.Nil(t, err)
+	assert.Equal(t, app.Seed, state.Seed)
+	assert.Equal(t, app.Seed, state.Copy().Seed)
+	assert.Equal(t, app.Seed, sm.LoadState(stateDB).Seed)
+}
+
+// TestApplyBlockRandomBeaconKeyChange ensures that the random beacon epochs
+// are derived from the key changes committed in the blocks.
+func TestApplyBlockRandomBeaconKeyChange(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB, privVals := makeState(1, 1)
+	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(),
+		mock.Mempool{}, sm.MockEvidencePool{})
+	proposerAddr := state.Validators.GetProposer().Address
+
+	keyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	key, err := types.NewRandomBeaconKey(keyring.MasterPubKey, keyring.T, keyring.N)
+	require.NoError(t, err)
+	change := &types.RandomBeaconKeyChange{Key: key, Participants: []types.Address{proposerAddr}}
+
+	// the block must commit the key its random data is signed with
+	block, _ := blockExec.CreateProposalBlock(1, state, new(types.Commit), change, proposerAddr)
+	assert.EqualValues(t, key.Hash(), block.RandomBeaconKeyHash)
+	block.RandomBeaconKeyHash = nil
+	err = blockExec.ValidateBlock(state, block)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RandomBeaconKeyHash")
+
+	// the first key of the chain is used from the block committing it on
+	block, _ = blockExec.CreateProposalBlock(1, state, new(types.Commit), change, proposerAddr)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: block.MakePartSet(testPartSize).Header()}
+	state, err = blockExec.ApplyBlock(state, blockID, block)
+	require.Nil(t, err)
+	assert.EqualValues(t, 1, state.RandomBeaconEpoch.Epoch)
+	assert.EqualValues(t, 1, state.RandomBeaconEpoch.StartHeight)
+	assert.Equal(t, key, state.RandomBeaconEpoch.Key())
+	epoch, err := sm.LoadRandomBeaconEpoch(stateDB, 1)
+	require.NoError(t, err)
+	assert.Equal(t, state.RandomBeaconEpoch, *epoch)
+	assert.Equal(t, state.RandomBeaconEpoch, sm.LoadState(stateDB).RandomBeaconEpoch)
+
+	// the key in use can not be committed again
+	commit, err := makeValidCommit(1, blockID, state.LastValidators, privVals)
+	require.NoError(t, err)
+	block, _ = blockExec.CreateProposalBlock(2, state, commit, change, proposerAddr)
+	err = blockExec.ValidateBlock(state, block)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not change the key")
+	block, _ = blockExec.CreateProposalBlock(2, state, commit, nil, proposerAddr)
+	assert.EqualValues(t, key.Hash(), block.RandomBeaconKeyHash)
+	assert.NoError(t, blockExec.ValidateBlock(state, block))
+}
+
+func TestRandomDataSigners(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB, _ := makeState(1, 1)
+	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(),
+		mock.Mempool{}, sm.MockEvidencePool{})
+	proposerAddr := state.Validators.GetProposer().Address
+
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	key, err := types.NewRandomBeaconKey(keyring.MasterPubKey, keyring.T, keyring.N)
+	require.NoError(t, err)
+	change := &types.RandomBeaconKeyChange{Key: key, Participants: []types.Address{proposerAddr}}
+	block, _ := blockExec.CreateProposalBlock(1, state, new(types.Commit), change, proposerAddr)
+
+	msg := types.MakeRandomMessage(state.LastRandomData, state.Seed)
+	sign := func(i int, msg []byte) []byte {
+		verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[i], keyring.T, keyring.N)
+		share, err := verifier.Sign(msg)
+		require.NoError(t, err)
+		return share
+	}
+	blockID := types.BlockID{Hash: block.Hash()}
+	commit := types.NewCommit(blockID, []*types.CommitSig{
+		{BlockID: blockID, BLSSignature: sign(0, msg)},
+		{BlockID: blockID, BLSSignature: sign(1, []byte("other message"))},
+		nil,
+		{BlockID: blockID, BLSSignature: sign(2, msg)},
+		{BlockID: blockID, BLSSignature: sign(0, msg)},
+	})
+	assert.Nil(t, blockExec.RandomDataSigners(state, block, commit, nil), "expected no signers without random data")
+
+	block.RandomData = recoverRandomData(t, keyring, msg)
+	signers := blockExec.RandomDataSigners(state, block, commit, nil)
+	require.NotNil(t, signers)
+	assert.Equal(t, "BA{5:x__x_}", signers.String())
+}
+
 // TestEndBlockValidatorUpdatesResultingInEmptySet checks that processing validator updates that
 // would result in empty set causes no panic, an error is raised and NextValidators is not updated
 func TestEndBlockValidatorUpdatesResultingInEmptySet(t *testing.T) {
@@ -60,3 +60,9 @@ func SaveConsensusParamsInfo(db dbm.DB, nextHeight, changeHeight int64, params t
 func SaveValidatorsInfo(db dbm.DB, height, lastHeightChanged int64, valSet *types.ValidatorSet) {
 	saveValidatorsInfo(db, height, lastHeightChanged, valSet)
 }
+
+// CalcRandomBeaconEpochKey is an alias for the private calcRandomBeaconEpochKey
+// method in store.go, exported exclusively and explicitly for testing.
+func CalcRandomBeaconEpochKey(epoch int64) []byte {
+	return calcRandomBeaconEpochKey(epoch)
+}
@@ -13,7 +13,8 @@ import (
 
 // database keys
 var (
-	stateKey = []byte("stateKey")
+	stateKey                 = []byte("stateKey")
+	lastRandomBeaconEpochKey = []byte("lastRandomBeaconEpochKey")
 )
 
 //-----------------------------------------------------------------------------
@@ -82,8 +83,19 @@ type State struct {
 	// the latest AppHash we've received from calling abci.Commit()
 	AppHash []byte
 
-	// Seed
+	// Seed returned by the application in EndBlock for LastBlockHeight.
+	// It is appended to the random data of the last block to form the message
+	// the validators sign to produce the random data of the next block.
+	// May be nil.
 	Seed []byte
+
+	// The random beacon epoch of the next block, whose key the random data of
+	// the block is signed with. The first epoch has the key of the genesis
+	// file, if any, the next ones the keys committed by the blocks.
+	RandomBeaconEpoch RandomBeaconEpoch
+
+	// Random data of the last block.
+	LastRandomData []byte
 }
 
 // Copy makes a copy of the State for mutating.
@@ -108,6 +120,11 @@ func (state State) Copy() State {
 		AppHash: state.AppHash,
 
 		LastResultsHash: state.LastResultsHash,
+
+		Seed: state.Seed,
+
+		RandomBeaconEpoch: state.RandomBeaconEpoch,
+		LastRandomData:    state.LastRandomData,
 	}
 }
 
@@ -140,9 +157,24 @@ func (state State) MakeBlock(
 	evidence []types.Evidence,
 	proposerAddress []byte,
 ) (*types.Block, *types.PartSet) {
+	return state.makeBlock(height, txs, commit, evidence, nil, proposerAddress)
+}
+
+// makeBlock is MakeBlock for a block which also commits the given random beacon
+// key change, which may be nil.
+func (state State) makeBlock(
+	height int64,
+	txs []types.Tx,
+	commit *types.Commit,
+	evidence []types.Evidence,
+	keyChange *types.RandomBeaconKeyChange,
+	proposerAddress []byte,
+) (*types.Block, *types.PartSet) {
 
 	// Build base block with block data.
 	block := types.MakeBlock(height, txs, commit, evidence)
+	block.RandomBeaconKeyChange = keyChange
+	block.RandomBeaconKeyChangeHash = keyChange.Hash()
 
 	// Set time.
 	var timestamp time.Time
@@ -160,10 +192,21 @@ func (state State) MakeBlock(
 		state.ConsensusParams.Hash(), state.AppHash, state.LastResultsHash,
 		proposerAddress,
 	)
+	block.RandomBeaconKeyHash = state.randomBeaconKey(keyChange).Hash()
 
 	return block, block.MakePartSet(types.BlockPartSizeBytes)
 }
 
+// randomBeaconKey returns the key the random data of the next block is signed
+// with: the key of the random beacon epoch of the state or, if the chain has
+// no key yet, the key the block commits, if any.
+func (state State) randomBeaconKey(keyChange *types.RandomBeaconKeyChange) types.RandomBeaconKey {
+	if key := state.RandomBeaconEpoch.Key(); !key.IsEmpty() || keyChange == nil {
+		return key
+	}
+	return keyChange.Key
+}
+
 // MedianTime computes a median time for a given Commit (based on Timestamp field of votes messages) and the
 // corresponding validator set. The computed time is always between timestamps of
 // the votes sent by honest processes, i.e., a faulty processes can not arbitrarily increase or decrease the
@@ -219,6 +262,14 @@ func MakeGenesisState(genDoc *types.GenesisDoc) (State, error) {
 		return State{}, fmt.Errorf("Error in genesis file: %v", err)
 	}
 
+	var randomBeaconEpoch RandomBeaconEpoch
+	if genDoc.BLSMasterPubKey != "" {
+		randomBeaconEpoch, err = genesisRandomBeaconEpoch(genDoc)
+		if err != nil {
+			return State{}, fmt.Errorf("Error in genesis file: %v", err)
+		}
+	}
+
 	var validatorSet, nextValidatorSet *types.ValidatorSet
 	if genDoc.Validators == nil {
 		validatorSet = types.NewValidatorSet(nil)
@@ -249,5 +300,8 @@ func MakeGenesisState(genDoc *types.GenesisDoc) (State, error) {
 		LastHeightConsensusParamsChanged: 1,
 
 		AppHash: genDoc.AppHash,
+
+		RandomBeaconEpoch: randomBeaconEpoch,
+		LastRandomData:    []byte(types.InitialRandomData),
 	}, nil
 }
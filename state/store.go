@@ -1,7 +1,11 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+
+	dkgtypes "github.com/corestario/dkglib/lib/types"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -31,6 +35,10 @@ func calcABCIResponsesKey(height int64) []byte {
 	return []byte(fmt.Sprintf("abciResponsesKey:%v", height))
 }
 
+func calcRandomBeaconEpochKey(epoch int64) []byte {
+	return []byte(fmt.Sprintf("randomBeaconEpochKey:%v", epoch))
+}
+
 // LoadStateFromDBOrGenesisFile loads the most recent state from the database,
 // or creates a new one from the given genesisFilePath and persists the result
 // to the database.
@@ -38,11 +46,11 @@ func LoadStateFromDBOrGenesisFile(stateDB dbm.DB, genesisFilePath string) (State
 	state := LoadState(stateDB)
 	if state.IsEmpty() {
 		var err error
-		state, err = MakeGenesisStateFromFile(genesisFilePath)
+		genDoc, err := MakeGenesisDocFromFile(genesisFilePath)
 		if err != nil {
 			return state, err
 		}
-		SaveState(stateDB, state)
+		return LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
 	}
 
 	return state, nil
@@ -106,6 +114,12 @@ func saveState(db dbm.DB, state State, key []byte) {
 	saveValidatorsInfo(db, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
 	// Save next consensus params.
 	saveConsensusParamsInfo(db, nextHeight, state.LastHeightConsensusParamsChanged, state.ConsensusParams)
+	// Save the random beacon epoch of the next block, if it is a new one.
+	if epoch := state.RandomBeaconEpoch; epoch.Epoch > LastRandomBeaconEpoch(db) {
+		if err := SaveRandomBeaconEpoch(db, &epoch); err != nil {
+			panic(fmt.Sprintf("Failed to save random beacon epoch: %v", err))
+		}
+	}
 	db.SetSync(key, state.Bytes())
 }
 
@@ -331,3 +345,256 @@ func saveConsensusParamsInfo(db dbm.DB, nextHeight, changeHeight int64, params t
 	}
 	db.Set(calcConsensusParamsKey(nextHeight), paramsInfo.Bytes())
 }
+
+//-----------------------------------------------------------------------------
+
+// RandomBeaconEpoch describes a key epoch of the random beacon: the BLS master
+// public key the random data is signed with from StartHeight on, until the
+// next epoch starts. The key of the first epoch comes from the genesis file,
+// the keys of later epochs are produced by DKG rounds and committed in blocks,
+// see types.RandomBeaconKeyChange. Participants holds the addresses of the
+// validators in the order of their key shares.
+type RandomBeaconEpoch struct {
+	Epoch        int64           `json:"epoch"`
+	StartHeight  int64           `json:"start_height"`
+	MasterPubKey string          `json:"master_pub_key"`
+	Threshold    int             `json:"threshold"`
+	NumShares    int             `json:"num_shares"`
+	Participants []types.Address `json:"participants"`
+}
+
+// Bytes serializes the RandomBeaconEpoch using go-amino.
+func (epoch *RandomBeaconEpoch) Bytes() []byte {
+	return cdc.MustMarshalBinaryBare(epoch)
+}
+
+// Key returns the key of the epoch.
+func (epoch *RandomBeaconEpoch) Key() types.RandomBeaconKey {
+	return types.RandomBeaconKey{
+		MasterPubKey: epoch.MasterPubKey,
+		Threshold:    epoch.Threshold,
+		NumShares:    epoch.NumShares,
+	}
+}
+
+// Verifier returns a verifier for the random data signed with the master
+// public key of the epoch, built from its key like the verifiers of the
+// blocks. It can not be used for signing.
+func (epoch *RandomBeaconEpoch) Verifier() (dkgtypes.Verifier, error) {
+	verifier, err := epoch.Key().Verifier(nil)
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// genesisRandomBeaconEpoch returns the first epoch of the chain, signed with
+// the key of the genesis file. It assumes that the genesis validator with
+// index i holds the key share with index i (as `tendermint testnet` assigns
+// them), which is how the conflicting random shares of the evidence are
+// attributed to the validators. Returns an error if there are more genesis
+// validators than key shares.
+func genesisRandomBeaconEpoch(genDoc *types.GenesisDoc) (RandomBeaconEpoch, error) {
+	if len(genDoc.Validators) > genDoc.BLSNumShares {
+		return RandomBeaconEpoch{}, fmt.Errorf("%d genesis validators, but only %d BLS key shares",
+			len(genDoc.Validators), genDoc.BLSNumShares)
+	}
+	participants := make([]types.Address, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		participants[i] = val.PubKey.Address()
+	}
+	return RandomBeaconEpoch{
+		Epoch:        1,
+		StartHeight:  1,
+		MasterPubKey: genDoc.BLSMasterPubKey,
+		Threshold:    genDoc.BLSThreshold,
+		NumShares:    genDoc.BLSNumShares,
+		Participants: participants,
+	}, nil
+}
+
+// nextRandomBeaconEpoch returns the random beacon epoch of the block after the
+// given one, which belongs to the given epoch.
+func nextRandomBeaconEpoch(epoch RandomBeaconEpoch, block *types.Block) RandomBeaconEpoch {
+	change := block.RandomBeaconKeyChange
+	if change == nil {
+		return epoch
+	}
+	startHeight := block.Height + 1
+	if epoch.Key().IsEmpty() {
+		// the first key of the chain is used by the block committing it
+		startHeight = block.Height
+	}
+	return RandomBeaconEpoch{
+		Epoch:        epoch.Epoch + 1,
+		StartHeight:  startHeight,
+		MasterPubKey: change.Key.MasterPubKey,
+		Threshold:    change.Key.Threshold,
+		NumShares:    change.Key.NumShares,
+		Participants: change.Participants,
+	}
+}
+
+// LastRandomBeaconEpoch returns the number of the last saved epoch or 0 if
+// there is none.
+func LastRandomBeaconEpoch(db dbm.DB) int64 {
+	buf := db.Get(lastRandomBeaconEpochKey)
+	if len(buf) == 0 {
+		return 0
+	}
+	var epoch int64
+	cdc.MustUnmarshalBinaryBare(buf, &epoch)
+	return epoch
+}
+
+// SaveRandomBeaconEpoch persists the epoch. Epochs must be saved in order and
+// may not start before the previous epoch.
+func SaveRandomBeaconEpoch(db dbm.DB, epoch *RandomBeaconEpoch) error {
+	last := LastRandomBeaconEpoch(db)
+	if epoch.Epoch != last+1 {
+		return fmt.Errorf("expected random beacon epoch %d, got %d", last+1, epoch.Epoch)
+	}
+	if last > 0 {
+		prev := LoadRandomBeaconEpochByNumber(db, last)
+		if prev == nil {
+			return ErrNoRandomBeaconEpoch{last}
+		}
+		if epoch.StartHeight < prev.StartHeight {
+			return fmt.Errorf("random beacon epoch %d starts at height %d before the previous epoch (%d)",
+				epoch.Epoch, epoch.StartHeight, prev.StartHeight)
+		}
+	}
+
+	batch := db.NewBatch()
+	defer batch.Close()
+	batch.Set(calcRandomBeaconEpochKey(epoch.Epoch), epoch.Bytes())
+	batch.Set(lastRandomBeaconEpochKey, cdc.MustMarshalBinaryBare(epoch.Epoch))
+	batch.WriteSync()
+	return nil
+}
+
+// LoadRandomBeaconEpochByNumber loads the epoch with the given number. It
+// returns nil if the epoch is not found.
+func LoadRandomBeaconEpochByNumber(db dbm.DB, number int64) *RandomBeaconEpoch {
+	buf := db.Get(calcRandomBeaconEpochKey(number))
+	if len(buf) == 0 {
+		return nil
+	}
+
+	epoch := new(RandomBeaconEpoch)
+	err := cdc.UnmarshalBinaryBare(buf, epoch)
+	if err != nil {
+		// DATA HAS BEEN CORRUPTED OR THE SPEC HAS CHANGED
+		cmn.Exit(fmt.Sprintf(`LoadRandomBeaconEpoch: Data has been corrupted or its spec has changed:
+                %v\n`, err))
+	}
+	return epoch
+}
+
+// LoadRandomBeaconEpoch loads the epoch the given height belongs to.
+// Returns ErrNoRandomBeaconEpochForHeight if there is none, and
+// ErrNoRandomBeaconEpoch if an epoch up to the last saved one is missing.
+func LoadRandomBeaconEpoch(db dbm.DB, height int64) (*RandomBeaconEpoch, error) {
+	// Epochs are saved in order of their start heights, so look for the last
+	// epoch that starts at or before the height.
+	last := LastRandomBeaconEpoch(db)
+	var missing int64
+	n := sort.Search(int(last), func(i int) bool {
+		epoch := LoadRandomBeaconEpochByNumber(db, int64(i+1))
+		if epoch == nil {
+			missing = int64(i + 1)
+			return true
+		}
+		return epoch.StartHeight > height
+	})
+	if missing > 0 {
+		return nil, ErrNoRandomBeaconEpoch{missing}
+	}
+	if n == 0 {
+		return nil, ErrNoRandomBeaconEpochForHeight{height}
+	}
+	epoch := LoadRandomBeaconEpochByNumber(db, int64(n))
+	if epoch == nil {
+		return nil, ErrNoRandomBeaconEpoch{int64(n)}
+	}
+	return epoch, nil
+}
+
+// VerifyRandomData checks that randomData is the random data of the block at
+// the given height, i.e. the signature of the random message built from the
+// random data of the previous block and the seed with the master public key of
+// the epoch the height belongs to.
+// Returns ErrNoRandomBeaconEpochForHeight if there is no such epoch.
+func VerifyRandomData(db dbm.DB, height int64, prevRandomData, seed, randomData []byte) error {
+	epoch, err := LoadRandomBeaconEpoch(db, height)
+	if err != nil {
+		return err
+	}
+	verifier, err := epoch.Verifier()
+	if err != nil {
+		return fmt.Errorf("invalid master public key of random beacon epoch %d: %v", epoch.Epoch, err)
+	}
+	return verifier.VerifyRandomData(types.MakeRandomMessage(prevRandomData, seed), randomData)
+}
+
+// VerifyRandomDataSigners checks that randomData is the random data of the
+// block at the given height, see VerifyRandomData, and that it is recovered
+// from the random shares of the precommits of commit marked by
+// commit.AggregateSigners, so the random beacon can be re-verified from the
+// seen commits of the block store.
+// Returns ErrNoRandomBeaconEpochForHeight if there is no epoch for the height.
+func VerifyRandomDataSigners(db dbm.DB, height int64, prevRandomData, seed, randomData []byte,
+	commit *types.Commit) error {
+	epoch, err := LoadRandomBeaconEpoch(db, height)
+	if err != nil {
+		return err
+	}
+	verifier, err := epoch.Verifier()
+	if err != nil {
+		return fmt.Errorf("invalid master public key of random beacon epoch %d: %v", epoch.Epoch, err)
+	}
+
+	msg := types.MakeRandomMessage(prevRandomData, seed)
+	if err := verifier.VerifyRandomData(msg, randomData); err != nil {
+		return err
+	}
+	recovered, err := verifier.Recover(msg, commit.AggregateShares())
+	if err != nil {
+		return fmt.Errorf("failed to recover random data from the signers: %v", err)
+	}
+	if !bytes.Equal(recovered, randomData) {
+		return fmt.Errorf("random data %X is not the one of the signers %X", randomData, recovered)
+	}
+	return nil
+}
+
+// VerifyConflictingRandomShares checks the random shares of the evidence
+// against the epoch of its height: the shares must be valid and signed with
+// the key share of the validator the evidence is about. Participant i of the
+// epoch holds key share i. The epochs are derived from the committed blocks,
+// so the height must not be beyond the next one of the state, see
+// VerifyEvidence.
+// Returns ErrNoRandomBeaconEpochForHeight if there is no epoch for the height.
+func VerifyConflictingRandomShares(db dbm.DB, ev *types.ConflictingRandomSharesEvidence) error {
+	epoch, err := LoadRandomBeaconEpoch(db, ev.Height())
+	if err != nil {
+		return err
+	}
+	index, err := ev.ShareIndex()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(epoch.Participants) {
+		return fmt.Errorf("share index %d is out of range of random beacon epoch %d (%d participants)",
+			index, epoch.Epoch, len(epoch.Participants))
+	}
+	if !bytes.Equal(epoch.Participants[index], ev.Address()) {
+		return fmt.Errorf("share %d of random beacon epoch %d belongs to %X, not %X",
+			index, epoch.Epoch, epoch.Participants[index], ev.Address())
+	}
+	verifier, err := epoch.Verifier()
+	if err != nil {
+		return fmt.Errorf("invalid master public key of random beacon epoch %d: %v", epoch.Epoch, err)
+	}
+	return ev.VerifyShares(verifier)
+}
@@ -5,10 +5,12 @@ import (
 	"os"
 	"testing"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
@@ -83,3 +85,237 @@ func BenchmarkLoadValidators(b *testing.B) {
 		})
 	}
 }
+
+func TestStoreRandomBeaconEpochs(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	keyrings := make([]*blsShare.BLSKeyring, 2)
+	masterPubKeys := make([]string, len(keyrings))
+	for i := range keyrings {
+		keyring, err := blsShare.NewBLSKeyring(2, 3)
+		require.NoError(t, err)
+		keyrings[i] = keyring
+		masterPubKeys[i], err = blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+		require.NoError(t, err)
+	}
+
+	// The first epoch comes from the genesis file.
+	val, _ := types.RandValidator(true, 10)
+	genDoc := &types.GenesisDoc{
+		ChainID:         "random-beacon-epochs",
+		Validators:      []types.GenesisValidator{{PubKey: val.PubKey, Power: val.VotingPower}},
+		BLSMasterPubKey: masterPubKeys[0],
+		BLSThreshold:    2,
+		BLSNumShares:    3,
+	}
+	_, err := sm.LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sm.LastRandomBeaconEpoch(stateDB))
+	epoch := sm.LoadRandomBeaconEpochByNumber(stateDB, 1)
+	require.NotNil(t, epoch)
+	assert.EqualValues(t, 1, epoch.StartHeight)
+	assert.Equal(t, masterPubKeys[0], epoch.MasterPubKey)
+	assert.Equal(t, []types.Address{val.Address}, epoch.Participants)
+
+	// Epochs must be saved in order.
+	assert.Error(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{Epoch: 3, StartHeight: 10}))
+	assert.Error(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{Epoch: 2, StartHeight: 0}))
+	require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{
+		Epoch:        2,
+		StartHeight:  10,
+		MasterPubKey: masterPubKeys[1],
+		Threshold:    2,
+		NumShares:    3,
+	}))
+	assert.EqualValues(t, 2, sm.LastRandomBeaconEpoch(stateDB))
+	assert.Nil(t, sm.LoadRandomBeaconEpochByNumber(stateDB, 3))
+
+	testCases := []struct {
+		height int64
+		epoch  int64
+	}{
+		{1, 1},
+		{9, 1},
+		{10, 2},
+		{100, 2},
+	}
+	for _, tc := range testCases {
+		epoch, err := sm.LoadRandomBeaconEpoch(stateDB, tc.height)
+		require.NoError(t, err)
+		assert.Equal(t, tc.epoch, epoch.Epoch, "height %d", tc.height)
+	}
+	_, err = sm.LoadRandomBeaconEpoch(stateDB, 0)
+	assert.Equal(t, sm.ErrNoRandomBeaconEpochForHeight{Height: 0}, err)
+
+	// Random data is verified with the key of the epoch of its height.
+	prevRandomData, seed := []byte("previous random data"), []byte("seed")
+	randomData := recoverRandomData(t, keyrings[1], types.MakeRandomMessage(prevRandomData, seed))
+	assert.NoError(t, sm.VerifyRandomData(stateDB, 10, prevRandomData, seed, randomData))
+	assert.Error(t, sm.VerifyRandomData(stateDB, 9, prevRandomData, seed, randomData))
+	assert.Error(t, sm.VerifyRandomData(stateDB, 10, prevRandomData, []byte("other seed"), randomData))
+	_, ok := sm.VerifyRandomData(stateDB, 0, prevRandomData, seed, randomData).(sm.ErrNoRandomBeaconEpochForHeight)
+	assert.True(t, ok)
+}
+
+func TestStoreMissingRandomBeaconEpochs(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{Epoch: i, StartHeight: 10 * i}))
+	}
+
+	// A gap between the epochs.
+	stateDB.Delete(sm.CalcRandomBeaconEpochKey(2))
+	for _, height := range []int64{10, 20, 30} {
+		_, err := sm.LoadRandomBeaconEpoch(stateDB, height)
+		assert.Equal(t, sm.ErrNoRandomBeaconEpoch{Epoch: 2}, err, "height %d", height)
+	}
+
+	// The last epoch is missing.
+	stateDB.Delete(sm.CalcRandomBeaconEpochKey(3))
+	_, err := sm.LoadRandomBeaconEpoch(stateDB, 30)
+	assert.Equal(t, sm.ErrNoRandomBeaconEpoch{Epoch: 2}, err)
+	assert.Equal(t, sm.ErrNoRandomBeaconEpoch{Epoch: 3},
+		sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{Epoch: 4, StartHeight: 40}))
+	ev := &types.ConflictingRandomSharesEvidence{Height_: 30}
+	assert.Equal(t, sm.ErrNoRandomBeaconEpoch{Epoch: 2}, sm.VerifyConflictingRandomShares(stateDB, ev))
+}
+
+// The genesis validator with index i holds the key share with index i, so
+// there can not be more genesis validators than key shares.
+func TestMakeGenesisStateRandomBeaconEpoch(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	require.NoError(t, err)
+	val1, _ := types.RandValidator(true, 10)
+	val2, _ := types.RandValidator(true, 10)
+	genDoc := &types.GenesisDoc{
+		ChainID: "random-beacon-epochs",
+		Validators: []types.GenesisValidator{
+			{PubKey: val1.PubKey, Power: val1.VotingPower},
+			{PubKey: val2.PubKey, Power: val2.VotingPower},
+		},
+		BLSMasterPubKey: masterPubKey,
+		BLSThreshold:    1,
+		BLSNumShares:    1,
+	}
+	_, err = sm.MakeGenesisState(genDoc)
+	assert.Error(t, err)
+
+	genDoc.BLSNumShares = 2
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{val1.Address, val2.Address}, state.RandomBeaconEpoch.Participants)
+}
+
+func TestStoreVerifyRandomDataSigners(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	require.NoError(t, err)
+	require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{
+		Epoch:        1,
+		StartHeight:  1,
+		MasterPubKey: masterPubKey,
+		Threshold:    2,
+		NumShares:    3,
+	}))
+
+	prevRandomData, seed := []byte("previous random data"), []byte("seed")
+	msg := types.MakeRandomMessage(prevRandomData, seed)
+	blockID := types.BlockID{Hash: []byte("block hash")}
+	precommits := make([]*types.CommitSig, keyring.N)
+	for i := range precommits {
+		verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[i], keyring.T, keyring.N)
+		share, err := verifier.Sign(msg)
+		require.NoError(t, err)
+		precommits[i] = &types.CommitSig{
+			Type:           types.PrecommitType,
+			Height:         5,
+			BlockID:        blockID,
+			ValidatorIndex: i,
+			BLSSignature:   share,
+		}
+	}
+
+	commit := types.NewCommit(blockID, precommits)
+	randomData := recoverRandomData(t, keyring, msg)
+	commit.AggregateSigners = cmn.NewBitArray(keyring.N)
+	commit.AggregateSigners.SetIndex(0, true)
+	commit.AggregateSigners.SetIndex(2, true)
+	require.NoError(t, commit.ValidateBasic())
+	assert.NoError(t, sm.VerifyRandomDataSigners(stateDB, 5, prevRandomData, seed, randomData, commit))
+	assert.Error(t, sm.VerifyRandomDataSigners(stateDB, 5, prevRandomData, []byte("other seed"), randomData, commit))
+
+	// The signers must hold enough shares.
+	commit.AggregateSigners.SetIndex(0, false)
+	assert.Error(t, sm.VerifyRandomDataSigners(stateDB, 5, prevRandomData, seed, randomData, commit))
+}
+
+func TestStoreVerifyConflictingRandomShares(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	require.NoError(t, err)
+	vals := make([]*types.Validator, keyring.N)
+	participants := make([]types.Address, keyring.N)
+	for i := range vals {
+		vals[i], _ = types.RandValidator(false, 10)
+		participants[i] = vals[i].Address
+	}
+	require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{
+		Epoch:        1,
+		StartHeight:  5,
+		MasterPubKey: masterPubKey,
+		Threshold:    2,
+		NumShares:    3,
+		Participants: participants,
+	}))
+
+	prevRandomData := cmn.RandBytes(types.RandomDataSize)
+	seedA, seedB := []byte("seed a"), []byte("seed b")
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[1], keyring.T, keyring.N)
+	shareA, err := verifier.Sign(types.MakeRandomMessage(prevRandomData, seedA))
+	require.NoError(t, err)
+	shareB, err := verifier.Sign(types.MakeRandomMessage(prevRandomData, seedB))
+	require.NoError(t, err)
+
+	ev := types.NewConflictingRandomSharesEvidence(vals[1].PubKey, 5, prevRandomData, seedA, shareA, seedB, shareB)
+	assert.NoError(t, sm.VerifyConflictingRandomShares(stateDB, ev))
+
+	// The key share belongs to another validator.
+	ev.PubKey = vals[2].PubKey
+	assert.Error(t, sm.VerifyConflictingRandomShares(stateDB, ev))
+
+	// The shares must be valid for the key set of the height.
+	ev = types.NewConflictingRandomSharesEvidence(vals[1].PubKey, 5, prevRandomData, seedA, shareB, seedB, shareA)
+	assert.Error(t, sm.VerifyConflictingRandomShares(stateDB, ev))
+	ev = types.NewConflictingRandomSharesEvidence(vals[1].PubKey, 4, prevRandomData, seedA, shareA, seedB, shareB)
+	_, ok := sm.VerifyConflictingRandomShares(stateDB, ev).(sm.ErrNoRandomBeaconEpochForHeight)
+	assert.True(t, ok)
+}
+
+// recoverRandomData returns the threshold signature of msg by the keyring.
+func recoverRandomData(t *testing.T, keyring *blsShare.BLSKeyring, msg []byte) []byte {
+	verifiers := make([]*blsShare.BLSVerifier, keyring.N)
+	for i := range verifiers {
+		verifiers[i] = blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[i], keyring.T, keyring.N)
+	}
+	shares := make([]blsShare.BLSSigner, keyring.T)
+	for i := range shares {
+		sig, err := verifiers[i].Sign(msg)
+		require.NoError(t, err)
+		shares[i] = &testBLSSigner{sig}
+	}
+	randomData, err := verifiers[0].Recover(msg, shares)
+	require.NoError(t, err)
+	return randomData
+}
+
+type testBLSSigner struct {
+	blsSignature []byte
+}
+
+func (s *testBLSSigner) GetBLSSignature() []byte { return s.blsSignature }
+func (s *testBLSSigner) GetHash() []byte         { return []byte("hash") }
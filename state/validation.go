@@ -87,6 +87,30 @@ func validateBlock(evidencePool EvidencePool, stateDB dbm.DB, state State, block
 		)
 	}
 
+	// Validate the random beacon key of the block, and its random data once it
+	// is committed.
+	if change := block.RandomBeaconKeyChange; change != nil &&
+		bytes.Equal(change.Key.Hash(), state.RandomBeaconEpoch.Key().Hash()) {
+		return errors.New("Block.RandomBeaconKeyChange does not change the key")
+	}
+	key := state.randomBeaconKey(block.RandomBeaconKeyChange)
+	if !bytes.Equal(block.RandomBeaconKeyHash, key.Hash()) {
+		return fmt.Errorf("Wrong Block.Header.RandomBeaconKeyHash.  Expected %X, got %v",
+			key.Hash(),
+			block.RandomBeaconKeyHash,
+		)
+	}
+	if len(block.RandomData) > 0 && !key.IsEmpty() {
+		verifier, err := key.Verifier(nil)
+		if err != nil {
+			return fmt.Errorf("Invalid random beacon key: %v", err)
+		}
+		msg := types.MakeRandomMessage(state.LastRandomData, state.Seed)
+		if err := verifier.VerifyRandomData(msg, block.RandomData); err != nil {
+			return fmt.Errorf("Wrong Block.Header.RandomData: %v", err)
+		}
+	}
+
 	// Validate block LastCommit.
 	if block.Height == 1 {
 		if len(block.LastCommit.Precommits) != 0 {
@@ -165,6 +189,7 @@ func validateBlock(evidencePool EvidencePool, stateDB dbm.DB, state State, block
 // - it is from a key who was a validator at the given height
 // - it is internally consistent
 // - it was properly signed by the alleged equivocator
+// - random shares were signed with the key share of the alleged equivocator
 func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error {
 	height := state.LastBlockHeight
 
@@ -198,5 +223,18 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 		return err
 	}
 
+	// Random shares can only be verified with the key set of their height. The
+	// epochs are derived from the blocks, so the key set of a height is only
+	// known to every node once the block before it is committed.
+	if ev, ok := evidence.(*types.ConflictingRandomSharesEvidence); ok {
+		if ev.Height() > state.LastBlockHeight+1 {
+			return fmt.Errorf("The random beacon epoch of height %d is not committed yet. Max height is %d",
+				ev.Height(), state.LastBlockHeight+1)
+		}
+		if err := VerifyConflictingRandomShares(stateDB, ev); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/mock"
 
@@ -257,3 +258,41 @@ func TestValidateFailBlockOnCommittedEvidence(t *testing.T) {
 	require.Error(t, err)
 	require.IsType(t, err, &types.ErrEvidenceInvalid{})
 }
+
+func TestVerifyEvidenceConflictingRandomShares(t *testing.T) {
+	state, stateDB, _ := makeState(3, 1)
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	require.NoError(t, err)
+	participants := make([]types.Address, keyring.N)
+	for i, val := range state.Validators.Validators {
+		participants[i] = val.Address
+	}
+	require.NoError(t, sm.SaveRandomBeaconEpoch(stateDB, &sm.RandomBeaconEpoch{
+		Epoch:        1,
+		StartHeight:  1,
+		MasterPubKey: masterPubKey,
+		Threshold:    keyring.T,
+		NumShares:    keyring.N,
+		Participants: participants,
+	}))
+
+	prevRandomData := []byte(types.InitialRandomData)
+	seedA, seedB := []byte("seed a"), []byte("seed b")
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[1], keyring.T, keyring.N)
+	shareA, err := verifier.Sign(types.MakeRandomMessage(prevRandomData, seedA))
+	require.NoError(t, err)
+	shareB, err := verifier.Sign(types.MakeRandomMessage(prevRandomData, seedB))
+	require.NoError(t, err)
+	pubKey := state.Validators.Validators[1].PubKey
+
+	ev := types.NewConflictingRandomSharesEvidence(pubKey, 1, prevRandomData, seedA, shareA, seedB, shareB)
+	require.NoError(t, sm.VerifyEvidence(stateDB, state, ev))
+
+	// The epoch of the height after the next one depends on the next block.
+	ev = types.NewConflictingRandomSharesEvidence(pubKey, 2, prevRandomData, seedA, shareA, seedB, shareB)
+	err = sm.VerifyEvidence(stateDB, state, ev)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not committed yet")
+}
@@ -8,6 +8,7 @@ import (
 
 	dbm "github.com/tendermint/tm-db"
 
+	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -27,7 +28,8 @@ the Commit data outside the Block. (TODO)
 // deserializing loaded data, indicating probable corruption on disk.
 */
 type BlockStore struct {
-	db dbm.DB
+	db     dbm.DB
+	logger log.Logger
 
 	mtx    sync.RWMutex
 	height int64
@@ -40,9 +42,15 @@ func NewBlockStore(db dbm.DB) *BlockStore {
 	return &BlockStore{
 		height: bsjson.Height,
 		db:     db,
+		logger: log.NewNopLogger(),
 	}
 }
 
+// SetLogger sets the logger of the BlockStore.
+func (bs *BlockStore) SetLogger(l log.Logger) {
+	bs.logger = l
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -169,6 +177,9 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 
 	// Save block meta
 	blockMeta := types.NewBlockMeta(block, blockParts)
+	if seenCommit != nil {
+		blockMeta.AggregateSigners = seenCommit.AggregateSigners
+	}
 	metaBytes := cdc.MustMarshalBinaryBare(blockMeta)
 	bs.db.Set(calcBlockMetaKey(height), metaBytes)
 
@@ -179,7 +190,12 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	}
 
 	// Save block commit (duplicate and separate from the Block)
-	blockCommitBytes := cdc.MustMarshalBinaryBare(block.LastCommit)
+	blockCommit, err := bs.blockCommitWithSigners(block.LastCommit, height-1)
+	if err != nil {
+		bs.logger.Error("Saving the block commit without the signers of the random data",
+			"height", height-1, "err", err)
+	}
+	blockCommitBytes := cdc.MustMarshalBinaryBare(blockCommit)
 	bs.db.Set(calcBlockCommitKey(height-1), blockCommitBytes)
 
 	// Save seen commit (seen +2/3 precommits for block)
@@ -199,6 +215,28 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.SetSync(nil, nil)
 }
 
+// blockCommitWithSigners returns a copy of the commit for the block at the
+// given height marked with the signers of its random data, as saved in its
+// block meta. The BLS signatures are deterministic, so the precommits of the
+// commit carry the same random shares as the ones of the seen commit. The
+// commit is returned as is, along with an error, if the signers are not valid
+// for it, e.g. if it does not have all of the marked precommits.
+func (bs *BlockStore) blockCommitWithSigners(commit *types.Commit, height int64) (*types.Commit, error) {
+	if commit == nil || commit.AggregateSigners != nil {
+		return commit, nil
+	}
+	blockMeta := bs.LoadBlockMeta(height)
+	if blockMeta == nil || blockMeta.AggregateSigners == nil {
+		return commit, nil
+	}
+	signedCommit := *commit
+	signedCommit.AggregateSigners = blockMeta.AggregateSigners
+	if err := signedCommit.ValidateBasic(); err != nil {
+		return commit, errors.Wrap(err, "invalid signers of the random data for the commit")
+	}
+	return &signedCommit, nil
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		panic(fmt.Sprintf("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -16,6 +16,7 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	sm "github.com/tendermint/tendermint/state"
 
@@ -382,6 +383,49 @@ func TestLoadBlockMeta(t *testing.T) {
 		"expecting successful retrieval of previously saved blockMeta")
 }
 
+func TestBlockStoreSaveAggregateSigners(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	defer cleanup()
+
+	block := makeBlock(bs.Height()+1, state, new(types.Commit))
+	partSet := block.MakePartSet(2)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: partSet.Header()}
+	precommit := func(idx int) *types.CommitSig {
+		return &types.CommitSig{Type: types.PrecommitType, Height: block.Height, BlockID: blockID,
+			ValidatorIndex: idx, BLSSignature: []byte{byte(idx)}}
+	}
+	seenCommit := types.NewCommit(blockID, []*types.CommitSig{precommit(0), precommit(1), precommit(2)})
+	seenCommit.AggregateSigners = cmn.NewBitArray(3)
+	seenCommit.AggregateSigners.SetIndex(0, true)
+	seenCommit.AggregateSigners.SetIndex(1, true)
+	bs.SaveBlock(block, partSet, seenCommit)
+
+	loadedCommit := bs.LoadSeenCommit(block.Height)
+	require.NotNil(t, loadedCommit)
+	assert.Equal(t, seenCommit.AggregateSigners, loadedCommit.AggregateSigners)
+	loadedMeta := bs.LoadBlockMeta(block.Height)
+	require.NotNil(t, loadedMeta)
+	assert.Equal(t, seenCommit.AggregateSigners, loadedMeta.AggregateSigners)
+
+	// the canonical commit, saved with the next block, is marked with the
+	// signers if it has their precommits
+	lastCommit := types.NewCommit(blockID, []*types.CommitSig{precommit(0), precommit(1), nil})
+	nextBlock := newBlock(types.Header{Height: block.Height + 1, ChainID: state.ChainID, Time: tmtime.Now()},
+		lastCommit)
+	bs.SaveBlock(nextBlock, nextBlock.MakePartSet(2), makeTestCommit(nextBlock.Height, tmtime.Now()))
+	loadedCommit = bs.LoadBlockCommit(block.Height)
+	require.NotNil(t, loadedCommit)
+	assert.Equal(t, seenCommit.AggregateSigners, loadedCommit.AggregateSigners)
+	assert.Nil(t, lastCommit.AggregateSigners, "the commit of the block is modified")
+	assert.Equal(t, nextBlock.Hash(), bs.LoadBlock(nextBlock.Height).Hash())
+
+	// and is not marked if it does not
+	lastCommit = types.NewCommit(blockID, []*types.CommitSig{precommit(0), nil, precommit(2)})
+	signedCommit, err := bs.blockCommitWithSigners(lastCommit, block.Height)
+	assert.Error(t, err)
+	assert.Nil(t, signedCommit.AggregateSigners)
+}
+
 func TestBlockFetchAtHeight(t *testing.T) {
 	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
 	defer cleanup()
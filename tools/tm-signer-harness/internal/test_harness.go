@@ -7,6 +7,9 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/corestario/dkglib/lib/alias"
+	"github.com/corestario/dkglib/lib/blsShare"
+
 	"github.com/tendermint/tendermint/crypto/tmhash"
 
 	"github.com/tendermint/tendermint/crypto/ed25519"
@@ -20,17 +23,19 @@ import (
 
 // Test harness error codes (which act as exit codes when the test harness fails).
 const (
-	NoError                    int = iota // 0
-	ErrInvalidParameters                  // 1
-	ErrMaxAcceptRetriesReached            // 2
-	ErrFailedToLoadGenesisFile            // 3
-	ErrFailedToCreateListener             // 4
-	ErrFailedToStartListener              // 5
-	ErrInterrupted                        // 6
-	ErrOther                              // 7
-	ErrTestPublicKeyFailed                // 8
-	ErrTestSignProposalFailed             // 9
-	ErrTestSignVoteFailed                 // 10
+	NoError                      int = iota // 0
+	ErrInvalidParameters                    // 1
+	ErrMaxAcceptRetriesReached              // 2
+	ErrFailedToLoadGenesisFile              // 3
+	ErrFailedToCreateListener               // 4
+	ErrFailedToStartListener                // 5
+	ErrInterrupted                          // 6
+	ErrOther                                // 7
+	ErrTestPublicKeyFailed                  // 8
+	ErrTestSignProposalFailed               // 9
+	ErrTestSignVoteFailed                   // 10
+	ErrTestSignDKGDataFailed                // 11
+	ErrTestSignRandomShareFailed            // 12
 )
 
 var voteTypes = []types.SignedMsgType{types.PrevoteType, types.PrecommitType}
@@ -52,6 +57,7 @@ type TestHarness struct {
 	signerClient     *privval.SignerClient
 	fpv              *privval.FilePV
 	chainID          string
+	blsVerifier      *blsShare.BLSVerifier // nil if the genesis has no BLS key set
 	acceptRetries    int
 	logger           log.Logger
 	exitWhenComplete bool
@@ -101,6 +107,15 @@ func NewTestHarness(logger log.Logger, cfg TestHarnessConfig) (*TestHarness, err
 	}
 	logger.Info("Loaded genesis file", "chainID", st.ChainID)
 
+	var blsVerifier *blsShare.BLSVerifier
+	if st.BLSMasterPubKey != "" {
+		masterPubKey, err := blsShare.LoadPubKey(st.BLSMasterPubKey, st.BLSNumShares)
+		if err != nil {
+			return nil, newTestHarnessError(ErrFailedToLoadGenesisFile, err, genesisFile)
+		}
+		blsVerifier = blsShare.NewBLSVerifier(masterPubKey, nil, st.BLSThreshold, st.BLSNumShares)
+	}
+
 	spv, err := newTestHarnessListener(logger, cfg)
 	if err != nil {
 		return nil, newTestHarnessError(ErrFailedToCreateListener, err, "")
@@ -116,6 +131,7 @@ func NewTestHarness(logger log.Logger, cfg TestHarnessConfig) (*TestHarness, err
 		signerClient:     signerClient,
 		fpv:              fpv,
 		chainID:          st.ChainID,
+		blsVerifier:      blsVerifier,
 		acceptRetries:    cfg.AcceptRetries,
 		logger:           logger,
 		exitWhenComplete: cfg.ExitWhenComplete,
@@ -180,6 +196,14 @@ func (th *TestHarness) Run() {
 		th.Shutdown(err)
 		return
 	}
+	if err := th.TestSignDKGData(); err != nil {
+		th.Shutdown(err)
+		return
+	}
+	if err := th.TestSignRandomShare(); err != nil {
+		th.Shutdown(err)
+		return
+	}
 	th.logger.Info("SUCCESS! All tests passed.")
 	th.Shutdown(nil)
 }
@@ -284,6 +308,55 @@ func (th *TestHarness) TestSignVote() error {
 	return nil
 }
 
+// TestSignDKGData makes sure the remote signer can successfully sign DKG
+// messages.
+func (th *TestHarness) TestSignDKGData() error {
+	th.logger.Info("TEST: Signing of DKG messages")
+	data := &alias.DKGData{
+		Type:    alias.DKGPubKey,
+		Addr:    th.fpv.GetPubKey().Address(),
+		RoundID: 1,
+		Data:    tmhash.Sum([]byte("pub key")),
+	}
+	dataBytes := data.SignBytes(th.chainID)
+	if err := th.signerClient.SignData(th.chainID, data); err != nil {
+		th.logger.Error("FAILED: Signing of DKG message", "err", err)
+		return newTestHarnessError(ErrTestSignDKGDataFailed, err, "")
+	}
+	th.logger.Debug("Signed DKG message", "data", data)
+	if th.signerClient.GetPubKey().VerifyBytes(dataBytes, data.Signature) {
+		th.logger.Info("Successfully validated DKG message signature")
+	} else {
+		th.logger.Error("FAILED: DKG message signature validation failed")
+		return newTestHarnessError(ErrTestSignDKGDataFailed, nil, "signature validation failed")
+	}
+	return nil
+}
+
+// TestSignRandomShare makes sure the remote signer can successfully sign random
+// shares with a BLS key share of the key set of the genesis. It is skipped if
+// the genesis has no BLS key set.
+func (th *TestHarness) TestSignRandomShare() error {
+	if th.blsVerifier == nil {
+		th.logger.Info("SKIPPED: Signing of random shares, no BLS key set in genesis")
+		return nil
+	}
+	th.logger.Info("TEST: Signing of random shares")
+	msg := types.MakeRandomMessage(tmhash.Sum([]byte("random data")), tmhash.Sum([]byte("seed")))
+	share, err := th.signerClient.SignRandomShare(th.chainID, 101, 0, msg)
+	if err != nil {
+		th.logger.Error("FAILED: Signing of random share", "err", err)
+		return newTestHarnessError(ErrTestSignRandomShareFailed, err, "")
+	}
+	th.logger.Debug("Signed random share", "share", cmn.HexBytes(share))
+	if err := th.blsVerifier.VerifyRandomShare("", msg, share); err != nil {
+		th.logger.Error("FAILED: Random share validation failed", "err", err)
+		return newTestHarnessError(ErrTestSignRandomShareFailed, err, "share validation failed")
+	}
+	th.logger.Info("Successfully validated random share")
+	return nil
+}
+
 // Shutdown will kill the test harness and attempt to close all open sockets
 // gracefully. If the supplied error is nil, it is assumed that the exit code
 // should be 0. If err is not nil, it will exit with an exit code related to the
@@ -385,6 +458,10 @@ func (e *TestHarnessError) Error() string {
 		msg = "Proposal signing validation test failed"
 	case ErrTestSignVoteFailed:
 		msg = "Vote signing validation test failed"
+	case ErrTestSignDKGDataFailed:
+		msg = "DKG message signing validation test failed"
+	case ErrTestSignRandomShareFailed:
+		msg = "Random share signing validation test failed"
 	default:
 		msg = "Unknown error"
 	}
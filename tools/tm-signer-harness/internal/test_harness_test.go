@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -72,7 +74,7 @@ const (
 )
 
 func TestRemoteSignerTestHarnessMaxAcceptRetriesReached(t *testing.T) {
-	cfg := makeConfig(t, 1, 2)
+	cfg := makeConfig(t, 1, 2, genesisFileContents)
 	defer cleanup(cfg)
 
 	th, err := NewTestHarness(log.TestingLogger(), cfg)
@@ -121,6 +123,46 @@ func TestRemoteSignerVoteSigningFailed(t *testing.T) {
 	)
 }
 
+func TestRemoteSignerRandomShareSigning(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	harnessTestWithGenesis(
+		t,
+		blsGenesisFileContents(t, keyring),
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockBLSSignerServer(t, th, keyring.Shares[0])
+		},
+		NoError,
+	)
+}
+
+func TestRemoteSignerRandomShareSigningFailed(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	otherKeyring, err := blsShare.NewBLSKeyring(1, 1)
+	require.NoError(t, err)
+	genesis := blsGenesisFileContents(t, keyring)
+
+	// no BLS key share
+	harnessTestWithGenesis(
+		t,
+		genesis,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		ErrTestSignRandomShareFailed,
+	)
+	// a BLS key share of another key set
+	harnessTestWithGenesis(
+		t,
+		genesis,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockBLSSignerServer(t, th, otherKeyring.Shares[0])
+		},
+		ErrTestSignRandomShareFailed,
+	)
+}
+
 func newMockSignerServer(
 	t *testing.T,
 	th *TestHarness,
@@ -142,9 +184,46 @@ func newMockSignerServer(
 	return privval.NewSignerServer(dialerEndpoint, th.chainID, mockPV)
 }
 
+// mockBLSPV is a MockPV that also signs random shares.
+type mockBLSPV struct {
+	*types.MockPV
+	share *blsShare.BLSShare
+}
+
+func (pv *mockBLSPV) SignRandomShare(chainID string, height int64, round int, msg []byte) ([]byte, error) {
+	return blsShare.NewBLSVerifier(nil, pv.share, 0, 0).Sign(msg)
+}
+
+func newMockBLSSignerServer(t *testing.T, th *TestHarness, share *blsShare.BLSShare) *privval.SignerServer {
+	mockPV := &mockBLSPV{
+		MockPV: types.NewMockPVWithParams(th.fpv.Key.PrivKey, false, false),
+		share:  share,
+	}
+
+	dialerEndpoint := privval.NewSignerDialerEndpoint(
+		th.logger,
+		privval.DialTCPFn(
+			th.addr,
+			time.Duration(defaultConnDeadline)*time.Millisecond,
+			ed25519.GenPrivKey(),
+		),
+	)
+
+	return privval.NewSignerServer(dialerEndpoint, th.chainID, mockPV)
+}
+
 // For running relatively standard tests.
 func harnessTest(t *testing.T, signerServerMaker func(th *TestHarness) *privval.SignerServer, expectedExitCode int) {
-	cfg := makeConfig(t, 100, 3)
+	harnessTestWithGenesis(t, genesisFileContents, signerServerMaker, expectedExitCode)
+}
+
+func harnessTestWithGenesis(
+	t *testing.T,
+	genesis string,
+	signerServerMaker func(th *TestHarness) *privval.SignerServer,
+	expectedExitCode int,
+) {
+	cfg := makeConfig(t, 100, 3, genesis)
 	defer cleanup(cfg)
 
 	th, err := NewTestHarness(log.TestingLogger(), cfg)
@@ -164,12 +243,12 @@ func harnessTest(t *testing.T, signerServerMaker func(th *TestHarness) *privval.
 	assert.Equal(t, expectedExitCode, th.exitCode)
 }
 
-func makeConfig(t *testing.T, acceptDeadline, acceptRetries int) TestHarnessConfig {
+func makeConfig(t *testing.T, acceptDeadline, acceptRetries int, genesis string) TestHarnessConfig {
 	return TestHarnessConfig{
 		BindAddr:         privval.GetFreeLocalhostAddrPort(),
 		KeyFile:          makeTempFile("tm-testharness-keyfile", keyFileContents),
 		StateFile:        makeTempFile("tm-testharness-statefile", stateFileContents),
-		GenesisFile:      makeTempFile("tm-testharness-genesisfile", genesisFileContents),
+		GenesisFile:      makeTempFile("tm-testharness-genesisfile", genesis),
 		AcceptDeadline:   time.Duration(acceptDeadline) * time.Millisecond,
 		ConnDeadline:     time.Duration(defaultConnDeadline) * time.Millisecond,
 		AcceptRetries:    acceptRetries,
@@ -178,6 +257,17 @@ func makeConfig(t *testing.T, acceptDeadline, acceptRetries int) TestHarnessConf
 	}
 }
 
+// blsGenesisFileContents returns the test genesis with the BLS key set of
+// keyring.
+func blsGenesisFileContents(t *testing.T, keyring *blsShare.BLSKeyring) string {
+	masterPubKey, err := blsShare.DumpMasterPubKey(keyring.MasterPubKey)
+	require.NoError(t, err)
+	return strings.Replace(genesisFileContents, `"app_hash": ""`, fmt.Sprintf(`"app_hash": "",
+	"bls_master_pub_key": "%s",
+	"bls_threshold": "%d",
+	"bls_num_shares": "%d"`, masterPubKey, keyring.T, keyring.N), 1)
+}
+
 func cleanup(cfg TestHarnessConfig) {
 	os.Remove(cfg.KeyFile)
 	os.Remove(cfg.StateFile)
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/corestario/dkglib/lib/blsShare"
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/merkle"
@@ -17,7 +18,7 @@ import (
 
 const (
 	// MaxHeaderBytes is a maximum header size (including amino overhead).
-	MaxHeaderBytes int64 = 653
+	MaxHeaderBytes int64 = 723
 
 	// MaxAminoOverheadForBlock - maximum amino overhead to encode a block (up to
 	// MaxBlockSizeBytes in size) not including it's parts except Data.
@@ -39,6 +40,9 @@ type Block struct {
 	Data       `json:"data"`
 	Evidence   EvidenceData `json:"evidence"`
 	LastCommit *Commit      `json:"last_commit"`
+
+	// change of the random beacon key committed in the block, may be nil
+	RandomBeaconKeyChange *RandomBeaconKeyChange `json:"random_beacon_key_change"`
 }
 
 // ValidateBasic performs basic validation that doesn't involve state data.
@@ -154,6 +158,26 @@ func (b *Block) ValidateBasic() error {
 			crypto.AddressSize, len(b.ProposerAddress))
 	}
 
+	// Validate the random beacon key change and its hash.
+	// Will validate the key fully against state in state#ValidateBlock.
+	if err := ValidateHash(b.RandomBeaconKeyHash); err != nil {
+		return fmt.Errorf("Wrong Header.RandomBeaconKeyHash: %v", err)
+	}
+	if err := ValidateHash(b.RandomBeaconKeyChangeHash); err != nil {
+		return fmt.Errorf("Wrong Header.RandomBeaconKeyChangeHash: %v", err)
+	}
+	if b.RandomBeaconKeyChange != nil {
+		if err := b.RandomBeaconKeyChange.ValidateBasic(); err != nil {
+			return fmt.Errorf("Invalid RandomBeaconKeyChange: %v", err)
+		}
+	}
+	if !bytes.Equal(b.RandomBeaconKeyChangeHash, b.RandomBeaconKeyChange.Hash()) {
+		return fmt.Errorf("Wrong Header.RandomBeaconKeyChangeHash. Expected %v, got %v",
+			cmn.HexBytes(b.RandomBeaconKeyChange.Hash()),
+			b.RandomBeaconKeyChangeHash,
+		)
+	}
+
 	return nil
 }
 
@@ -168,6 +192,9 @@ func (b *Block) fillHeader() {
 	if b.EvidenceHash == nil {
 		b.EvidenceHash = b.Evidence.Hash()
 	}
+	if b.RandomBeaconKeyChangeHash == nil {
+		b.RandomBeaconKeyChangeHash = b.RandomBeaconKeyChange.Hash()
+	}
 }
 
 // Hash computes and returns the block hash.
@@ -241,11 +268,13 @@ func (b *Block) StringIndented(indent string) string {
 %s  %v
 %s  %v
 %s  %v
+%s  %v
 %s}#%v`,
 		indent, b.Header.StringIndented(indent+"  "),
 		indent, b.Data.StringIndented(indent+"  "),
 		indent, b.Evidence.StringIndented(indent+"  "),
 		indent, b.LastCommit.StringIndented(indent+"  "),
+		indent, b.RandomBeaconKeyChange,
 		indent, b.Hash())
 }
 
@@ -262,6 +291,16 @@ func (h *Header) SetRandomData(randomData []byte) {
 	h.RandomHash = h.getRandomHash()
 }
 
+// MakeRandomMessage returns the message whose threshold signature becomes the
+// random data of the next block: the random data of the previous block
+// followed by the seed the application returned in its EndBlock response.
+// The seed may be nil.
+func MakeRandomMessage(prevRandomData, seed []byte) []byte {
+	msg := make([]byte, 0, len(prevRandomData)+len(seed))
+	msg = append(msg, prevRandomData...)
+	return append(msg, seed...)
+}
+
 func (h *Header) getRandomHash() cmn.HexBytes {
 	return merkle.SimpleHashFromByteSlices([][]byte{
 		cdcEncode(h.RandomData),
@@ -376,6 +415,10 @@ type Header struct {
 
 	RandomData []byte       `json:"random_number"`
 	RandomHash cmn.HexBytes `json:"final_hash"`
+
+	// random beacon info
+	RandomBeaconKeyHash       cmn.HexBytes `json:"random_beacon_key_hash"`        // key the random data is signed with
+	RandomBeaconKeyChangeHash cmn.HexBytes `json:"random_beacon_key_change_hash"` // key change included in the block
 }
 
 // Populate the Header with state-derived data.
@@ -403,6 +446,9 @@ func (h *Header) Populate(
 // Hash returns the hash of the header.
 // It computes a Merkle tree from the header fields
 // ordered as they appear in the Header.
+// The random data is not part of the hash, as it is only known once the block
+// is committed, and the random beacon hashes are only part of it on chains
+// with a random beacon key, so that the hashes of other headers stay the same.
 // Returns nil if ValidatorHash is missing,
 // since a Header is not valid unless there is
 // a ValidatorsHash (corresponding to the validator set).
@@ -410,7 +456,7 @@ func (h *Header) Hash() cmn.HexBytes {
 	if h == nil || len(h.ValidatorsHash) == 0 {
 		return nil
 	}
-	return merkle.SimpleHashFromByteSlices([][]byte{
+	fields := [][]byte{
 		cdcEncode(h.Version),
 		cdcEncode(h.ChainID),
 		cdcEncode(h.Height),
@@ -427,7 +473,14 @@ func (h *Header) Hash() cmn.HexBytes {
 		cdcEncode(h.LastResultsHash),
 		cdcEncode(h.EvidenceHash),
 		cdcEncode(h.ProposerAddress),
-	})
+	}
+	if len(h.RandomBeaconKeyHash) > 0 || len(h.RandomBeaconKeyChangeHash) > 0 {
+		fields = append(fields,
+			cdcEncode(h.RandomBeaconKeyHash),
+			cdcEncode(h.RandomBeaconKeyChangeHash),
+		)
+	}
+	return merkle.SimpleHashFromByteSlices(fields)
 }
 
 // StringIndented returns a string representation of the header
@@ -452,6 +505,8 @@ func (h *Header) StringIndented(indent string) string {
 %s  Results:        %v
 %s  Evidence:       %v
 %s  Proposer:       %v
+%s  RandomBeaconKey: %v
+%s  RandomBeaconKeyChange: %v
 %s}#%v`,
 		indent, h.Version,
 		indent, h.ChainID,
@@ -469,6 +524,8 @@ func (h *Header) StringIndented(indent string) string {
 		indent, h.LastResultsHash,
 		indent, h.EvidenceHash,
 		indent, h.ProposerAddress,
+		indent, h.RandomBeaconKeyHash,
+		indent, h.RandomBeaconKeyChangeHash,
 		indent, h.Hash())
 }
 
@@ -508,6 +565,13 @@ type Commit struct {
 	BlockID    BlockID      `json:"block_id"`
 	Precommits []*CommitSig `json:"precommits"`
 
+	// AggregateSigners marks the precommits whose random shares the
+	// aggregate signature of the block, i.e. Header.RandomData, was recovered
+	// from. It is only set on the commits saved by the block store, i.e. the
+	// seen commits and the canonical commits that have all of the marked
+	// precommits, and is not covered by the hash of the commit.
+	AggregateSigners *cmn.BitArray `json:"aggregate_signers"`
+
 	// memoized in first call to corresponding method
 	// NOTE: can't memoize in constructor because constructor
 	// isn't used for unmarshaling
@@ -568,7 +632,23 @@ func (commit *Commit) GetVote(valIdx int) *Vote {
 		ValidatorAddress: commitSig.ValidatorAddress,
 		ValidatorIndex:   valIdx,
 		Signature:        commitSig.Signature,
+		BLSSignature:     commitSig.BLSSignature,
+	}
+}
+
+// AggregateShares returns the precommits marked by AggregateSigners, which
+// carry the random shares the aggregate signature was recovered from.
+func (commit *Commit) AggregateShares() []blsShare.BLSSigner {
+	var shares []blsShare.BLSSigner
+	if commit.AggregateSigners == nil {
+		return shares
+	}
+	for idx := 0; idx < len(commit.Precommits); idx++ {
+		if commit.AggregateSigners.GetIndex(idx) && commit.Precommits[idx] != nil {
+			shares = append(shares, commit.GetVote(idx))
+		}
 	}
+	return shares
 }
 
 // VoteSignBytes constructs the SignBytes for the given CommitSig.
@@ -681,6 +761,23 @@ func (commit *Commit) ValidateBasic() error {
 				round, precommit.Round)
 		}
 	}
+
+	// Validate the signers of the aggregate signature.
+	if commit.AggregateSigners != nil {
+		if commit.AggregateSigners.Size() != len(commit.Precommits) {
+			return fmt.Errorf("Wrong aggregate signers size. Expected %v, got %v",
+				len(commit.Precommits), commit.AggregateSigners.Size())
+		}
+		for idx, precommit := range commit.Precommits {
+			if !commit.AggregateSigners.GetIndex(idx) {
+				continue
+			}
+			if precommit == nil || !precommit.BlockID.Equals(commit.BlockID) ||
+				len(precommit.BLSSignature) == 0 {
+				return fmt.Errorf("Aggregate signer #%d has no random share for the block", idx)
+			}
+		}
+	}
 	return nil
 }
 
@@ -712,10 +809,12 @@ func (commit *Commit) StringIndented(indent string) string {
 %s  BlockID:    %v
 %s  Precommits:
 %s    %v
+%s  Aggregate:  %v
 %s}#%v`,
 		indent, commit.BlockID,
 		indent,
 		indent, strings.Join(precommitStrings, "\n"+indent+"    "),
+		indent, commit.AggregateSigners,
 		indent, commit.hash)
 }
 
@@ -1,9 +1,17 @@
 package types
 
+import (
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
 // BlockMeta contains meta information about a block - namely, it's ID and Header.
 type BlockMeta struct {
 	BlockID BlockID `json:"block_id"` // the block hash and partsethash
 	Header  Header  `json:"header"`   // The block's Header
+
+	// The signers of the random data of the block, see Commit.AggregateSigners.
+	// They are set by the block store from the seen commit of the block.
+	AggregateSigners *cmn.BitArray `json:"aggregate_signers"`
 }
 
 // NewBlockMeta returns a new BlockMeta from the block and its blockParts.
@@ -8,6 +8,7 @@ import (
 	"math"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -232,6 +233,17 @@ func TestCommitValidateBasic(t *testing.T) {
 		{"Incorrect type", func(com *Commit) { com.Precommits[0].Type = PrevoteType }, true},
 		{"Incorrect height", func(com *Commit) { com.Precommits[0].Height = int64(100) }, true},
 		{"Incorrect round", func(com *Commit) { com.Precommits[0].Round = 100 }, true},
+		{"Aggregate signers", func(com *Commit) {
+			com.Precommits[0].BLSSignature = []byte{1}
+			com.AggregateSigners = aggregateSigners(len(com.Precommits), 0)
+		}, false},
+		{"Aggregate signer without random share", func(com *Commit) {
+			com.AggregateSigners = aggregateSigners(len(com.Precommits), 0)
+		}, true},
+		{"Incorrect aggregate signers size", func(com *Commit) {
+			com.Precommits[0].BLSSignature = []byte{1}
+			com.AggregateSigners = aggregateSigners(len(com.Precommits)+1, 0)
+		}, true},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -243,6 +255,26 @@ func TestCommitValidateBasic(t *testing.T) {
 	}
 }
 
+func aggregateSigners(size int, indices ...int) *cmn.BitArray {
+	signers := cmn.NewBitArray(size)
+	for _, idx := range indices {
+		signers.SetIndex(idx, true)
+	}
+	return signers
+}
+
+func TestCommitAggregateShares(t *testing.T) {
+	com := randCommit()
+	assert.Empty(t, com.AggregateShares())
+
+	com.Precommits[0].BLSSignature = []byte{1}
+	com.AggregateSigners = aggregateSigners(len(com.Precommits), 0)
+	shares := com.AggregateShares()
+	require.Len(t, shares, 1)
+	assert.Equal(t, []byte{1}, shares[0].GetBLSSignature())
+	assert.Equal(t, com.GetVote(0), shares[0])
+}
+
 func TestHeaderHash(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -266,6 +298,27 @@ func TestHeaderHash(t *testing.T) {
 			LastResultsHash:    tmhash.Sum([]byte("last_results_hash")),
 			EvidenceHash:       tmhash.Sum([]byte("evidence_hash")),
 			ProposerAddress:    crypto.AddressHash([]byte("proposer_address")),
+
+			RandomBeaconKeyHash:       tmhash.Sum([]byte("random_beacon_key_hash")),
+			RandomBeaconKeyChangeHash: tmhash.Sum([]byte("random_beacon_key_change_hash")),
+		}, hexBytesFromString("1922C1116A231323D33FACCF7B4B3ED25D11D8884FE355A3A83D457A19AE1E49")},
+		{"Generates expected hash without random beacon key", &Header{
+			Version:            version.Consensus{Block: 1, App: 2},
+			ChainID:            "chainId",
+			Height:             3,
+			Time:               time.Date(2019, 10, 13, 16, 14, 44, 0, time.UTC),
+			NumTxs:             4,
+			TotalTxs:           5,
+			LastBlockID:        makeBlockID(make([]byte, tmhash.Size), 6, make([]byte, tmhash.Size)),
+			LastCommitHash:     tmhash.Sum([]byte("last_commit_hash")),
+			DataHash:           tmhash.Sum([]byte("data_hash")),
+			ValidatorsHash:     tmhash.Sum([]byte("validators_hash")),
+			NextValidatorsHash: tmhash.Sum([]byte("next_validators_hash")),
+			ConsensusHash:      tmhash.Sum([]byte("consensus_hash")),
+			AppHash:            tmhash.Sum([]byte("app_hash")),
+			LastResultsHash:    tmhash.Sum([]byte("last_results_hash")),
+			EvidenceHash:       tmhash.Sum([]byte("evidence_hash")),
+			ProposerAddress:    crypto.AddressHash([]byte("proposer_address")),
 		}, hexBytesFromString("A37A7A69D89D3A66D599B0914A53F959EFE490EE9B449C95852F6FB331D58D07")},
 		{"nil header yields nil", nil, nil},
 		{"nil ValidatorsHash yields nil", &Header{
@@ -303,6 +356,10 @@ func TestHeaderHash(t *testing.T) {
 						continue
 					}
 					f := s.Field(i)
+					// headers without random beacon key hash the same as before
+					if strings.HasPrefix(fieldName, "RandomBeacon") && f.IsZero() {
+						continue
+					}
 					assert.False(t, f.IsZero(), "Found zero-valued field %v",
 						fieldName)
 					byteSlices = append(byteSlices, cdcEncode(f.Interface()))
@@ -345,6 +402,9 @@ func TestMaxHeaderBytes(t *testing.T) {
 		LastResultsHash:    tmhash.Sum([]byte("last_results_hash")),
 		EvidenceHash:       tmhash.Sum([]byte("evidence_hash")),
 		ProposerAddress:    crypto.AddressHash([]byte("proposer_address")),
+
+		RandomBeaconKeyHash:       tmhash.Sum([]byte("random_beacon_key_hash")),
+		RandomBeaconKeyChangeHash: tmhash.Sum([]byte("random_beacon_key_change_hash")),
 	}
 
 	bz, err := cdc.MarshalBinaryLengthPrefixed(h)
@@ -382,9 +442,9 @@ func TestBlockMaxDataBytes(t *testing.T) {
 	}{
 		0: {-10, 1, 0, true, 0},
 		1: {10, 1, 0, true, 0},
-		2: {886, 1, 0, true, 0},
-		3: {887, 1, 0, false, 0},
-		4: {888, 1, 0, false, 1},
+		2: {956, 1, 0, true, 0},
+		3: {957, 1, 0, false, 0},
+		4: {958, 1, 0, false, 1},
 	}
 
 	for i, tc := range testCases {
@@ -411,9 +471,9 @@ func TestBlockMaxDataBytesUnknownEvidence(t *testing.T) {
 	}{
 		0: {-10, 1, true, 0},
 		1: {10, 1, true, 0},
-		2: {984, 1, true, 0},
-		3: {985, 1, false, 0},
-		4: {986, 1, false, 1},
+		2: {1062, 1, true, 0},
+		3: {1063, 1, false, 0},
+		4: {1064, 1, false, 1},
 	}
 
 	for i, tc := range testCases {
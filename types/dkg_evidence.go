@@ -0,0 +1,213 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
+	vss "go.dedis.ch/kyber/v3/share/vss/rabin"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// The types of the DKG messages, as numbered by dkglib.
+const (
+	DKGMessageTypePubKey = iota
+	DKGMessageTypeDeal
+	DKGMessageTypeResponse
+	DKGMessageTypeJustification
+	DKGMessageTypeCommits
+	DKGMessageTypeComplaint
+	DKGMessageTypeReconstructCommit
+)
+
+// MaxDKGMessageEntities is the maximum number of sub-entities of the data of a
+// DKG message, e.g. of secret commits, which is bound by the number of
+// validators. The DKG allocates one entity per NumEntities before decoding the
+// data, so the limit has to be checked first.
+const MaxDKGMessageEntities = MaxVotesCount
+
+// DKGMessage is a DKG message signed by a validator. It has the fields of the
+// DKGData of dkglib, in the same order, so that it has the same sign bytes.
+type DKGMessage struct {
+	Type        int
+	Addr        []byte
+	RoundID     int
+	Data        []byte // gob encoded kyber objects
+	ToIndex     int
+	NumEntities int
+	Signature   []byte
+}
+
+// SignBytes returns the bytes the validator signed, i.e. the message without
+// its signature. Unlike votes, the DKG messages are not bound to a chain.
+func (m DKGMessage) SignBytes() []byte {
+	m.Signature = nil
+	return cdc.MustMarshalBinaryLengthPrefixed(m)
+}
+
+// String returns a string representation of the message.
+func (m DKGMessage) String() string {
+	return fmt.Sprintf("DKGMessage{Type: %d, Addr: %v, RoundID: %d, ToIndex: %d, Data: %X}",
+		m.Type, crypto.Address(m.Addr), m.RoundID, m.ToIndex, cmn.Fingerprint(m.Data))
+}
+
+// ValidateBasic performs basic validation.
+func (m DKGMessage) ValidateBasic() error {
+	if m.NumEntities < 0 {
+		return errors.New("Negative NumEntities")
+	}
+	if m.NumEntities > MaxDKGMessageEntities {
+		return fmt.Errorf("NumEntities is too big: %d (max: %d)", m.NumEntities, MaxDKGMessageEntities)
+	}
+	return nil
+}
+
+// DataError returns an error if the data of the message can not be decoded
+// the way the DKG decodes the data of a message of its type. Honest
+// validators never send such data. Only the messages whose data has no
+// interface fields are checked, and the data of the others never is an error.
+// The data of a message that fails ValidateBasic is not decoded.
+func (m DKGMessage) DataError() error {
+	if err := m.ValidateBasic(); err != nil {
+		return err
+	}
+	suite := bn256.NewSuiteG2()
+	var v interface{}
+	switch m.Type {
+	case DKGMessageTypePubKey:
+		v = suite.Point()
+	case DKGMessageTypeDeal:
+		v = &dkg.Deal{Deal: &vss.EncryptedDeal{DHKey: suite.Point()}}
+	case DKGMessageTypeResponse:
+		v = &dkg.Response{}
+	case DKGMessageTypeCommits:
+		commits := &dkg.SecretCommits{}
+		for i := 0; i < m.NumEntities; i++ {
+			commits.Commitments = append(commits.Commitments, suite.Point())
+		}
+		v = commits
+	default:
+		return nil
+	}
+	return gob.NewDecoder(bytes.NewBuffer(m.Data)).Decode(v)
+}
+
+// DKGEvidenceCorruptData contains evidence that a validator sent a DKG message
+// with corrupt data, i.e. data that can not be decoded. Such a message fails
+// the DKG round it is sent in. The message is signed by the validator, so the
+// evidence can be verified by anyone. A validator that failed to send its DKG
+// messages can not be told from one whose messages were lost, so there is no
+// evidence for missing DKG data.
+type DKGEvidenceCorruptData struct {
+	PubKey  crypto.PubKey
+	Height_ int64 // height at which the DKG message was received
+	Message DKGMessage
+}
+
+var _ Evidence = &DKGEvidenceCorruptData{}
+
+// NewDKGEvidenceCorruptData returns evidence that the validator with the given
+// public key sent the DKG message msg, whose data is corrupt.
+func NewDKGEvidenceCorruptData(pubKey crypto.PubKey, height int64, msg DKGMessage) *DKGEvidenceCorruptData {
+	return &DKGEvidenceCorruptData{
+		PubKey:  pubKey,
+		Height_: height,
+		Message: msg,
+	}
+}
+
+// String returns a string representation of the evidence.
+func (m *DKGEvidenceCorruptData) String() string {
+	return fmt.Sprintf("DKGEvidenceCorruptData{Address: %v, Height: %d, Message: %v}",
+		m.PubKey.Address(), m.Height_, m.Message)
+}
+
+// Height returns the height this evidence refers to.
+func (m *DKGEvidenceCorruptData) Height() int64 {
+	return m.Height_
+}
+
+// Address returns the address of the validator.
+func (m *DKGEvidenceCorruptData) Address() []byte {
+	return m.PubKey.Address()
+}
+
+// Bytes returns the bytes which compromise the evidence.
+func (m *DKGEvidenceCorruptData) Bytes() []byte {
+	return cdcEncode(m)
+}
+
+// Hash returns the hash of the evidence.
+func (m *DKGEvidenceCorruptData) Hash() []byte {
+	return tmhash.Sum(cdcEncode(m))
+}
+
+// Verify returns an error if the evidence does not refer to the validator
+// with the given public key, if the DKG message was not signed by it or if the
+// data of the message is not corrupt.
+func (m *DKGEvidenceCorruptData) Verify(chainID string, pubKey crypto.PubKey) error {
+	if !pubKey.Equals(m.PubKey) {
+		return fmt.Errorf("DKGEvidenceCorruptData Error: pubkey (%v) doesn't match validator pubkey (%v)",
+			m.PubKey, pubKey)
+	}
+	if !bytes.Equal(m.Message.Addr, pubKey.Address()) {
+		return fmt.Errorf("DKGEvidenceCorruptData Error: message address (%v) doesn't match validator address (%v)",
+			crypto.Address(m.Message.Addr), pubKey.Address())
+	}
+	if err := m.Message.ValidateBasic(); err != nil {
+		return fmt.Errorf("DKGEvidenceCorruptData Error: invalid DKG message: %v", err)
+	}
+	if !pubKey.VerifyBytes(m.Message.SignBytes(), m.Message.Signature) {
+		return errors.New("DKGEvidenceCorruptData Error: invalid DKG message signature")
+	}
+	if m.Message.DataError() == nil {
+		return errors.New("DKGEvidenceCorruptData Error: the data of the DKG message is not corrupt")
+	}
+	return nil
+}
+
+// Equal checks if two pieces of evidence are equal.
+func (m *DKGEvidenceCorruptData) Equal(ev Evidence) bool {
+	if _, ok := ev.(*DKGEvidenceCorruptData); !ok {
+		return false
+	}
+
+	// just check their hashes
+	mHash := tmhash.Sum(cdcEncode(m))
+	evHash := tmhash.Sum(cdcEncode(ev))
+	return bytes.Equal(mHash, evHash)
+}
+
+// ValidateBasic performs basic validation.
+func (m *DKGEvidenceCorruptData) ValidateBasic() error {
+	if m.PubKey == nil || len(m.PubKey.Bytes()) == 0 {
+		return errors.New("Empty PubKey")
+	}
+	if m.Height_ <= 0 {
+		return errors.New("Height must be greater than 0")
+	}
+	if len(m.Message.Addr) != crypto.AddressSize {
+		return fmt.Errorf("Expected message address size to be %d bytes, got %d bytes",
+			crypto.AddressSize, len(m.Message.Addr))
+	}
+	if len(m.Message.Signature) == 0 {
+		return errors.New("Empty Signature")
+	}
+	if len(m.Message.Signature) > MaxSignatureSize {
+		return fmt.Errorf("Signature is too big (max: %d)", MaxSignatureSize)
+	}
+	if err := m.Message.ValidateBasic(); err != nil {
+		return fmt.Errorf("Invalid Message: %v", err)
+	}
+	// Evidence must fit into the space reserved for it in a block.
+	if size := int64(len(cdc.MustMarshalBinaryLengthPrefixed(m))); size > MaxEvidenceBytes {
+		return fmt.Errorf("Evidence is too big: %d bytes (max: %d)", size, MaxEvidenceBytes)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// signedDKGMessage returns the DKG message of the given type and data, signed
+// with privKey.
+func signedDKGMessage(t *testing.T, privKey crypto.PrivKey, msgType int, data []byte) DKGMessage {
+	msg := DKGMessage{
+		Type:    msgType,
+		Addr:    privKey.PubKey().Address(),
+		RoundID: 1,
+		Data:    data,
+	}
+	sig, err := privKey.Sign(msg.SignBytes())
+	require.NoError(t, err)
+	msg.Signature = sig
+	return msg
+}
+
+func TestDKGMessageDataError(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, gob.NewEncoder(buf).Encode(suite.Point().Pick(suite.RandomStream())))
+	pubKeyData := buf.Bytes()
+
+	testCases := []struct {
+		msgType int
+		data    []byte
+		corrupt bool
+	}{
+		{DKGMessageTypePubKey, pubKeyData, false},
+		{DKGMessageTypePubKey, []byte("corrupt"), true},
+		{DKGMessageTypePubKey, nil, true},
+		{DKGMessageTypeDeal, []byte("corrupt"), true},
+		{DKGMessageTypeResponse, []byte("corrupt"), true},
+		{DKGMessageTypeCommits, []byte("corrupt"), true},
+		// the data of the messages with interface fields is not checked
+		{DKGMessageTypeJustification, []byte("corrupt"), false},
+		{DKGMessageTypeComplaint, []byte("corrupt"), false},
+		{DKGMessageTypeReconstructCommit, []byte("corrupt"), false},
+	}
+	for i, tc := range testCases {
+		err := DKGMessage{Type: tc.msgType, Data: tc.data}.DataError()
+		assert.Equal(t, tc.corrupt, err != nil, "#%d: %v", i, err)
+	}
+}
+
+// Ensure the DKG messages with too many entities are rejected before an
+// entity is allocated for each of them.
+func TestDKGMessageNumEntities(t *testing.T) {
+	privKey := ed25519.GenPrivKey()
+	pubKey := privKey.PubKey()
+	for _, numEntities := range []int{-1, MaxDKGMessageEntities + 1, 1 << 40} {
+		msg := DKGMessage{
+			Type:        DKGMessageTypeCommits,
+			Addr:        pubKey.Address(),
+			RoundID:     1,
+			Data:        []byte("corrupt"),
+			NumEntities: numEntities,
+		}
+		sig, err := privKey.Sign(msg.SignBytes())
+		require.NoError(t, err)
+		msg.Signature = sig
+		assert.NotNil(t, msg.ValidateBasic(), "%d", numEntities)
+
+		ev := NewDKGEvidenceCorruptData(pubKey, 10, msg)
+		assert.NotNil(t, ev.ValidateBasic(), "%d", numEntities)
+		allocs := testing.AllocsPerRun(1, func() {
+			assert.NotNil(t, msg.DataError(), "%d", numEntities)
+			assert.NotNil(t, ev.Verify("mychain", pubKey), "%d", numEntities)
+		})
+		assert.True(t, allocs < 100, "%d: %v allocations", numEntities, allocs)
+	}
+}
+
+func TestDKGEvidenceCorruptData(t *testing.T) {
+	privKey := ed25519.GenPrivKey()
+	pubKey := privKey.PubKey()
+	msg := signedDKGMessage(t, privKey, DKGMessageTypePubKey, []byte("corrupt"))
+
+	ev := NewDKGEvidenceCorruptData(pubKey, 10, msg)
+	assert.Nil(t, ev.ValidateBasic())
+	assert.EqualValues(t, 10, ev.Height())
+	assert.Equal(t, pubKey.Address().Bytes(), ev.Address())
+	assert.Nil(t, ev.Verify("mychain", pubKey))
+	assert.NotNil(t, ev.Verify("mychain", ed25519.GenPrivKey().PubKey()))
+	assert.True(t, ev.Equal(NewDKGEvidenceCorruptData(pubKey, 10, msg)))
+	assert.False(t, ev.Equal(NewDKGEvidenceCorruptData(pubKey, 11, msg)))
+	assert.False(t, ev.Equal(NewMockGoodEvidence(10, 0, pubKey.Address())))
+
+	// the evidence survives an amino round trip
+	var decoded Evidence
+	require.NoError(t, cdc.UnmarshalBinaryBare(cdcEncode(Evidence(ev)), &decoded))
+	assert.True(t, ev.Equal(decoded))
+	assert.Nil(t, decoded.Verify("mychain", pubKey))
+
+	// the message of another validator
+	otherKey := ed25519.GenPrivKey()
+	otherMsg := signedDKGMessage(t, otherKey, DKGMessageTypePubKey, []byte("corrupt"))
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 10, otherMsg).Verify("mychain", pubKey))
+
+	// a forged signature
+	forged := msg
+	forged.Data = []byte("forged")
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 10, forged).Verify("mychain", pubKey))
+
+	// a message whose data is not corrupt
+	suite := bn256.NewSuiteG2()
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, gob.NewEncoder(buf).Encode(suite.Point().Pick(suite.RandomStream())))
+	validMsg := signedDKGMessage(t, privKey, DKGMessageTypePubKey, buf.Bytes())
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 10, validMsg).Verify("mychain", pubKey))
+
+	// invalid evidence
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 0, msg).ValidateBasic())
+	noSig := msg
+	noSig.Signature = nil
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 10, noSig).ValidateBasic())
+	tooBig := signedDKGMessage(t, privKey, DKGMessageTypePubKey, make([]byte, MaxEvidenceBytes))
+	assert.NotNil(t, NewDKGEvidenceCorruptData(pubKey, 10, tooBig).ValidateBasic())
+}
@@ -217,10 +217,44 @@ func (b *EventBus) PublishEventLock(data EventDataRoundState) error {
 	return b.Publish(EventLock, data)
 }
 
+func (b *EventBus) PublishEventRandomDataRecoveryFailed(data EventDataRoundState) error {
+	return b.Publish(EventRandomDataRecoveryFailed, data)
+}
+
 func (b *EventBus) PublishEventValidatorSetUpdates(data EventDataValidatorSetUpdates) error {
 	return b.Publish(EventValidatorSetUpdates, data)
 }
 
+func (b *EventBus) PublishEventRandomData(data EventDataRandomData) error {
+	return b.Publish(EventRandomData, data)
+}
+
+func (b *EventBus) PublishEventDKGRoundStarted(data EventDataDKGRound) error {
+	return b.Publish(EventDKGRoundStarted, data)
+}
+
+func (b *EventBus) PublishEventDKGRoundCompleted(data EventDataDKGRound) error {
+	return b.Publish(EventDKGRoundCompleted, data)
+}
+
+func (b *EventBus) PublishEventDKGRoundFailed(data EventDataDKGRound) error {
+	return b.Publish(EventDKGRoundFailed, data)
+}
+
+// PublishEventExpiredTx publishes the expired tx event with the predefined
+// tags (EventTypeKey, TxHashKey), so clients can subscribe to the event of
+// their tx.
+func (b *EventBus) PublishEventExpiredTx(data EventDataExpiredTx) error {
+	// no explicit deadline for publishing events
+	ctx := context.Background()
+
+	events := map[string][]string{
+		EventTypeKey: {EventExpiredTx},
+		TxHashKey:    {fmt.Sprintf("%X", data.Tx.Hash())},
+	}
+	return b.pubsub.PublishWithEvents(ctx, data, events)
+}
+
 //-----------------------------------------------------------------------------
 type NopEventBus struct{}
 
@@ -285,6 +319,10 @@ func (NopEventBus) PublishEventUnlock(data EventDataRoundState) error {
 	return nil
 }
 
+func (NopEventBus) PublishEventRandomDataRecoveryFailed(data EventDataRoundState) error {
+	return nil
+}
+
 func (NopEventBus) PublishEventRelock(data EventDataRoundState) error {
 	return nil
 }
@@ -296,3 +334,23 @@ func (NopEventBus) PublishEventLock(data EventDataRoundState) error {
 func (NopEventBus) PublishEventValidatorSetUpdates(data EventDataValidatorSetUpdates) error {
 	return nil
 }
+
+func (NopEventBus) PublishEventRandomData(data EventDataRandomData) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventDKGRoundStarted(data EventDataDKGRound) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventDKGRoundCompleted(data EventDataDKGRound) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventDKGRoundFailed(data EventDataDKGRound) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventExpiredTx(data EventDataExpiredTx) error {
+	return nil
+}
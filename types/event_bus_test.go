@@ -260,7 +260,7 @@ func TestEventBusPublish(t *testing.T) {
 	require.NoError(t, err)
 	defer eventBus.Stop()
 
-	const numEventsExpected = 14
+	const numEventsExpected = 20
 
 	sub, err := eventBus.Subscribe(context.Background(), "test", tmquery.Empty{}, numEventsExpected)
 	require.NoError(t, err)
@@ -303,8 +303,20 @@ func TestEventBusPublish(t *testing.T) {
 	require.NoError(t, err)
 	err = eventBus.PublishEventLock(EventDataRoundState{})
 	require.NoError(t, err)
+	err = eventBus.PublishEventRandomDataRecoveryFailed(EventDataRoundState{})
+	require.NoError(t, err)
 	err = eventBus.PublishEventValidatorSetUpdates(EventDataValidatorSetUpdates{})
 	require.NoError(t, err)
+	err = eventBus.PublishEventRandomData(EventDataRandomData{})
+	require.NoError(t, err)
+	err = eventBus.PublishEventDKGRoundStarted(EventDataDKGRound{})
+	require.NoError(t, err)
+	err = eventBus.PublishEventDKGRoundCompleted(EventDataDKGRound{})
+	require.NoError(t, err)
+	err = eventBus.PublishEventDKGRoundFailed(EventDataDKGRound{})
+	require.NoError(t, err)
+	err = eventBus.PublishEventExpiredTx(EventDataExpiredTx{})
+	require.NoError(t, err)
 
 	select {
 	case <-done:
@@ -5,6 +5,7 @@ import (
 
 	amino "github.com/tendermint/go-amino"
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 )
@@ -24,17 +25,36 @@ const (
 	// Internal consensus events.
 	// These are used for testing the consensus state machine.
 	// They can also be used to build real-time consensus visualizers.
-	EventCompleteProposal = "CompleteProposal"
-	EventLock             = "Lock"
-	EventNewRound         = "NewRound"
-	EventNewRoundStep     = "NewRoundStep"
-	EventPolka            = "Polka"
-	EventRelock           = "Relock"
-	EventTimeoutPropose   = "TimeoutPropose"
-	EventTimeoutWait      = "TimeoutWait"
-	EventUnlock           = "Unlock"
-	EventValidBlock       = "ValidBlock"
-	EventVote             = "Vote"
+	EventCompleteProposal         = "CompleteProposal"
+	EventLock                     = "Lock"
+	EventNewRound                 = "NewRound"
+	EventNewRoundStep             = "NewRoundStep"
+	EventPolka                    = "Polka"
+	EventRandomDataRecoveryFailed = "RandomDataRecoveryFailed"
+	EventRelock                   = "Relock"
+	EventTimeoutPropose           = "TimeoutPropose"
+	EventTimeoutWait              = "TimeoutWait"
+	EventUnlock                   = "Unlock"
+	EventValidBlock               = "ValidBlock"
+	EventVote                     = "Vote"
+)
+
+// Random beacon events.
+// EventRandomData is triggered by the consensus state when a block with
+// random data has been committed. The DKG round events are triggered by the
+// consensus state for the DKG rounds producing the keys of the beacon.
+const (
+	EventDKGRoundCompleted = "DKGRoundCompleted"
+	EventDKGRoundFailed    = "DKGRoundFailed"
+	EventDKGRoundStarted   = "DKGRoundStarted"
+	EventRandomData        = "RandomData"
+)
+
+// Mempool events.
+// EventExpiredTx is triggered by the mempool when a tx is dropped because it
+// was in the mempool for longer than its TTL.
+const (
+	EventExpiredTx = "ExpiredTx"
 )
 
 //DKG events
@@ -51,6 +71,12 @@ const (
 	EventDKGReconstructCommitsProcessed = "DKGReconstructCommitsProcessed"
 	EventDKGSuccessful                  = "DKGSuccessful"
 	EventDKGKeyChange                   = "DKGKeyChange"
+	// EventDKGVerifierReady is fired with the *BLSVerifier of a DKG round
+	// once the round is over and its key is known.
+	EventDKGVerifierReady = "DKGVerifierReady"
+	// EventDKGCorruptData is fired with the DKG messages of the validators
+	// whose data is corrupt, see DKGEvidenceCorruptData.
+	EventDKGCorruptData = "DKGCorruptData"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -73,6 +99,9 @@ func RegisterEventDatas(cdc *amino.Codec) {
 	cdc.RegisterConcrete(EventDataVote{}, "tendermint/event/Vote", nil)
 	cdc.RegisterConcrete(EventDataValidatorSetUpdates{}, "tendermint/event/ValidatorSetUpdates", nil)
 	cdc.RegisterConcrete(EventDataString(""), "tendermint/event/ProposalString", nil)
+	cdc.RegisterConcrete(EventDataRandomData{}, "tendermint/event/RandomData", nil)
+	cdc.RegisterConcrete(EventDataDKGRound{}, "tendermint/event/DKGRound", nil)
+	cdc.RegisterConcrete(EventDataExpiredTx{}, "tendermint/event/ExpiredTx", nil)
 }
 
 // Most event messages are basic types (a block, a transaction)
@@ -136,6 +165,29 @@ type EventDataValidatorSetUpdates struct {
 	ValidatorUpdates []*Validator `json:"validator_updates"`
 }
 
+// EventDataRandomData carries the random data of a committed block.
+type EventDataRandomData struct {
+	Height     int64        `json:"height"`
+	RandomData cmn.HexBytes `json:"random_data"`
+}
+
+// EventDataDKGRound describes a DKG round at the given height. StartHeight is
+// the first height the key produced by a completed round is used at, Reason
+// tells why a round failed.
+type EventDataDKGRound struct {
+	RoundID     int    `json:"round_id"`
+	Height      int64  `json:"height"`
+	StartHeight int64  `json:"start_height,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// EventDataExpiredTx carries a tx dropped from the mempool at the given height
+// because it expired.
+type EventDataExpiredTx struct {
+	Height int64 `json:"height"`
+	Tx     Tx    `json:"tx"`
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBSUB
 ///////////////////////////////////////////////////////////////////////////////
@@ -152,27 +204,39 @@ const (
 )
 
 var (
-	EventQueryCompleteProposal    = QueryForEvent(EventCompleteProposal)
-	EventQueryLock                = QueryForEvent(EventLock)
-	EventQueryNewBlock            = QueryForEvent(EventNewBlock)
-	EventQueryNewBlockHeader      = QueryForEvent(EventNewBlockHeader)
-	EventQueryNewRound            = QueryForEvent(EventNewRound)
-	EventQueryNewRoundStep        = QueryForEvent(EventNewRoundStep)
-	EventQueryPolka               = QueryForEvent(EventPolka)
-	EventQueryRelock              = QueryForEvent(EventRelock)
-	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutPropose)
-	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWait)
-	EventQueryTx                  = QueryForEvent(EventTx)
-	EventQueryUnlock              = QueryForEvent(EventUnlock)
-	EventQueryValidatorSetUpdates = QueryForEvent(EventValidatorSetUpdates)
-	EventQueryValidBlock          = QueryForEvent(EventValidBlock)
-	EventQueryVote                = QueryForEvent(EventVote)
+	EventQueryCompleteProposal         = QueryForEvent(EventCompleteProposal)
+	EventQueryDKGRoundCompleted        = QueryForEvent(EventDKGRoundCompleted)
+	EventQueryDKGRoundFailed           = QueryForEvent(EventDKGRoundFailed)
+	EventQueryDKGRoundStarted          = QueryForEvent(EventDKGRoundStarted)
+	EventQueryExpiredTx                = QueryForEvent(EventExpiredTx)
+	EventQueryLock                     = QueryForEvent(EventLock)
+	EventQueryNewBlock                 = QueryForEvent(EventNewBlock)
+	EventQueryNewBlockHeader           = QueryForEvent(EventNewBlockHeader)
+	EventQueryNewRound                 = QueryForEvent(EventNewRound)
+	EventQueryNewRoundStep             = QueryForEvent(EventNewRoundStep)
+	EventQueryPolka                    = QueryForEvent(EventPolka)
+	EventQueryRandomData               = QueryForEvent(EventRandomData)
+	EventQueryRandomDataRecoveryFailed = QueryForEvent(EventRandomDataRecoveryFailed)
+	EventQueryRelock                   = QueryForEvent(EventRelock)
+	EventQueryTimeoutPropose           = QueryForEvent(EventTimeoutPropose)
+	EventQueryTimeoutWait              = QueryForEvent(EventTimeoutWait)
+	EventQueryTx                       = QueryForEvent(EventTx)
+	EventQueryUnlock                   = QueryForEvent(EventUnlock)
+	EventQueryValidatorSetUpdates      = QueryForEvent(EventValidatorSetUpdates)
+	EventQueryValidBlock               = QueryForEvent(EventValidBlock)
+	EventQueryVote                     = QueryForEvent(EventVote)
 )
 
 func EventQueryTxFor(tx Tx) tmpubsub.Query {
 	return tmquery.MustParse(fmt.Sprintf("%s='%s' AND %s='%X'", EventTypeKey, EventTx, TxHashKey, tx.Hash()))
 }
 
+// EventQueryExpiredTxFor returns a query for the ExpiredTx event of the given
+// tx.
+func EventQueryExpiredTxFor(tx Tx) tmpubsub.Query {
+	return tmquery.MustParse(fmt.Sprintf("%s='%s' AND %s='%X'", EventTypeKey, EventExpiredTx, TxHashKey, tx.Hash()))
+}
+
 func QueryForEvent(eventType string) tmpubsub.Query {
 	return tmquery.MustParse(fmt.Sprintf("%s='%s'", EventTypeKey, eventType))
 }
@@ -188,3 +252,8 @@ type BlockEventPublisher interface {
 type TxEventPublisher interface {
 	PublishEventTx(EventDataTx) error
 }
+
+// MempoolEventPublisher publishes the events of the mempool
+type MempoolEventPublisher interface {
+	PublishEventExpiredTx(EventDataExpiredTx) error
+}
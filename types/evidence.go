@@ -68,6 +68,8 @@ type Evidence interface {
 func RegisterEvidences(cdc *amino.Codec) {
 	cdc.RegisterInterface((*Evidence)(nil), nil)
 	cdc.RegisterConcrete(&DuplicateVoteEvidence{}, "tendermint/DuplicateVoteEvidence", nil)
+	cdc.RegisterConcrete(&DKGEvidenceCorruptData{}, "tendermint/DKGEvidenceCorruptData", nil)
+	cdc.RegisterConcrete(&ConflictingRandomSharesEvidence{}, "tendermint/ConflictingRandomSharesEvidence", nil)
 }
 
 func RegisterMockEvidences(cdc *amino.Codec) {
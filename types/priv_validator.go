@@ -23,6 +23,16 @@ type DataSigner interface {
 	SetSignature([]byte)
 }
 
+// RandomShareSigner is implemented by the private validators that hold the BLS
+// key share of the validator, e.g. a remote signer, so that it does not have
+// to be stored by the node. Consensus signs the random shares with the
+// verifier of the DKG for the private validators that do not implement it.
+type RandomShareSigner interface {
+	// SignRandomShare returns the share of the random data of the given
+	// height and round, i.e. the signature of msg with the BLS key share.
+	SignRandomShare(chainID string, height int64, round int, msg []byte) ([]byte, error)
+}
+
 //----------------------------------------
 // Misc.
 
@@ -15,8 +15,10 @@ import (
 // Use strings to distinguish types in ABCI messages
 
 const (
-	ABCIEvidenceTypeDuplicateVote = "duplicate/vote"
-	ABCIEvidenceTypeMockGood      = "mock/good"
+	ABCIEvidenceTypeDuplicateVote  = "duplicate/vote"
+	ABCIEvidenceTypeDKGCorruptData = "dkg/corrupt_data"
+	ABCIEvidenceTypeRandomShares   = "random/conflicting_shares"
+	ABCIEvidenceTypeMockGood       = "mock/good"
 )
 
 const (
@@ -156,6 +158,10 @@ func (tm2pb) Evidence(ev Evidence, valSet *ValidatorSet, evTime time.Time) abci.
 	switch ev.(type) {
 	case *DuplicateVoteEvidence:
 		evType = ABCIEvidenceTypeDuplicateVote
+	case *DKGEvidenceCorruptData:
+		evType = ABCIEvidenceTypeDKGCorruptData
+	case *ConflictingRandomSharesEvidence:
+		evType = ABCIEvidenceTypeRandomShares
 	case MockGoodEvidence:
 		// XXX: not great to have test types in production paths ...
 		evType = ABCIEvidenceTypeMockGood
@@ -154,6 +154,12 @@ func TestABCIEvidence(t *testing.T) {
 	)
 
 	assert.Equal(t, "duplicate/vote", abciEv.Type)
+
+	valSet := NewValidatorSet([]*Validator{NewValidator(pubKey, 10)})
+	abciEv = TM2PB.Evidence(&DKGEvidenceCorruptData{PubKey: pubKey, Height_: 10}, valSet, time.Now())
+	assert.Equal(t, "dkg/corrupt_data", abciEv.Type)
+	abciEv = TM2PB.Evidence(&ConflictingRandomSharesEvidence{PubKey: pubKey, Height_: 10}, valSet, time.Now())
+	assert.Equal(t, "random/conflicting_shares", abciEv.Type)
 }
 
 type pubKeyEddie struct{}
@@ -0,0 +1,119 @@
+// Package random derives pseudo random values from the random data of a block,
+// i.e. Header.RandomData, which ABCI applications receive as the random_number
+// of the header in BeginBlock.
+//
+// Every derivation takes a label separating its values from the values derived
+// for other purposes from the same random data, so different uses get
+// independent values. The values are unbiased and the same on every node:
+// anyone holding the random data of a block can verify them.
+package random
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// domain separates the values of this package from other hashes of the random
+// data.
+const domain = "tendermint/random/v1"
+
+// Stream is a deterministic stream of pseudo random values derived from random
+// data and a label.
+type Stream struct {
+	seed    []byte
+	counter uint64
+}
+
+// NewStream returns the stream of values derived from randomData for the
+// given label.
+func NewStream(randomData []byte, label string) *Stream {
+	h := tmhash.New()
+	h.Write([]byte(domain))
+	writeLengthPrefixed(h, []byte(label))
+	writeLengthPrefixed(h, randomData)
+	return &Stream{seed: h.Sum(nil)}
+}
+
+func writeLengthPrefixed(h hash.Hash, bz []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(bz)))
+	h.Write(length[:])
+	h.Write(bz)
+}
+
+// Uint64 returns the next value of the stream.
+func (s *Stream) Uint64() uint64 {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], s.counter)
+	s.counter++
+
+	h := tmhash.New()
+	h.Write(s.seed)
+	h.Write(counter[:])
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+// Uint64n returns the next value of the stream in [0, n). Every value of the
+// range is equally likely. It panics if n is 0.
+func (s *Stream) Uint64n(n uint64) uint64 {
+	if n == 0 {
+		panic("random: Uint64n called with n == 0")
+	}
+	// Reject the values above the largest multiple of n, they would make the
+	// smallest values of the range more likely than the others.
+	rem := (math.MaxUint64%n + 1) % n
+	for {
+		v := s.Uint64()
+		if v <= math.MaxUint64-rem {
+			return v % n
+		}
+	}
+}
+
+// DeriveUint64 returns a value derived from randomData for the given label.
+func DeriveUint64(randomData []byte, label string) uint64 {
+	return NewStream(randomData, label).Uint64()
+}
+
+// Shuffle shuffles n elements with swap, which swaps the elements with
+// indexes i and j, in an order derived from randomData for the given label.
+// Every permutation is equally likely. It panics if n < 0.
+func Shuffle(randomData []byte, label string, n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("random: Shuffle called with n < 0")
+	}
+	s := NewStream(randomData, label)
+	for i := n - 1; i > 0; i-- {
+		j := int(s.Uint64n(uint64(i + 1)))
+		swap(i, j)
+	}
+}
+
+// WeightedPick returns the index of an element of weights picked by a value
+// derived from randomData for the given label. An element is picked with the
+// probability of its weight divided by the sum of the weights.
+func WeightedPick(randomData []byte, label string, weights []uint64) (int, error) {
+	var total uint64
+	for _, weight := range weights {
+		if total+weight < total {
+			return 0, errors.New("sum of the weights overflows uint64")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return 0, errors.New("sum of the weights is zero")
+	}
+
+	v := NewStream(randomData, label).Uint64n(total)
+	for i, weight := range weights {
+		if v < weight {
+			return i, nil
+		}
+		v -= weight
+	}
+	panic("unreachable")
+}
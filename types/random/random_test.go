@@ -0,0 +1,77 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRandomData = []byte("random data of a block")
+
+func TestDeriveUint64(t *testing.T) {
+	v := DeriveUint64(testRandomData, "label")
+	assert.Equal(t, v, DeriveUint64(testRandomData, "label"))
+	assert.NotEqual(t, v, DeriveUint64(testRandomData, "other label"))
+	assert.NotEqual(t, v, DeriveUint64([]byte("other random data"), "label"))
+
+	// The label and the random data can not be shifted into each other.
+	assert.NotEqual(t, DeriveUint64([]byte("bc"), "a"), DeriveUint64([]byte("c"), "ab"))
+
+	// The first value of the stream of the label.
+	assert.Equal(t, v, NewStream(testRandomData, "label").Uint64())
+}
+
+func TestStreamUint64n(t *testing.T) {
+	s := NewStream(testRandomData, "uint64n")
+	counts := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		v := s.Uint64n(6)
+		require.True(t, v < 6)
+		counts[v]++
+	}
+	for v, count := range counts {
+		assert.InDelta(t, 1000, count, 150, "value %d", v)
+	}
+
+	assert.Panics(t, func() { s.Uint64n(0) })
+	assert.EqualValues(t, 0, s.Uint64n(1))
+}
+
+func TestShuffle(t *testing.T) {
+	shuffle := func(randomData []byte, label string) []int {
+		values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		Shuffle(randomData, label, len(values), func(i, j int) {
+			values[i], values[j] = values[j], values[i]
+		})
+		return values
+	}
+
+	values := shuffle(testRandomData, "shuffle")
+	assert.Equal(t, values, shuffle(testRandomData, "shuffle"))
+	assert.NotEqual(t, values, shuffle(testRandomData, "other shuffle"))
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, values)
+
+	Shuffle(testRandomData, "shuffle", 0, func(i, j int) { t.Fatal("unexpected swap") })
+	assert.Panics(t, func() { Shuffle(testRandomData, "shuffle", -1, func(i, j int) {}) })
+}
+
+func TestWeightedPick(t *testing.T) {
+	weights := []uint64{1, 0, 3}
+	counts := make([]int, len(weights))
+	for i := 0; i < 4000; i++ {
+		idx, err := WeightedPick([]byte{byte(i), byte(i >> 8)}, "pick", weights)
+		require.NoError(t, err)
+		counts[idx]++
+	}
+	assert.InDelta(t, 1000, counts[0], 150)
+	assert.Equal(t, 0, counts[1])
+	assert.InDelta(t, 3000, counts[2], 150)
+
+	_, err := WeightedPick(testRandomData, "pick", nil)
+	assert.Error(t, err)
+	_, err = WeightedPick(testRandomData, "pick", []uint64{0, 0})
+	assert.Error(t, err)
+	_, err = WeightedPick(testRandomData, "pick", []uint64{1 << 63, 1 << 63})
+	assert.Error(t, err)
+}
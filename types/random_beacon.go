@@ -0,0 +1,221 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// RandomBeaconKey is the public part of the BLS threshold key set the random
+// data of the blocks is signed with: the master public key, and the number of
+// shares out of NumShares needed to recover a signature.
+type RandomBeaconKey struct {
+	MasterPubKey string `json:"master_pub_key"` // as dumped by blsShare.DumpMasterPubKey
+	Threshold    int    `json:"threshold"`
+	NumShares    int    `json:"num_shares"`
+}
+
+// NewRandomBeaconKey returns the key of the given master public key and
+// threshold parameters.
+func NewRandomBeaconKey(masterPubKey *share.PubPoly, t, n int) (RandomBeaconKey, error) {
+	if masterPubKey == nil {
+		return RandomBeaconKey{}, errors.New("no master public key")
+	}
+	masterPubKeyStr, err := blsShare.DumpMasterPubKey(masterPubKey)
+	if err != nil {
+		return RandomBeaconKey{}, err
+	}
+	return RandomBeaconKey{
+		MasterPubKey: masterPubKeyStr,
+		Threshold:    t,
+		NumShares:    n,
+	}, nil
+}
+
+// IsEmpty returns true if there is no key.
+func (key RandomBeaconKey) IsEmpty() bool {
+	return key.MasterPubKey == ""
+}
+
+// Hash returns the hash of the key, or nil if there is no key. The gob
+// encoding of the master public key depends on the types the process encoded
+// before, so the same key may be dumped differently by different nodes: the
+// hash is the one of the points of the key instead.
+func (key RandomBeaconKey) Hash() []byte {
+	if key.IsEmpty() {
+		return nil
+	}
+	commits, err := key.commits()
+	if err != nil {
+		// an invalid key is only told apart by its encoding
+		return tmhash.Sum(cdcEncode(key))
+	}
+	points := make([][]byte, len(commits))
+	for i, commit := range commits {
+		if points[i], err = commit.MarshalBinary(); err != nil {
+			return tmhash.Sum(cdcEncode(key))
+		}
+	}
+	return tmhash.Sum(cdcEncode(struct {
+		Commits   [][]byte
+		Threshold int
+		NumShares int
+	}{points, key.Threshold, key.NumShares}))
+}
+
+// ValidateBasic performs basic validation.
+func (key RandomBeaconKey) ValidateBasic() error {
+	if key.IsEmpty() {
+		return errors.New("empty master public key")
+	}
+	if key.Threshold <= 0 || key.Threshold > key.NumShares {
+		return fmt.Errorf("threshold %d is out of range [1, %d]", key.Threshold, key.NumShares)
+	}
+	if _, err := key.PubPoly(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PubPoly returns the master public key.
+func (key RandomBeaconKey) PubPoly() (*share.PubPoly, error) {
+	commits, err := key.commits()
+	if err != nil {
+		return nil, err
+	}
+	return share.NewPubPoly(bn256.NewSuite().G2(), nil, commits), nil
+}
+
+// commits decodes the master public key like blsShare.LoadPubKey does, but
+// allocates no more points than the encoded key can hold rather than
+// NumShares of them.
+func (key RandomBeaconKey) commits() ([]kyber.Point, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(key.MasterPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base64-decode master public key: %v", err)
+	}
+	g2 := bn256.NewSuite().G2()
+	commits := make([]kyber.Point, len(keyBytes)/g2.PointLen()+1)
+	for i := range commits {
+		commits[i] = g2.Point()
+	}
+	if err := gob.NewDecoder(bytes.NewReader(keyBytes)).Decode(&commits); err != nil {
+		return nil, fmt.Errorf("failed to decode master public key: %v", err)
+	}
+	return commits, nil
+}
+
+// Verifier returns a verifier of the key, which signs with keypair. keypair
+// may be nil if the verifier is not used to sign.
+func (key RandomBeaconKey) Verifier(keypair *blsShare.BLSShare) (*BLSVerifier, error) {
+	masterPubKey, err := key.PubPoly()
+	if err != nil {
+		return nil, err
+	}
+	return &BLSVerifier{
+		BLSVerifier: blsShare.NewBLSVerifier(masterPubKey, keypair, key.Threshold, key.NumShares),
+		Key:         key,
+	}, nil
+}
+
+// BLSVerifier is a verifier of dkglib along with its key, which dkglib does not
+// export.
+type BLSVerifier struct {
+	*blsShare.BLSVerifier
+	Key RandomBeaconKey
+
+	// The addresses of the holders of the key shares, by share index, if the
+	// verifier comes from a DKG round of this node.
+	Participants []Address
+}
+
+// NewBLSVerifier returns a verifier of the given master public key and
+// threshold parameters, which signs with keypair.
+func NewBLSVerifier(masterPubKey *share.PubPoly, keypair *blsShare.BLSShare, t, n int) (*BLSVerifier, error) {
+	key, err := NewRandomBeaconKey(masterPubKey, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSVerifier{
+		BLSVerifier: blsShare.NewBLSVerifier(masterPubKey, keypair, t, n),
+		Key:         key,
+	}, nil
+}
+
+// IsNil returns true if v is a nil verifier.
+func (v *BLSVerifier) IsNil() bool {
+	return v == nil || v.BLSVerifier == nil
+}
+
+// KeyChange returns the change to the key of the verifier, or nil if the
+// holders of its key shares are not known.
+func (v *BLSVerifier) KeyChange() *RandomBeaconKeyChange {
+	if v.IsNil() || len(v.Participants) == 0 {
+		return nil
+	}
+	return &RandomBeaconKeyChange{
+		Key:          v.Key,
+		Participants: v.Participants,
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// RandomBeaconKeyChange changes the key of the random beacon to the key of a
+// DKG round of the validators. A key change committed in a block takes effect
+// at the next block or, if the chain has no key yet, at the block itself.
+type RandomBeaconKeyChange struct {
+	Key          RandomBeaconKey `json:"key"`
+	Participants []Address       `json:"participants"` // holders of the key shares, by share index
+}
+
+// Hash returns the hash of the key change, or nil if there is none. Like the
+// hash of the key, it does not depend on the encoding of the master public key.
+func (c *RandomBeaconKeyChange) Hash() []byte {
+	if c == nil {
+		return nil
+	}
+	return tmhash.Sum(cdcEncode(struct {
+		KeyHash      []byte
+		Participants []Address
+	}{c.Key.Hash(), c.Participants}))
+}
+
+// ValidateBasic performs basic validation.
+func (c *RandomBeaconKeyChange) ValidateBasic() error {
+	// checked first, as the number of participants bounds the number of shares
+	if len(c.Participants) != c.Key.NumShares {
+		return fmt.Errorf("expected %d participants, got %d", c.Key.NumShares, len(c.Participants))
+	}
+	for i, addr := range c.Participants {
+		if len(addr) != crypto.AddressSize {
+			return fmt.Errorf("expected len(Participants[%d]) to be %d, got %d", i, crypto.AddressSize, len(addr))
+		}
+		if i > 0 && bytes.Compare(c.Participants[i-1], addr) >= 0 {
+			return errors.New("participants are not sorted by address")
+		}
+	}
+	if err := c.Key.ValidateBasic(); err != nil {
+		return fmt.Errorf("wrong key: %v", err)
+	}
+	return nil
+}
+
+// String returns a string representation of the key change.
+func (c *RandomBeaconKeyChange) String() string {
+	if c == nil {
+		return "nil-RandomBeaconKeyChange"
+	}
+	return fmt.Sprintf("RandomBeaconKeyChange{%X %d/%d}", cmn.Fingerprint(c.Key.Hash()), c.Key.Threshold, c.Key.NumShares)
+}
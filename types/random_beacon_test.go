@@ -0,0 +1,213 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func TestBLSVerifierKey(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+
+	verifier, err := NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[0], keyring.T, keyring.N)
+	require.NoError(t, err)
+	assert.False(t, verifier.IsNil())
+	assert.Equal(t, keyring.T, verifier.Key.Threshold)
+	assert.Equal(t, keyring.N, verifier.Key.NumShares)
+
+	// a verifier of the key verifies the random data signed with the keyring
+	prevRandomData := cmn.RandBytes(RandomDataSize)
+	signers := make([]blsShare.BLSSigner, 0, keyring.T)
+	for i := 0; i < keyring.T; i++ {
+		share, err := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[i], keyring.T, keyring.N).
+			Sign(prevRandomData)
+		require.NoError(t, err)
+		signers = append(signers, &testBLSSigner{share})
+	}
+	randomData, err := verifier.Recover(prevRandomData, signers)
+	require.NoError(t, err)
+
+	keyVerifier, err := verifier.Key.Verifier(nil)
+	require.NoError(t, err)
+	assert.Nil(t, keyVerifier.Keypair)
+	assert.NoError(t, keyVerifier.VerifyRandomData(prevRandomData, randomData))
+
+	_, err = NewBLSVerifier(nil, keyring.Shares[0], keyring.T, keyring.N)
+	assert.Error(t, err)
+	assert.True(t, (*BLSVerifier)(nil).IsNil())
+	assert.True(t, (&BLSVerifier{}).IsNil())
+}
+
+// The random shares and random data signed with the 1-of-4 test key of
+// dkglib. They pin the pairing (kyber bn256, in pure Go) and the encoding of
+// the signatures, so a change of the BLS backend which breaks the random
+// beacon of existing chains fails here.
+var blsVectors = []struct {
+	seed       string
+	share      string
+	randomData string
+}{
+	{
+		"",
+		"00005D2720A94C14E74F577869D2949C89481A7C9ED8F30F60453F5B2D9E62419A6A8C42D08D58153C16C58E96F03EC322D2" +
+			"16F587D49F97E7C1A0AF43A41D10844C",
+		"5D2720A94C14E74F577869D2949C89481A7C9ED8F30F60453F5B2D9E62419A6A8C42D08D58153C16C58E96F03EC322D216F5" +
+			"87D49F97E7C1A0AF43A41D10844C",
+	},
+	{
+		"seed",
+		"0000102BB9DB1825AC92E643DE262950196B47D58E85B41AAD6FBE639504B2A820BF29932A4CBB3BD452B36B4AF6DDE610AD" +
+			"743830D05E26693140985E529AC61591",
+		"102BB9DB1825AC92E643DE262950196B47D58E85B41AAD6FBE639504B2A820BF29932A4CBB3BD452B36B4AF6DDE610AD7438" +
+			"30D05E26693140985E529AC61591",
+	},
+}
+
+func TestBLSVerifierVectors(t *testing.T) {
+	signer := blsShare.NewTestBLSVerifier("test")
+	key := RandomBeaconKey{
+		MasterPubKey: blsShare.DefaultBLSVerifierMasterPubKey,
+		Threshold:    1,
+		NumShares:    4,
+	}
+	verifier, err := key.Verifier(nil)
+	require.NoError(t, err)
+
+	for _, vector := range blsVectors {
+		var seed []byte
+		if vector.seed != "" {
+			seed = []byte(vector.seed)
+		}
+		msg := MakeRandomMessage([]byte(InitialRandomData), seed)
+		share, err := hex.DecodeString(vector.share)
+		require.NoError(t, err)
+		randomData, err := hex.DecodeString(vector.randomData)
+		require.NoError(t, err)
+
+		// BLS signatures are deterministic
+		signed, err := signer.Sign(msg)
+		require.NoError(t, err)
+		assert.Equal(t, share, signed, "seed %q", vector.seed)
+
+		assert.NoError(t, verifier.VerifyRandomShare("", msg, share))
+		recovered, err := verifier.Recover(msg, []blsShare.BLSSigner{&testBLSSigner{share}})
+		require.NoError(t, err)
+		assert.Equal(t, randomData, recovered, "seed %q", vector.seed)
+		assert.NoError(t, verifier.VerifyRandomData(msg, randomData))
+
+		corrupt := append([]byte{}, randomData...)
+		corrupt[len(corrupt)-1] ^= 0xff
+		assert.Error(t, verifier.VerifyRandomData(msg, corrupt))
+	}
+}
+
+func TestRandomBeaconKeyHash(t *testing.T) {
+	// dumped by another process, whose gob type ids differ from the ones of this
+	// process
+	key := RandomBeaconKey{
+		MasterPubKey: "Df+DAgEC/4QAAf+CAAAR/4EGAQEFUG9pbnQB/4IAAAD/hv+EAAH/gG9Pz5sOyRmxdttuuCOwK+efAvhrO9nTVk+" +
+			"JrBLW1EscSDz3QBnKSWTCHb26RDbQGJEfo2Utq29y/uzFHKqrHNAzlbSe9+0Nv8sCldtXiPz96STqRp1Nxtso7Cnk2Z+" +
+			"q1lu39AFVYFluEUbpKWcdXXAqupgfHuyEwiCLjNDHoc/Q",
+		Threshold: 1,
+		NumShares: 4,
+	}
+	require.NoError(t, key.ValidateBasic())
+	pubPoly, err := key.PubPoly()
+	require.NoError(t, err)
+	dumped, err := NewRandomBeaconKey(pubPoly, key.Threshold, key.NumShares)
+	require.NoError(t, err)
+
+	// the hash does not depend on the encoding of the key
+	assert.NotEqual(t, key.MasterPubKey, dumped.MasterPubKey)
+	assert.Equal(t, key.Hash(), dumped.Hash())
+	assert.Equal(t,
+		(&RandomBeaconKeyChange{Key: key}).Hash(),
+		(&RandomBeaconKeyChange{Key: dumped}).Hash())
+
+	other := dumped
+	other.Threshold = 2
+	assert.NotEqual(t, key.Hash(), other.Hash())
+	assert.Nil(t, RandomBeaconKey{}.Hash())
+
+	// an invalid key is only decoded into the points it holds
+	key.NumShares = 1 << 30
+	_, err = key.PubPoly()
+	assert.NoError(t, err)
+	key.MasterPubKey = "invalid"
+	assert.Error(t, key.ValidateBasic())
+	assert.NotNil(t, key.Hash())
+}
+
+type testBLSSigner struct {
+	sig []byte
+}
+
+func (s *testBLSSigner) GetBLSSignature() []byte { return s.sig }
+func (s *testBLSSigner) GetHash() []byte         { return []byte("hash") }
+
+func TestRandomBeaconKeyChangeValidateBasic(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	key, err := NewRandomBeaconKey(keyring.MasterPubKey, keyring.T, keyring.N)
+	require.NoError(t, err)
+
+	participants := func() []Address {
+		return []Address{
+			bytes.Repeat([]byte{1}, crypto.AddressSize),
+			bytes.Repeat([]byte{2}, crypto.AddressSize),
+			bytes.Repeat([]byte{3}, crypto.AddressSize),
+		}
+	}
+
+	testCases := []struct {
+		testName string
+		malleate func(*RandomBeaconKeyChange)
+		expErr   bool
+	}{
+		{"Valid", func(c *RandomBeaconKeyChange) {}, false},
+		{"Empty key", func(c *RandomBeaconKeyChange) { c.Key.MasterPubKey = "" }, true},
+		{"Zero threshold", func(c *RandomBeaconKeyChange) { c.Key.Threshold = 0 }, true},
+		{"Threshold above shares", func(c *RandomBeaconKeyChange) { c.Key.Threshold = 4 }, true},
+		{"Missing participant", func(c *RandomBeaconKeyChange) { c.Participants = c.Participants[1:] }, true},
+		{"Too many shares", func(c *RandomBeaconKeyChange) { c.Key.NumShares = 1 << 30 }, true},
+		{"Short address", func(c *RandomBeaconKeyChange) { c.Participants[0] = []byte{1} }, true},
+		{"Unsorted participants", func(c *RandomBeaconKeyChange) {
+			c.Participants[0], c.Participants[1] = c.Participants[1], c.Participants[0]
+		}, true},
+		{"Duplicate participant", func(c *RandomBeaconKeyChange) { c.Participants[1] = c.Participants[0] }, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			change := &RandomBeaconKeyChange{Key: key, Participants: participants()}
+			tc.malleate(change)
+			err := change.ValidateBasic()
+			assert.Equal(t, tc.expErr, err != nil, "%v", err)
+		})
+	}
+
+	// the key change is committed in the header of the block
+	change := &RandomBeaconKeyChange{Key: key, Participants: participants()}
+	assert.Nil(t, (*RandomBeaconKeyChange)(nil).Hash())
+	assert.NotEqual(t, change.Hash(), (&RandomBeaconKeyChange{Key: key}).Hash())
+
+	block := MakeBlock(1, nil, nil, nil)
+	block.ProposerAddress = participants()[0]
+	block.RandomBeaconKeyChange = change
+	block.fillHeader()
+	assert.EqualValues(t, change.Hash(), block.RandomBeaconKeyChangeHash)
+	assert.NoError(t, block.ValidateBasic())
+
+	block.RandomBeaconKeyChange = &RandomBeaconKeyChange{Key: key, Participants: participants()[:2]}
+	assert.Error(t, block.ValidateBasic())
+	block.RandomBeaconKeyChange = nil
+	assert.Error(t, block.ValidateBasic())
+}
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// RandomDataSize is the size of the random data of a block, a BLS signature
+// on the BN256 curve. Only the first block uses InitialRandomData instead.
+const RandomDataSize = 64
+
+// ConflictingRandomSharesEvidence contains evidence that a validator signed
+// two different random messages following the same random data, i.e. signed
+// the random data of a height twice with different seeds. A validator signs
+// exactly one random message per height, and the random data it follows is
+// unique to the height, so two signature shares of the same key share for
+// such messages are an equivocation.
+type ConflictingRandomSharesEvidence struct {
+	PubKey         crypto.PubKey
+	Height_        int64 // height the random shares were signed for
+	PrevRandomData []byte
+	SeedA          []byte
+	ShareA         []byte
+	SeedB          []byte
+	ShareB         []byte
+}
+
+var _ Evidence = &ConflictingRandomSharesEvidence{}
+
+// NewConflictingRandomSharesEvidence returns evidence that the validator with
+// the given public key signed shareA and shareB, the random shares of the
+// messages following prevRandomData with seedA and seedB.
+func NewConflictingRandomSharesEvidence(pubKey crypto.PubKey, height int64, prevRandomData,
+	seedA, shareA, seedB, shareB []byte) *ConflictingRandomSharesEvidence {
+	return &ConflictingRandomSharesEvidence{
+		PubKey:         pubKey,
+		Height_:        height,
+		PrevRandomData: prevRandomData,
+		SeedA:          seedA,
+		ShareA:         shareA,
+		SeedB:          seedB,
+		ShareB:         shareB,
+	}
+}
+
+// String returns a string representation of the evidence.
+func (m *ConflictingRandomSharesEvidence) String() string {
+	return fmt.Sprintf("ConflictingRandomSharesEvidence{Address: %v, Height: %d, ShareA: %X, ShareB: %X}",
+		m.PubKey.Address(), m.Height_, cmn.Fingerprint(m.ShareA), cmn.Fingerprint(m.ShareB))
+}
+
+// Height returns the height this evidence refers to.
+func (m *ConflictingRandomSharesEvidence) Height() int64 {
+	return m.Height_
+}
+
+// Address returns the address of the validator.
+func (m *ConflictingRandomSharesEvidence) Address() []byte {
+	return m.PubKey.Address()
+}
+
+// Bytes returns the bytes which compromise the evidence.
+func (m *ConflictingRandomSharesEvidence) Bytes() []byte {
+	return cdcEncode(m)
+}
+
+// Hash returns the hash of the evidence.
+func (m *ConflictingRandomSharesEvidence) Hash() []byte {
+	return tmhash.Sum(cdcEncode(m))
+}
+
+// MessageA returns the random message signed by ShareA.
+func (m *ConflictingRandomSharesEvidence) MessageA() []byte {
+	return MakeRandomMessage(m.PrevRandomData, m.SeedA)
+}
+
+// MessageB returns the random message signed by ShareB.
+func (m *ConflictingRandomSharesEvidence) MessageB() []byte {
+	return MakeRandomMessage(m.PrevRandomData, m.SeedB)
+}
+
+// ShareIndex returns the index of the key share the random shares were signed
+// with.
+func (m *ConflictingRandomSharesEvidence) ShareIndex() (int, error) {
+	return tbls.SigShare(m.ShareA).Index()
+}
+
+// Verify returns an error if the evidence does not refer to the validator
+// with the given public key or if the random shares do not conflict. The
+// shares themselves can only be checked against the key set of the height,
+// see VerifyShares.
+func (m *ConflictingRandomSharesEvidence) Verify(chainID string, pubKey crypto.PubKey) error {
+	if !pubKey.Equals(m.PubKey) {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error: pubkey (%v) doesn't match validator pubkey (%v)",
+			m.PubKey, pubKey)
+	}
+	if bytes.Equal(m.MessageA(), m.MessageB()) {
+		return errors.New("ConflictingRandomSharesEvidence Error: the random messages are the same")
+	}
+	indexA, err := tbls.SigShare(m.ShareA).Index()
+	if err != nil {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error: invalid ShareA: %v", err)
+	}
+	indexB, err := tbls.SigShare(m.ShareB).Index()
+	if err != nil {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error: invalid ShareB: %v", err)
+	}
+	if indexA != indexB {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error: share indices do not match. Got %d and %d",
+			indexA, indexB)
+	}
+	return nil
+}
+
+// RandomShareVerifier verifies the random shares signed with a key set.
+type RandomShareVerifier interface {
+	VerifyRandomShare(addr string, msg, sigShare []byte) error
+}
+
+// VerifyShares returns an error if the random shares are not valid signature
+// shares of their messages for the key set of verifier.
+func (m *ConflictingRandomSharesEvidence) VerifyShares(verifier RandomShareVerifier) error {
+	addr := m.PubKey.Address().String()
+	if err := verifier.VerifyRandomShare(addr, m.MessageA(), m.ShareA); err != nil {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error verifying ShareA: %v", err)
+	}
+	if err := verifier.VerifyRandomShare(addr, m.MessageB(), m.ShareB); err != nil {
+		return fmt.Errorf("ConflictingRandomSharesEvidence Error verifying ShareB: %v", err)
+	}
+	return nil
+}
+
+// Equal checks if two pieces of evidence are equal.
+func (m *ConflictingRandomSharesEvidence) Equal(ev Evidence) bool {
+	if _, ok := ev.(*ConflictingRandomSharesEvidence); !ok {
+		return false
+	}
+
+	// just check their hashes
+	mHash := tmhash.Sum(cdcEncode(m))
+	evHash := tmhash.Sum(cdcEncode(ev))
+	return bytes.Equal(mHash, evHash)
+}
+
+// ValidateBasic performs basic validation.
+func (m *ConflictingRandomSharesEvidence) ValidateBasic() error {
+	if m.PubKey == nil || len(m.PubKey.Bytes()) == 0 {
+		return errors.New("Empty PubKey")
+	}
+	if m.Height_ <= 0 {
+		return errors.New("Height must be greater than 0")
+	}
+	// The random data must be complete, or messages signed for other heights
+	// could be split into a common prefix and different seeds.
+	if len(m.PrevRandomData) != RandomDataSize && string(m.PrevRandomData) != InitialRandomData {
+		return fmt.Errorf("PrevRandomData must be %d bytes long", RandomDataSize)
+	}
+	if len(m.ShareA) == 0 || len(m.ShareB) == 0 {
+		return errors.New("Empty random share")
+	}
+	// Evidence must fit into the space reserved for it in a block.
+	if size := int64(len(cdc.MustMarshalBinaryLengthPrefixed(m))); size > MaxEvidenceBytes {
+		return fmt.Errorf("Evidence is too big: %d bytes (max: %d)", size, MaxEvidenceBytes)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/corestario/dkglib/lib/blsShare"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// randomShares returns the random shares of the messages following
+// prevRandomData with each seed, signed with key share id of the keyring.
+func randomShares(t *testing.T, keyring *blsShare.BLSKeyring, id int, prevRandomData []byte,
+	seeds ...[]byte) [][]byte {
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, keyring.Shares[id], keyring.T, keyring.N)
+	shares := make([][]byte, len(seeds))
+	for i, seed := range seeds {
+		share, err := verifier.Sign(MakeRandomMessage(prevRandomData, seed))
+		require.NoError(t, err)
+		shares[i] = share
+	}
+	return shares
+}
+
+func TestConflictingRandomSharesEvidence(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	verifier := blsShare.NewBLSVerifier(keyring.MasterPubKey, nil, keyring.T, keyring.N)
+
+	privKey := ed25519.GenPrivKey()
+	prevRandomData := cmn.RandBytes(RandomDataSize)
+	seedA, seedB := tmhash.Sum([]byte("seed a")), tmhash.Sum([]byte("seed b"))
+	shares := randomShares(t, keyring, 1, prevRandomData, seedA, seedB)
+	otherShares := randomShares(t, keyring, 2, prevRandomData, seedB)
+
+	ev := NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData, seedA, shares[0], seedB, shares[1])
+	assert.Nil(t, ev.ValidateBasic())
+	assert.EqualValues(t, 10, ev.Height())
+	assert.Equal(t, privKey.PubKey().Address().Bytes(), ev.Address())
+	assert.Nil(t, ev.Verify("mychain", privKey.PubKey()))
+	assert.NotNil(t, ev.Verify("mychain", ed25519.GenPrivKey().PubKey()))
+	assert.Nil(t, ev.VerifyShares(verifier))
+	index, err := ev.ShareIndex()
+	require.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	assert.True(t, ev.Equal(NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData,
+		seedA, shares[0], seedB, shares[1])))
+	assert.False(t, ev.Equal(NewMockGoodEvidence(10, 0, privKey.PubKey().Address())))
+
+	// The shares of the same message do not conflict.
+	sameMsg := NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData,
+		seedA, shares[0], seedA, shares[0])
+	assert.NotNil(t, sameMsg.Verify("mychain", privKey.PubKey()))
+
+	// Shares of different key shares do not conflict.
+	otherKey := NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData,
+		seedA, shares[0], seedB, otherShares[0])
+	assert.NotNil(t, otherKey.Verify("mychain", privKey.PubKey()))
+
+	// The shares must be signed for the messages.
+	swapped := NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData,
+		seedA, shares[1], seedB, shares[0])
+	assert.Nil(t, swapped.Verify("mychain", privKey.PubKey()))
+	assert.NotNil(t, swapped.VerifyShares(verifier))
+
+	testCases := []struct {
+		testName         string
+		malleateEvidence func(*ConflictingRandomSharesEvidence)
+	}{
+		{"Nil PubKey", func(ev *ConflictingRandomSharesEvidence) { ev.PubKey = nil }},
+		{"Zero height", func(ev *ConflictingRandomSharesEvidence) { ev.Height_ = 0 }},
+		{"Partial random data", func(ev *ConflictingRandomSharesEvidence) { ev.PrevRandomData = prevRandomData[:10] }},
+		{"Empty share", func(ev *ConflictingRandomSharesEvidence) { ev.ShareB = nil }},
+		{"Too big seed", func(ev *ConflictingRandomSharesEvidence) { ev.SeedA = make([]byte, MaxEvidenceBytes) }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			ev := NewConflictingRandomSharesEvidence(privKey.PubKey(), 10, prevRandomData,
+				seedA, shares[0], seedB, shares[1])
+			tc.malleateEvidence(ev)
+			assert.NotNil(t, ev.ValidateBasic())
+		})
+	}
+}
+
+func TestConflictingRandomSharesEvidenceAmino(t *testing.T) {
+	keyring, err := blsShare.NewBLSKeyring(2, 3)
+	require.NoError(t, err)
+	// The first random message follows the initial random data.
+	prevRandomData := []byte(InitialRandomData)
+	seedA, seedB := tmhash.Sum([]byte("seed a")), tmhash.Sum([]byte("seed b"))
+	shares := randomShares(t, keyring, 0, prevRandomData, seedA, seedB)
+
+	// use secp because it's pubkey is longer
+	ev := NewConflictingRandomSharesEvidence(secp256k1.GenPrivKey().PubKey(), 1, prevRandomData,
+		seedA, shares[0], seedB, shares[1])
+	require.NoError(t, ev.ValidateBasic())
+
+	bz, err := cdc.MarshalBinaryBare(ev)
+	require.NoError(t, err)
+	var ev2 Evidence
+	require.NoError(t, cdc.UnmarshalBinaryBare(bz, &ev2))
+	assert.True(t, ev.Equal(ev2))
+}